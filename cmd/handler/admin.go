@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// adminPath prefixes the paths of the maintainer endpoints.
+const adminPath = "/admin/"
+
+// RequireAdminToken provides an HTTP middleware that guards the admin endpoints
+// with a bearer token, and leaves all others as is.
+//
+// Requests without the token get a status 401 Unauthorized. Admin endpoints
+// are disabled if token is empty, so those get a status 404 Not Found instead.
+func RequireAdminToken(token string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, adminPath) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			span := telemetry.SpanFromContext(r.Context())
+
+			if token == "" {
+				span.RecordError(usecases.ErrStatusNotFound)
+				http.NotFound(w, r)
+
+				return
+			}
+
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				span.RecordError(usecases.ErrStatusUnauthenticated)
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, usecases.ErrStatusUnauthenticated.Error(), http.StatusUnauthorized)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	const (
+		conflicts = "/admin/conflicts"
+		public    = "/user/foo/media"
+	)
+
+	tests := map[string]struct {
+		token         string
+		path          string
+		authorization string
+		want          int
+	}{
+		"disabled":      {token: "", path: conflicts, authorization: "Bearer ", want: http.StatusNotFound},
+		"missing token": {token: "hunter2", path: conflicts, authorization: "", want: http.StatusUnauthorized},
+		"wrong token": {
+			token: "hunter2", path: conflicts, authorization: "Bearer hunter3",
+			want: http.StatusUnauthorized,
+		},
+		"wrong scheme": {
+			token: "hunter2", path: conflicts, authorization: "Basic hunter2",
+			want: http.StatusUnauthorized,
+		},
+		"valid token": {
+			token: "hunter2", path: conflicts, authorization: "Bearer hunter2",
+			want: http.StatusNoContent,
+		},
+		"public route":      {token: "hunter2", path: public, authorization: "", want: http.StatusNoContent},
+		"public route open": {token: "", path: public, authorization: "", want: http.StatusNoContent},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+
+			RequireAdminToken(test.token)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, test.want, rec.Code)
+
+			if test.want == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
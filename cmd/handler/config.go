@@ -0,0 +1,616 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/chaincache"
+	"github.com/wwmoraes/anilistarr/internal/adapters/sealedtokens"
+	"github.com/wwmoraes/anilistarr/internal/drivers/badger"
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/kitsu"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	anilistDefaultEndpoint = "https://graphql.anilist.co"
+	// anilistDefaultConcurrency is how many media list pages get requested at a
+	// time by default.
+	anilistDefaultConcurrency = 4
+	// anilistMaxPageSize is the largest page the Anilist API returns.
+	anilistMaxPageSize = 50
+	maxPort            = 1<<16 - 1
+	// redacted replaces secrets when printing settings, as URLs do.
+	redactedSecret = "xxxxx"
+
+	defaultBreakerOpenTimeout     = 30 * time.Second
+	defaultCacheWrite             = "through"
+	defaultBreakerThreshold       = 5
+	defaultCacheMediaListTTL      = time.Hour
+	defaultCacheMediaListStaleTTL = 24 * time.Hour
+	defaultCacheUserTTL           = 24 * time.Hour
+	defaultCacheUserNotFoundTTL   = 10 * time.Minute
+	defaultHost                   = "0.0.0.0"
+	defaultMappingsRefresh        = 7 * 24 * time.Hour
+	defaultPort                   = 8080
+	defaultRateLimitBurst         = 1000
+	defaultRateLimitInterval      = time.Minute
+)
+
+// environment maps the flag of each setting to the environment variable that
+// overrides it.
+var environment = map[string]string{
+	"admin-token":                "ADMIN_TOKEN",
+	"anilist-client-id":          "ANILIST_CLIENT_ID",
+	"anilist-client-secret":      "ANILIST_CLIENT_SECRET",
+	"anilist-concurrency":        "ANILIST_CONCURRENCY",
+	"anilist-endpoint":           "ANILIST_GRAPHQL_ENDPOINT",
+	"anilist-page-size":          "ANILIST_PAGE_SIZE",
+	"anilist-redirect-url":       "ANILIST_REDIRECT_URL",
+	"anilist-retry-budget":       "ANILIST_RETRY_BUDGET",
+	"anilist-token-key":          "ANILIST_TOKEN_KEY",
+	"breaker-open-timeout":       "BREAKER_OPEN_TIMEOUT",
+	"breaker-threshold":          "BREAKER_THRESHOLD",
+	"cache-media-list-stale-ttl": "CACHE_MEDIA_LIST_STALE_TTL",
+	"cache-media-list-ttl":       "CACHE_MEDIA_LIST_TTL",
+	"cache-backfill":             "CACHE_BACKFILL",
+	"cache-tiers":                "CACHE_TIERS",
+	"cache-url":                  "CACHE_URL",
+	"cache-user-not-found-ttl":   "CACHE_USER_NOT_FOUND_TTL",
+	"cache-user-ttl":             "CACHE_USER_TTL",
+	"cache-write":                "CACHE_WRITE",
+	"data-path":                  "DATA_PATH",
+	"host":                       "HOST",
+	"kitsu-endpoint":             "KITSU_API_ENDPOINT",
+	"mal-client-id":              "MAL_CLIENT_ID",
+	"mal-endpoint":               "MAL_API_ENDPOINT",
+	"mappings-refresh":           "MAPPINGS_REFRESH_INTERVAL",
+	"port":                       "PORT",
+	"rate-limit-burst":           "RATE_LIMIT_BURST",
+	"rate-limit-interval":        "RATE_LIMIT_INTERVAL",
+	"store-url":                  "STORE_URL",
+}
+
+// duration is a [time.Duration] written as text, e.g. 24h, in both the config
+// file and flags.
+type duration time.Duration
+
+// urlList is a list of URLs, written as a comma-separated list in flags.
+type urlList []string
+
+// config contains all handler settings. Those come from, in increasing order of
+// precedence: defaults, a YAML config file, environment variables and flags.
+type config struct {
+	Storage  storageConfig  `yaml:"storage"`
+	MAL      malConfig      `yaml:"mal"`
+	Kitsu    kitsuConfig    `yaml:"kitsu"`
+	Anilist  anilistConfig  `yaml:"anilist"`
+	Server   serverConfig   `yaml:"server"`
+	Cache    cacheConfig    `yaml:"cache"`
+	Breaker  breakerConfig  `yaml:"breaker"`
+	Mappings mappingsConfig `yaml:"mappings"`
+}
+
+// serverConfig contains the REST API settings. AdminToken is the bearer token
+// of the maintainer endpoints, which are disabled without one.
+type serverConfig struct {
+	Host       string          `yaml:"host"`
+	AdminToken string          `yaml:"adminToken"`
+	RateLimit  rateLimitConfig `yaml:"rateLimit"`
+	Port       int             `yaml:"port"`
+}
+
+// rateLimitConfig limits inbound requests to Burst per Interval.
+type rateLimitConfig struct {
+	Burst    int      `yaml:"burst"`
+	Interval duration `yaml:"interval"`
+}
+
+// storageConfig contains the store and cache settings. Both URLs default to
+// BadgerDB within DataPath. CacheTiers are the URLs of faster caches in front of
+// the CacheURL one, from the fastest to the slowest, e.g. memory:?capacity=1000.
+//
+// CacheWrite tells which caches get the values set: either the first, all of
+// them (through), or the first and then the others in the background (behind).
+// CacheBackfill sets the entries found on a cache to the faster ones.
+type storageConfig struct {
+	DataPath      string  `yaml:"dataPath"`
+	StoreURL      string  `yaml:"storeURL"`
+	CacheURL      string  `yaml:"cacheURL"`
+	CacheWrite    string  `yaml:"cacheWrite"`
+	CacheTiers    urlList `yaml:"cacheTiers"`
+	CacheBackfill bool    `yaml:"cacheBackfill"`
+}
+
+// cacheConfig contains how long tracker data remains cached. Media lists past
+// their TTL get served as stale for MediaListStaleTTL while they refresh.
+// Users the tracker lacks remain cached as such for UserNotFoundTTL.
+type cacheConfig struct {
+	UserTTL           duration `yaml:"userTTL"`
+	UserNotFoundTTL   duration `yaml:"userNotFoundTTL"`
+	MediaListTTL      duration `yaml:"mediaListTTL"`
+	MediaListStaleTTL duration `yaml:"mediaListStaleTTL"`
+}
+
+// breakerConfig contains the circuit breaker settings of the trackers, which
+// stop requesting those after Threshold consecutive failures for OpenTimeout. A
+// zero Threshold disables it.
+type breakerConfig struct {
+	Threshold   int      `yaml:"threshold"`
+	OpenTimeout duration `yaml:"openTimeout"`
+}
+
+// mappingsConfig contains how often the mapping sources refresh.
+type mappingsConfig struct {
+	RefreshInterval duration `yaml:"refreshInterval"`
+}
+
+// anilistConfig contains the Anilist API settings. A positive RetryBudget
+// makes requests wait for the rate limits and retry failures for up to it.
+// Concurrency is how many media list pages get requested at a time.
+//
+// ClientID enables users to authorize access to their private profiles and
+// lists, which needs the ClientSecret and RedirectURL of the same Anilist API
+// client. TokenKey is the base64 of the 32 bytes key that encrypts the stored
+// user tokens.
+type anilistConfig struct {
+	Endpoint     string   `yaml:"endpoint"`
+	ClientID     string   `yaml:"clientID"`
+	ClientSecret string   `yaml:"clientSecret"`
+	RedirectURL  string   `yaml:"redirectURL"`
+	TokenKey     string   `yaml:"tokenKey"`
+	PageSize     int      `yaml:"pageSize"`
+	Concurrency  int      `yaml:"concurrency"`
+	RetryBudget  duration `yaml:"retryBudget"`
+}
+
+// malConfig enables MyAnimeList if ClientID is set, as it requires one even for
+// public endpoints.
+type malConfig struct {
+	Endpoint string `yaml:"endpoint"`
+	ClientID string `yaml:"clientID"`
+}
+
+// kitsuConfig contains the Kitsu API settings. Kitsu is enabled unless Endpoint
+// is empty.
+type kitsuConfig struct {
+	Endpoint string `yaml:"endpoint"`
+}
+
+// String implements [flag.Value].
+func (value *duration) String() string {
+	return time.Duration(*value).String()
+}
+
+// Set implements [flag.Value].
+func (value *duration) Set(text string) error {
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	*value = duration(parsed)
+
+	return nil
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (value duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(value).String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (value *duration) UnmarshalText(text []byte) error {
+	return value.Set(string(text))
+}
+
+// String implements [flag.Value].
+func (value *urlList) String() string {
+	return strings.Join(*value, ",")
+}
+
+// Set implements [flag.Value]. It replaces the list, so that later sources
+// override earlier ones instead of adding up.
+func (value *urlList) Set(text string) error {
+	*value = nil
+
+	if text != "" {
+		*value = strings.Split(text, ",")
+	}
+
+	return nil
+}
+
+// newConfig returns the default settings.
+func newConfig() *config {
+	return &config{
+		Storage: storageConfig{
+			DataPath:      "",
+			StoreURL:      "",
+			CacheURL:      "",
+			CacheWrite:    defaultCacheWrite,
+			CacheTiers:    nil,
+			CacheBackfill: true,
+		},
+		Anilist: anilistConfig{
+			Endpoint:     anilistDefaultEndpoint,
+			ClientID:     "",
+			ClientSecret: "",
+			RedirectURL:  "",
+			TokenKey:     "",
+			PageSize:     anilistMaxPageSize,
+			Concurrency:  anilistDefaultConcurrency,
+			RetryBudget:  0,
+		},
+		MAL: malConfig{
+			Endpoint: "",
+			ClientID: "",
+		},
+		Server: serverConfig{
+			Host:       defaultHost,
+			AdminToken: "",
+			RateLimit: rateLimitConfig{
+				Burst:    defaultRateLimitBurst,
+				Interval: duration(defaultRateLimitInterval),
+			},
+			Port: defaultPort,
+		},
+		Kitsu: kitsuConfig{
+			Endpoint: kitsu.DefaultEndpoint,
+		},
+		Cache: cacheConfig{
+			UserTTL:           duration(defaultCacheUserTTL),
+			UserNotFoundTTL:   duration(defaultCacheUserNotFoundTTL),
+			MediaListTTL:      duration(defaultCacheMediaListTTL),
+			MediaListStaleTTL: duration(defaultCacheMediaListStaleTTL),
+		},
+		Breaker: breakerConfig{
+			Threshold:   defaultBreakerThreshold,
+			OpenTimeout: duration(defaultBreakerOpenTimeout),
+		},
+		Mappings: mappingsConfig{
+			RefreshInterval: duration(defaultMappingsRefresh),
+		},
+	}
+}
+
+// bind registers a flag per setting, which defaults to its current value.
+func (cfg *config) bind(flags *flag.FlagSet) {
+	flags.StringVar(&cfg.Storage.DataPath, "data-path", cfg.Storage.DataPath,
+		"directory of the data files, such as overrides and the default stores")
+	flags.StringVar(&cfg.Storage.StoreURL, "store-url", cfg.Storage.StoreURL,
+		"store driver base URL, e.g. sqlite:///data/sqlite (defaults to badger within the data path)")
+	flags.StringVar(&cfg.Storage.CacheURL, "cache-url", cfg.Storage.CacheURL,
+		"cache driver URL, e.g. redis://localhost:6379/0 (defaults to badger within the data path)")
+	flags.Var(&cfg.Storage.CacheTiers, "cache-tiers",
+		"comma-separated cache driver URLs in front of the cache URL, from the fastest, e.g. memory:?capacity=1000")
+	flags.StringVar(&cfg.Storage.CacheWrite, "cache-write", cfg.Storage.CacheWrite,
+		"which cache tiers get values set: first, through (all) or behind (first, then the others)")
+	flags.BoolVar(&cfg.Storage.CacheBackfill, "cache-backfill", cfg.Storage.CacheBackfill,
+		"set the entries found on a cache tier to the faster ones")
+	flags.StringVar(&cfg.Server.Host, "host", cfg.Server.Host, "address to listen on")
+	flags.IntVar(&cfg.Server.Port, "port", cfg.Server.Port, "port to listen on")
+	flags.StringVar(&cfg.Server.AdminToken, "admin-token", cfg.Server.AdminToken,
+		"bearer token of the /admin endpoints, which are disabled without one")
+	flags.IntVar(&cfg.Server.RateLimit.Burst, "rate-limit-burst", cfg.Server.RateLimit.Burst,
+		"inbound requests allowed per rate limit interval")
+	flags.Var(&cfg.Server.RateLimit.Interval, "rate-limit-interval", "inbound rate limit interval")
+	flags.Var(&cfg.Cache.UserTTL, "cache-user-ttl", "how long user IDs remain cached")
+	flags.Var(&cfg.Cache.UserNotFoundTTL, "cache-user-not-found-ttl",
+		"how long users the tracker lacks remain cached as such (0 disables it)")
+	flags.Var(&cfg.Cache.MediaListTTL, "cache-media-list-ttl", "how long media lists remain cached")
+	flags.Var(&cfg.Cache.MediaListStaleTTL, "cache-media-list-stale-ttl",
+		"how long media lists past their TTL get served as stale while refreshing (0 disables it)")
+	flags.IntVar(&cfg.Breaker.Threshold, "breaker-threshold", cfg.Breaker.Threshold,
+		"consecutive tracker failures that open its circuit breaker (0 disables it)")
+	flags.Var(&cfg.Breaker.OpenTimeout, "breaker-open-timeout",
+		"how long an open circuit breaker fails fast before probing the tracker")
+	flags.Var(&cfg.Mappings.RefreshInterval, "mappings-refresh", "how often the mapping sources refresh")
+	flags.StringVar(&cfg.Anilist.Endpoint, "anilist-endpoint", cfg.Anilist.Endpoint, "Anilist GraphQL API URL")
+	flags.IntVar(&cfg.Anilist.PageSize, "anilist-page-size", cfg.Anilist.PageSize,
+		"media list entries per Anilist request")
+	flags.IntVar(&cfg.Anilist.Concurrency, "anilist-concurrency", cfg.Anilist.Concurrency,
+		"media list pages requested at a time from Anilist")
+	flags.Var(&cfg.Anilist.RetryBudget, "anilist-retry-budget",
+		"how long Anilist requests may wait for rate limits and retries (0 disables it)")
+	flags.StringVar(&cfg.Anilist.ClientID, "anilist-client-id", cfg.Anilist.ClientID,
+		"Anilist API client ID, which enables users to authorize access to their private data")
+	flags.StringVar(&cfg.Anilist.ClientSecret, "anilist-client-secret", cfg.Anilist.ClientSecret,
+		"Anilist API client secret")
+	flags.StringVar(&cfg.Anilist.RedirectURL, "anilist-redirect-url", cfg.Anilist.RedirectURL,
+		"Anilist API client redirect URL, which is the /auth/anilist/callback of this service")
+	flags.StringVar(&cfg.Anilist.TokenKey, "anilist-token-key", cfg.Anilist.TokenKey,
+		"base64 of the 32 bytes key that encrypts the stored Anilist user tokens")
+	flags.StringVar(&cfg.MAL.Endpoint, "mal-endpoint", cfg.MAL.Endpoint, "MyAnimeList API URL")
+	flags.StringVar(&cfg.MAL.ClientID, "mal-client-id", cfg.MAL.ClientID,
+		"MyAnimeList API client ID, which enables it")
+	flags.StringVar(&cfg.Kitsu.Endpoint, "kitsu-endpoint", cfg.Kitsu.Endpoint, "Kitsu API URL (empty disables it)")
+}
+
+// load merges the config file at filePath, if any, the environment variables
+// and the flags set on the command line into the bound settings, and then
+// validates the result.
+func (cfg *config) load(flags *flag.FlagSet, filePath string) error {
+	// the file and environment must not override the command line, so it is
+	// reapplied after them
+	args := map[string]string{}
+	flags.Visit(func(f *flag.Flag) {
+		args[f.Name] = f.Value.String()
+	})
+
+	if filePath != "" {
+		err := cfg.read(filePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := setEnvironment(flags)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range args {
+		err = flags.Set(name, value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid -%s: %w", usecases.ErrStatusInvalidArgument, name, err)
+		}
+	}
+
+	if cfg.Storage.StoreURL == "" {
+		cfg.Storage.StoreURL = badger.Scheme + "://" + path.Join(cfg.Storage.DataPath, "badger")
+	}
+
+	if cfg.Storage.CacheURL == "" {
+		cfg.Storage.CacheURL = badger.Scheme + "://" + path.Join(cfg.Storage.DataPath, "badger", "cache")
+	}
+
+	return cfg.validate()
+}
+
+// read merges the YAML file at filePath into the settings. Unknown keys are
+// errors, as those are most likely typos.
+func (cfg *config) read(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open config file: %w", usecases.ErrStatusFailedPrecondition, err)
+	}
+
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(cfg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: failed to parse config file: %w", usecases.ErrStatusInvalidArgument, err)
+	}
+
+	return nil
+}
+
+// validate reports all invalid settings at once.
+func (cfg *config) validate() error {
+	errs := []error{
+		validateURL("storage.storeURL", cfg.Storage.StoreURL),
+		cfg.Storage.validateCache(),
+		validateURL("anilist.endpoint", cfg.Anilist.Endpoint),
+		validatePositive("server.rateLimit.interval", cfg.Server.RateLimit.Interval),
+		validatePositive("cache.userTTL", cfg.Cache.UserTTL),
+		validatePositive("cache.mediaListTTL", cfg.Cache.MediaListTTL),
+		validatePositive("mappings.refreshInterval", cfg.Mappings.RefreshInterval),
+		validatePositive("breaker.openTimeout", cfg.Breaker.OpenTimeout),
+	}
+
+	if cfg.Anilist.ClientID != "" {
+		errs = append(errs, cfg.Anilist.validateAuthorization())
+	}
+
+	if cfg.MAL.Endpoint != "" {
+		errs = append(errs, validateURL("mal.endpoint", cfg.MAL.Endpoint))
+	}
+
+	if cfg.Kitsu.Endpoint != "" {
+		errs = append(errs, validateURL("kitsu.endpoint", cfg.Kitsu.Endpoint))
+	}
+
+	if cfg.Server.Port < 1 || cfg.Server.Port > maxPort {
+		errs = append(errs, fmt.Errorf(
+			"%w: server.port must be within 1 and %d",
+			usecases.ErrStatusInvalidArgument,
+			maxPort,
+		))
+	}
+
+	errs = append(errs,
+		validateNonNegative("cache.userNotFoundTTL", cfg.Cache.UserNotFoundTTL),
+		validateNonNegative("cache.mediaListStaleTTL", cfg.Cache.MediaListStaleTTL),
+		validateNonNegative("anilist.retryBudget", cfg.Anilist.RetryBudget),
+	)
+
+	errs = append(errs,
+		validateMinimum("server.rateLimit.burst", cfg.Server.RateLimit.Burst, 1),
+		validateMinimum("anilist.concurrency", cfg.Anilist.Concurrency, 1),
+		validateMinimum("breaker.threshold", cfg.Breaker.Threshold, 0),
+	)
+
+	if cfg.Anilist.PageSize < 1 || cfg.Anilist.PageSize > anilistMaxPageSize {
+		errs = append(errs, fmt.Errorf(
+			"%w: anilist.pageSize must be within 1 and %d",
+			usecases.ErrStatusInvalidArgument,
+			anilistMaxPageSize,
+		))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
+
+// print writes the settings as YAML, with the URL passwords and other secrets
+// redacted.
+func (cfg *config) print(writer io.Writer) error {
+	redacted := *cfg
+	redacted.Storage.StoreURL = redactURL(cfg.Storage.StoreURL)
+	redacted.Storage.CacheURL = redactURL(cfg.Storage.CacheURL)
+	redacted.Storage.CacheTiers = make(urlList, 0, len(cfg.Storage.CacheTiers))
+
+	for _, tier := range cfg.Storage.CacheTiers {
+		redacted.Storage.CacheTiers = append(redacted.Storage.CacheTiers, redactURL(tier))
+	}
+	redacted.Server.AdminToken = redactSecret(cfg.Server.AdminToken)
+	redacted.Anilist.ClientSecret = redactSecret(cfg.Anilist.ClientSecret)
+	redacted.Anilist.TokenKey = redactSecret(cfg.Anilist.TokenKey)
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(&redacted)
+	if err != nil {
+		return fmt.Errorf("failed to print config: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+// tokenKey decodes the key that encrypts the user tokens.
+func (cfg anilistConfig) tokenKey() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.TokenKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: anilist.tokenKey is not valid base64: %w", usecases.ErrStatusInvalidArgument, err)
+	}
+
+	if len(key) != sealedtokens.KeySize {
+		return nil, fmt.Errorf(
+			"%w: anilist.tokenKey must be %d bytes long",
+			usecases.ErrStatusInvalidArgument,
+			sealedtokens.KeySize,
+		)
+	}
+
+	return key, nil
+}
+
+// validateAuthorization checks the settings user authorization needs.
+func (cfg anilistConfig) validateAuthorization() error {
+	errs := []error{
+		validateURL("anilist.redirectURL", cfg.RedirectURL),
+	}
+
+	if cfg.ClientSecret == "" {
+		errs = append(errs, fmt.Errorf("%w: anilist.clientSecret must be set", usecases.ErrStatusInvalidArgument))
+	}
+
+	_, err := cfg.tokenKey()
+
+	return errors.Join(append(errs, err)...)
+}
+
+// writePolicy decodes the policy of the cache tiers.
+func (cfg storageConfig) writePolicy() (chaincache.WritePolicy, error) {
+	switch cfg.CacheWrite {
+	case "first":
+		return chaincache.WriteFirst, nil
+	case "through":
+		return chaincache.WriteThrough, nil
+	case "behind":
+		return chaincache.WriteBehind, nil
+	default:
+		return 0, fmt.Errorf(
+			"%w: storage.cacheWrite must be one of first, through or behind",
+			usecases.ErrStatusInvalidArgument,
+		)
+	}
+}
+
+// validateCache checks the cache URL, those of its tiers and their policy.
+func (cfg storageConfig) validateCache() error {
+	_, err := cfg.writePolicy()
+
+	errs := []error{
+		validateURL("storage.cacheURL", cfg.CacheURL),
+		err,
+	}
+
+	for index, tier := range cfg.CacheTiers {
+		errs = append(errs, validateURL(fmt.Sprintf("storage.cacheTiers[%d]", index), tier))
+	}
+
+	return errors.Join(errs...)
+}
+
+// setEnvironment sets the flags of all non-empty environment variables.
+func setEnvironment(flags *flag.FlagSet) error {
+	for name, key := range environment {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+
+		err := flags.Set(name, value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid %s: %w", usecases.ErrStatusInvalidArgument, key, err)
+		}
+	}
+
+	return nil
+}
+
+// redactSecret hides a secret, if any, which makes it safe to print.
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return redactedSecret
+}
+
+func validateURL(key, rawURL string) error {
+	uri, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s is not a valid URL: %w", usecases.ErrStatusInvalidArgument, key, err)
+	}
+
+	if uri.Scheme == "" {
+		return fmt.Errorf("%w: %s must be an absolute URL", usecases.ErrStatusInvalidArgument, key)
+	}
+
+	return nil
+}
+
+func validatePositive(key string, value duration) error {
+	if value <= 0 {
+		return fmt.Errorf("%w: %s must be positive", usecases.ErrStatusInvalidArgument, key)
+	}
+
+	return nil
+}
+
+func validateNonNegative(key string, value duration) error {
+	if value < 0 {
+		return fmt.Errorf("%w: %s must not be negative", usecases.ErrStatusInvalidArgument, key)
+	}
+
+	return nil
+}
+
+// validateMinimum checks that the setting at key is at least minimum.
+func validateMinimum(key string, value, minimum int) error {
+	if value < minimum {
+		return fmt.Errorf("%w: %s must be at least %d", usecases.ErrStatusInvalidArgument, key, minimum)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@
 Handler processes requests to map media list IDs to specific services and formats.
 
 The sample implementation maps Anilist IDs to TVDB ones and formats the result as a Sonarr Custom List.
+It also maps Anilist movies to TMDB ones and formats those as a Radarr Custom List.
 */
 package main
 
@@ -9,10 +10,13 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"net/textproto"
 	"os"
 	"os/signal"
+	"path"
+	"strconv"
 	"time"
 
 	"github.com/denisbrodbeck/machineid"
@@ -30,7 +34,10 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/adapters/sources"
 	"github.com/wwmoraes/anilistarr/internal/api"
 	"github.com/wwmoraes/anilistarr/internal/drivers/animelists"
+	"github.com/wwmoraes/anilistarr/internal/drivers/overrides"
 	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/anilist"
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/kitsu"
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/mal"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 	"github.com/wwmoraes/anilistarr/pkg/process"
 )
@@ -39,8 +46,11 @@ const (
 	serviceNamespace = "github.com/wwmoraes/anilistarr"
 	serviceName      = "anilistarr"
 
-	apiInboundRateBurst                = 1000
-	apiInboundRateInterval             = time.Minute
+	animeListsURL    = "https://github.com/Fribb/anime-lists/raw/master/anime-list-full.json"
+	animeListsXMLURL = "https://github.com/Anime-Lists/anime-lists/raw/master/anime-list-master.xml"
+	manamiURL        = "https://github.com/manami-project/anime-offline-database/releases/" +
+		"download/latest/anime-offline-database-minified.json"
+
 	gracefulShutdownTimeout            = 5 * time.Second
 	httpClientTimeout                  = 30 * time.Second
 	httpServerReadHeaderTimeout        = 5 * time.Second
@@ -48,7 +58,6 @@ const (
 	httpTransportMaxIdleConns          = 1000
 	httpTransportResponseHeaderTimeout = 5 * time.Second
 	httpTransportTLSHandshakeTimeout   = 5 * time.Second
-	mappingRefreshInterval             = time.Hour * 24 * 7
 )
 
 var version = "0.0.0-unknown"
@@ -58,12 +67,21 @@ func main() {
 	defer process.HandleExit()
 
 	flags := struct {
-		version bool
+		configFile  string
+		printConfig bool
+		version     bool
 	}{
-		version: false,
+		configFile:  "",
+		printConfig: false,
+		version:     false,
 	}
 
+	cfg := newConfig()
+	cfg.bind(flag.CommandLine)
+
 	flag.BoolVar(&flags.version, "version", false, "shows version and exits")
+	flag.BoolVar(&flags.printConfig, "print-config", false, "shows the effective configuration and exits")
+	flag.StringVar(&flags.configFile, "config", os.Getenv("CONFIG_FILE"), "YAML configuration file")
 	flag.Parse()
 
 	if flags.version {
@@ -72,6 +90,14 @@ func main() {
 		return
 	}
 
+	process.AssertWith(cfg.load(flag.CommandLine, flags.configFile), "configuration failure")
+
+	if flags.printConfig {
+		process.Assert(cfg.print(os.Stdout))
+
+		return
+	}
+
 	ctx := context.Background()
 
 	log := logr.New(logging.NewStandardLogSink())
@@ -93,31 +119,29 @@ func main() {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	defer cancel()
 
-	dataPath := os.Getenv("DATA_PATH")
-
-	host := os.Getenv("HOST")
-	if host == "" {
-		host = "0.0.0.0"
-	}
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	wiring := newWiring(cfg)
 
 	log.Info(
 		"staring up",
 		"name", serviceName,
 		"version", version,
-		"data path", dataPath,
-		"host", host,
-		"port", port,
+		"config file", flags.configFile,
+		"data path", cfg.Storage.DataPath,
+		"store URL", redactURL(cfg.Storage.StoreURL),
+		"cache URL", redactURL(cfg.Storage.CacheURL),
+		"host", cfg.Server.Host,
+		"port", cfg.Server.Port,
 	)
 
-	store, err := newStore(ctx, dataPath)
+	store, err := wiring.newStore(ctx, "store")
 	process.Assert(err)
 
-	fileCache, err := newCache(ctx, dataPath)
+	movieStore, err := wiring.newStore(ctx, "movie-store")
+	process.Assert(err)
+
+	defer process.AssertClose(movieStore, "failed to close movie store")
+
+	fileCache, err := wiring.newCache(ctx)
 	process.Assert(err)
 
 	defer process.AssertClose(fileCache, "failed to close cache")
@@ -136,31 +160,91 @@ func main() {
 		process.Exit(1)
 	}
 
+	httpClient := &http.Client{
+		Transport: transport,
+		Timeout:   httpClientTimeout,
+	}
+
+	anilistOptions := cfg.Anilist.options(httpClient)
+
+	// users authorize access to their private data with an Anilist API client
+	var tokens usecases.TokenStore
+
+	if cfg.Anilist.ClientID != "" {
+		tokens, err = wiring.newTokens(store)
+		process.Assert(err)
+
+		anilistOptions = append(anilistOptions, anilist.WithTokens(tokens))
+	}
+
+	anilistTracker := anilist.New(cfg.Anilist.Endpoint, anilistOptions...)
+
 	tracker := cachedtracker.CachedTracker{
-		Cache: fileCache,
-		Tracker: anilist.New(
-			os.Getenv("ANILIST_GRAPHQL_ENDPOINT"),
-			anilist.WithPageSize(anilistPageSize),
-			anilist.WithClient(&http.Client{
-				Transport: transport,
-				Timeout:   httpClientTimeout,
-			}),
-		),
-		TTL: cachedtracker.TTLs{
-			UserID:       cacheUserTTL,
-			MediaListIDs: cacheMediaListTTL,
-		},
+		Cache:     fileCache,
+		Namespace: cachedtracker.DefaultNamespace,
+		Tracker:   cfg.Breaker.wrap(anilistTracker),
+		TTL:       cfg.Cache.ttls(),
 	}
 	defer process.AssertClose(&tracker, "failed to close tracker")
 
-	source := sources.JSON[animelists.Anilist2TVDBMetadata](
-		"https://github.com/Fribb/anime-lists/raw/master/anime-list-full.json",
-	)
-
+	// season-aware mappings take precedence over the flat ones, and the offline
+	// database fills the AniDB IDs the Fribb list lacks for recent releases
 	mediaLister := usecases.MediaList{
 		Tracker: &tracker,
-		Source:  source,
-		Store:   store,
+		Source: &sources.Priority{
+			Sources: []usecases.Source{
+				&animelists.XML{
+					IDs: &sources.Priority{
+						Sources: []usecases.Source{
+							sources.JSON[animelists.Anilist2AniDBMetadata](animeListsURL),
+							animelists.Manami[animelists.Anilist2AniDBMetadata](manamiURL),
+						},
+					},
+					URI: animeListsXMLURL,
+				},
+				sources.JSON[animelists.Anilist2TVDBMetadata](animeListsURL),
+			},
+		},
+		Store:       store,
+		MovieSource: sources.JSON[animelists.Anilist2TMDBMetadata](animeListsURL),
+		MovieStore:  movieStore,
+		Overrides:   overrides.New(path.Join(cfg.Storage.DataPath, "anilist-overrides.yaml")),
+		Prune:       true,
+	}
+
+	mediaListers := map[api.Tracker]usecases.MediaLister{
+		api.Anilist: &mediaLister,
+	}
+
+	// MyAnimeList requires a client ID even for public endpoints
+	if cfg.MAL.ClientID != "" {
+		malMediaLister, err := wiring.newMediaList(
+			ctx,
+			"mal",
+			fileCache,
+			mal.New(cfg.MAL.Endpoint, cfg.MAL.ClientID, mal.WithClient(httpClient)),
+			sources.JSON[animelists.MAL2TVDBMetadata](animeListsURL),
+		)
+		process.Assert(err)
+
+		defer process.AssertClose(malMediaLister.Store, "failed to close MyAnimeList store")
+
+		mediaListers[api.Mal] = malMediaLister
+	}
+
+	if cfg.Kitsu.Endpoint != "" {
+		kitsuMediaLister, err := wiring.newMediaList(
+			ctx,
+			"kitsu",
+			fileCache,
+			kitsu.New(cfg.Kitsu.Endpoint, kitsu.WithClient(httpClient)),
+			sources.JSON[animelists.Kitsu2TVDBMetadata](animeListsURL),
+		)
+		process.Assert(err)
+
+		defer process.AssertClose(kitsuMediaLister.Store, "failed to close Kitsu store")
+
+		mediaListers[api.Kitsu] = kitsuMediaLister
 	}
 
 	router := chi.NewRouter()
@@ -171,24 +255,47 @@ func main() {
 		"X-Frame-Options":              []string{"DENY"},
 	}))
 	router.Use(Limiter(rate.NewLimiter(
-		rate.Limit(apiInboundRateBurst)*rate.Every(apiInboundRateInterval),
-		apiInboundRateBurst,
+		rate.Limit(cfg.Server.RateLimit.Burst)*rate.Every(time.Duration(cfg.Server.RateLimit.Interval)),
+		cfg.Server.RateLimit.Burst,
 	)))
+	router.Use(RequireAdminToken(cfg.Server.AdminToken))
 
 	service := api.Service{
-		MediaLister: &mediaLister,
+		MediaLister:  &mediaLister,
+		MediaListers: mediaListers,
+		Tokens:       tokens,
+	}
+
+	if tokens != nil {
+		service.UserIDs = &tracker
+		service.InsecureCookies = cfg.Anilist.insecureRedirect()
+		service.Authorizer = &anilist.Authorizer{
+			Client:       httpClient,
+			Tracker:      anilistTracker,
+			AuthorizeURL: anilist.DefaultAuthorizeURL,
+			TokenURL:     anilist.DefaultTokenURL,
+			ClientID:     cfg.Anilist.ClientID,
+			ClientSecret: cfg.Anilist.ClientSecret,
+			RedirectURL:  cfg.Anilist.RedirectURL,
+		}
 	}
 
 	api.HandlerFromMux(&service, router)
 
 	server := http.Server{
-		Addr:              fmt.Sprintf("%s:%s", host, port),
+		Addr:              net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port)),
 		Handler:           router,
 		ReadHeaderTimeout: httpServerReadHeaderTimeout,
 	}
 
-	// update mapping every week
-	go scheduledRefresh(ctx, &mediaLister, mappingRefreshInterval)
+	// update mappings periodically
+	for name, lister := range mediaListers {
+		// validators are per tracker as their sources share URIs
+		getter := usecases.ConditionalHTTPGetter(http.DefaultClient, fileCache, string(name))
+
+		go scheduledRefresh(ctx, lister, getter, time.Duration(cfg.Mappings.RefreshInterval))
+	}
+
 	//nolint:errcheck // ignore listen errors
 	go server.ListenAndServe()
 
@@ -200,13 +307,18 @@ func main() {
 	gracefulShutdown(&server)
 }
 
-func scheduledRefresh(ctx context.Context, linker usecases.MediaLister, interval time.Duration) {
+func scheduledRefresh(
+	ctx context.Context,
+	linker usecases.MediaLister,
+	getter usecases.Getter,
+	interval time.Duration,
+) {
 	log := telemetry.Logr(ctx)
 
 	for {
 		log.Info("refreshing linker metadata")
 
-		err := linker.Refresh(ctx, usecases.HTTPGetter(http.DefaultClient))
+		err := linker.Refresh(ctx, getter)
 		process.Assert(err)
 
 		log.Info("linker metadata refreshed")
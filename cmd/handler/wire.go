@@ -2,47 +2,61 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"path"
+	"slices"
 	"time"
 
-	telemetry "github.com/wwmoraes/gotell"
-
+	"github.com/wwmoraes/anilistarr/internal/adapters/breakertracker"
+	"github.com/wwmoraes/anilistarr/internal/adapters/cachedtracker"
+	"github.com/wwmoraes/anilistarr/internal/adapters/chaincache"
+	"github.com/wwmoraes/anilistarr/internal/adapters/sealedtokens"
 	"github.com/wwmoraes/anilistarr/internal/drivers/badger"
 	"github.com/wwmoraes/anilistarr/internal/drivers/bolt"
+	"github.com/wwmoraes/anilistarr/internal/drivers/memory"
+	"github.com/wwmoraes/anilistarr/internal/drivers/overrides"
+	"github.com/wwmoraes/anilistarr/internal/drivers/postgres"
 	"github.com/wwmoraes/anilistarr/internal/drivers/redis"
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
 	"github.com/wwmoraes/anilistarr/internal/drivers/sqlite"
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/anilist"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
+	"github.com/wwmoraes/anilistarr/pkg/with"
 )
 
-const (
-	cacheUserTTL      = 24 * time.Hour
-	cacheMediaListTTL = time.Hour
-	anilistPageSize   = 50
-	storeType         = StoreBadger
-	cacheType         = CacheBadger
-)
+// wiring contains the settings needed to build the handler components. Stores
+// are named entries within the store URL, as each media list needs its own.
+type wiring struct {
+	drivers *registry.Drivers
+	config  *config
+}
 
-func newStore(ctx context.Context, dataPath string) (usecases.Store, error) {
-	var store usecases.Store
+// newWiring registers all supported drivers.
+func newWiring(cfg *config) *wiring {
+	var drivers registry.Drivers
 
-	var err error
+	badger.Register(&drivers)
+	bolt.Register(&drivers)
+	memory.Register(&drivers)
+	postgres.Register(&drivers)
+	redis.Register(&drivers)
+	sqlite.Register(&drivers)
 
-	// TODO kill this switch...
-	switch storeType {
-	case StoreBadger:
-		store, err = badger.New(
-			path.Join(dataPath, "badger", "store"),
-			badger.WithLogger(&badger.Logr{
-				Logger: telemetry.Logr(ctx),
-			}),
-		)
-	case StoreSQL:
-		store, err = sqlite.New(ctx, path.Join(dataPath, "sqlite-store.db"))
-	default:
-		return nil, usecases.ErrStatusUnimplemented
+	return &wiring{
+		drivers: &drivers,
+		config:  cfg,
 	}
+}
 
+func (wiring *wiring) newStore(ctx context.Context, name string) (usecases.Store, error) {
+	uri, err := url.JoinPath(wiring.config.Storage.StoreURL, name)
+	if err != nil {
+		return nil, fmt.Errorf("store initialization failed: %w", err)
+	}
+
+	store, err := wiring.drivers.Stores.New(ctx, uri)
 	if err != nil {
 		return nil, fmt.Errorf("store initialization failed: %w", err)
 	}
@@ -50,33 +64,153 @@ func newStore(ctx context.Context, dataPath string) (usecases.Store, error) {
 	return store, nil
 }
 
-func newCache(ctx context.Context, dataPath string) (usecases.Cache, error) {
-	var cache usecases.Cache
-
-	var err error
-
-	// TODO kill this switch...
-	switch cacheType {
-	case CacheBadger:
-		cache, err = badger.New(
-			path.Join(dataPath, "badger", "cache"),
-			badger.WithLogger(&badger.Logr{
-				Logger: telemetry.Logr(ctx),
-			}),
-		)
-	case CacheBolt:
-		cache, err = bolt.New(path.Join(dataPath, "bolt-cache.db"), nil)
-	case CacheRedis:
-		cache, err = redis.New(ctx, nil)
-	case CacheSQL:
-		cache, err = sqlite.New(ctx, path.Join(dataPath, "sqlite-cache.db"))
-	default:
-		return nil, usecases.ErrStatusUnimplemented
+// newTokens returns the store of the Anilist user tokens, which encrypts those
+// within store.
+func (wiring *wiring) newTokens(store usecases.Store) (usecases.TokenStore, error) {
+	tokens, ok := store.(usecases.TokenStore)
+	if !ok {
+		return nil, fmt.Errorf("%w: the store driver does not support tokens", usecases.ErrStatusUnimplemented)
+	}
+
+	key, err := wiring.config.Anilist.tokenKey()
+	if err != nil {
+		return nil, fmt.Errorf("token store initialization failed: %w", err)
 	}
 
+	aead, err := sealedtokens.NewAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("token store initialization failed: %w", err)
+	}
+
+	return &sealedtokens.SealedTokens{
+		Store: tokens,
+		AEAD:  aead,
+	}, nil
+}
+
+// newCache returns the cache at the cache URL, behind a chain of the cache
+// tiers if any. Tiers get backfilled only with the entries found on slower
+// ones that report their expiration.
+func (wiring *wiring) newCache(ctx context.Context) (usecases.Cache, error) {
+	policy, err := wiring.config.Storage.writePolicy()
 	if err != nil {
 		return nil, fmt.Errorf("cache initialization failed: %w", err)
 	}
 
-	return cache, nil
+	uris := append(slices.Clone(wiring.config.Storage.CacheTiers), wiring.config.Storage.CacheURL)
+	chain := &chaincache.ChainCache{
+		Caches:      make([]usecases.Cache, 0, len(uris)),
+		BackfillTTL: 0,
+		Write:       policy,
+		Backfill:    wiring.config.Storage.CacheBackfill,
+	}
+
+	for _, uri := range uris {
+		cache, err := wiring.drivers.Caches.New(ctx, uri)
+		if err != nil {
+			// closes the caches created so far
+			return nil, errors.Join(fmt.Errorf("cache initialization failed: %w", err), chain.Close())
+		}
+
+		chain.Caches = append(chain.Caches, cache)
+	}
+
+	if len(chain.Caches) == 1 {
+		return chain.Caches[0], nil
+	}
+
+	return chain, nil
+}
+
+// newMediaList wires a tracker to the shared cache and to a store of its own.
+// Each tracker uses distinct media IDs, so their mappings cannot share a store.
+func (wiring *wiring) newMediaList(
+	ctx context.Context,
+	namespace string,
+	cache usecases.Cache,
+	tracker usecases.Tracker,
+	source usecases.Source,
+) (*usecases.MediaList, error) {
+	store, err := wiring.newStore(ctx, namespace+"-store")
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecases.MediaList{
+		Tracker: &cachedtracker.CachedTracker{
+			Cache:     cache,
+			Namespace: namespace,
+			Tracker:   wiring.config.Breaker.wrap(tracker),
+			TTL:       wiring.config.Cache.ttls(),
+		},
+		Source:      source,
+		Store:       store,
+		MovieSource: nil,
+		MovieStore:  nil,
+		Overrides:   overrides.New(path.Join(wiring.config.Storage.DataPath, namespace+"-overrides.yaml")),
+		Prune:       true,
+	}, nil
+}
+
+// redactURL hides the password within rawURL, which makes it safe to log.
+func redactURL(rawURL string) string {
+	uri, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid)"
+	}
+
+	return uri.Redacted()
+}
+
+// ttls returns the tracker cache expiration settings.
+func (cfg cacheConfig) ttls() cachedtracker.TTLs {
+	return cachedtracker.TTLs{
+		UserID:            time.Duration(cfg.UserTTL),
+		MediaListIDs:      time.Duration(cfg.MediaListTTL),
+		MediaListIDsStale: time.Duration(cfg.MediaListStaleTTL),
+		UserIDNotFound:    time.Duration(cfg.UserNotFoundTTL),
+	}
+}
+
+// options returns the Anilist tracker settings, which retry rate limited
+// requests only if there's a retry budget.
+func (cfg anilistConfig) options(client usecases.Doer) []with.Option[anilist.Options] {
+	options := []with.Option[anilist.Options]{
+		anilist.WithPageSize(cfg.PageSize),
+		anilist.WithConcurrency(cfg.Concurrency),
+		anilist.WithClient(client),
+	}
+
+	if cfg.RetryBudget > 0 {
+		options = append(options, anilist.WithRetry(&anilist.Retry{
+			Clock:     nil,
+			Budget:    time.Duration(cfg.RetryBudget),
+			BaseDelay: anilist.DefaultRetryBaseDelay,
+			MaxDelay:  anilist.DefaultRetryMaxDelay,
+		}))
+	}
+
+	return options
+}
+
+// insecureRedirect reports whether the redirect URL lacks TLS, which secure
+// cookies do not reach.
+func (cfg anilistConfig) insecureRedirect() bool {
+	uri, err := url.Parse(cfg.RedirectURL)
+
+	return err == nil && uri.Scheme == "http"
+}
+
+// wrap returns the tracker behind a circuit breaker, unless it is disabled.
+func (cfg breakerConfig) wrap(tracker usecases.Tracker) usecases.Tracker {
+	if cfg.Threshold == 0 {
+		return tracker
+	}
+
+	return &breakertracker.BreakerTracker{
+		Tracker:     tracker,
+		Now:         time.Now,
+		OpenTimeout: time.Duration(cfg.OpenTimeout),
+		Threshold:   cfg.Threshold,
+	}
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/chaincache"
+	"github.com/wwmoraes/anilistarr/internal/drivers/memory"
+)
+
+func TestWiring_newCache(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfig()
+	cfg.Storage.CacheURL = "memory:"
+
+	cache, err := newWiring(cfg).newCache(t.Context())
+	require.NoError(t, err)
+
+	assert.IsType(t, &memory.Cache{}, cache)
+	require.NoError(t, cache.Close())
+}
+
+func TestWiring_newCache_tiers(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfig()
+	cfg.Storage.CacheURL = "memory:"
+	cfg.Storage.CacheTiers = urlList{"memory:?capacity=10"}
+	cfg.Storage.CacheWrite = "behind"
+	cfg.Storage.CacheBackfill = false
+
+	cache, err := newWiring(cfg).newCache(t.Context())
+	require.NoError(t, err)
+
+	chain, ok := cache.(*chaincache.ChainCache)
+	require.True(t, ok)
+
+	assert.Len(t, chain.Caches, 2)
+	assert.Equal(t, chaincache.WriteBehind, chain.Write)
+	assert.False(t, chain.Backfill)
+	require.NoError(t, cache.Close())
+}
+
+func TestWiring_newCache_error(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfig()
+	cfg.Storage.CacheURL = "unknown:"
+	cfg.Storage.CacheTiers = urlList{"memory:"}
+
+	cache, err := newWiring(cfg).newCache(t.Context())
+	require.Error(t, err)
+
+	assert.Nil(t, cache)
+}
@@ -73,18 +73,25 @@ func main() {
 	process.Assert(err)
 
 	cachedTracker := cachedtracker.CachedTracker{
-		Cache:   cache,
-		Tracker: &tracker,
+		Cache:     cache,
+		Namespace: cachedtracker.DefaultNamespace,
+		Tracker:   &tracker,
 		TTL: cachedtracker.TTLs{
-			UserID:       time.Hour,
-			MediaListIDs: time.Hour,
+			UserID:            time.Hour,
+			MediaListIDs:      time.Hour,
+			MediaListIDsStale: 0,
+			UserIDNotFound:    0,
 		},
 	}
 
 	mediaLister := usecases.MediaList{
-		Tracker: &cachedTracker,
-		Source:  sources.JSON[animelists.Anilist2TVDBMetadata](`memory:///test`),
-		Store:   store,
+		Tracker:     &cachedTracker,
+		Source:      sources.JSON[animelists.Anilist2TVDBMetadata](`memory:///test`),
+		Store:       store,
+		MovieSource: nil,
+		MovieStore:  nil,
+		Overrides:   nil,
+		Prune:       false,
 	}
 	defer process.AssertClose(&mediaLister, "failed to close media lister")
 
@@ -112,7 +119,7 @@ func main() {
 
 	log.Info("GenerateCustomList", "username", coverageUsername, "list", customList)
 
-	//nolint:mnd // test data
+	//nolint:exhaustruct,mnd // test data
 	wantedCustomList := entities.CustomList{
 		entities.CustomEntry{TvdbID: 101},
 		entities.CustomEntry{TvdbID: 102},
@@ -30,10 +30,12 @@ func (tracker *memoryTracker) GetUserID(_ context.Context, name string) (string,
 	return strconv.Itoa(id), nil
 }
 
-// GetMediaListIDs retrieves the media IDs of a registered user
+// GetMediaListIDs retrieves the media IDs of a registered user. It has no
+// notion of statuses, so it ignores them.
 func (tracker *memoryTracker) GetMediaListIDs(
 	_ context.Context,
 	userID string,
+	_ ...string,
 ) ([]entities.SourceID, error) {
 	if tracker.MediaLists == nil {
 		return nil, usecases.ErrStatusFailedPrecondition
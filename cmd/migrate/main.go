@@ -0,0 +1,131 @@
+/*
+Migrate shows and applies the schema migrations of SQLite stores and caches.
+
+Usage:
+
+	migrate status|up DATABASE...
+
+Each database is either a SQLite driver URL, e.g. sqlite:///data/sqlite/store,
+or a data source name that the driver receives as is. The handler applies
+pending migrations on start as well; this allows doing so ahead of time.
+*/
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/sqlite"
+	"github.com/wwmoraes/anilistarr/pkg/process"
+)
+
+const usage = `Usage: %s status|up DATABASE...
+
+Commands:
+  status  shows which migrations each database has
+  up      applies all pending migrations to each database
+
+Databases are either SQLite URLs, e.g. sqlite:///data/sqlite/store, or data
+source names.
+`
+
+func main() {
+	defer process.HandleExit()
+
+	flag.CommandLine.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, os.Args[0])
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		process.Exit(2)
+	}
+
+	var command func(ctx context.Context, writer io.Writer, db *sql.DB) error
+
+	switch flag.Arg(0) {
+	case "status":
+		command = status
+	case "up":
+		command = up
+	default:
+		flag.Usage()
+		process.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	for _, database := range flag.Args()[1:] {
+		fmt.Fprintf(os.Stdout, "%s:\n", database)
+
+		db, err := sql.Open("sqlite", dataSourceName(database))
+		process.AssertWith(err, "failed to open database")
+
+		err = command(ctx, os.Stdout, db)
+		process.AssertWith(db.Close(), "failed to close database")
+		process.AssertWith(err, "migration failure")
+	}
+}
+
+func status(ctx context.Context, writer io.Writer, db *sql.DB) error {
+	states, err := sqlite.Status(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "VERSION\tNAME\tAPPLIED AT")
+
+	for _, state := range states {
+		appliedAt := "pending"
+		if state.Applied {
+			appliedAt = state.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(table, "%04d\t%s\t%s\n", state.Version, state.Name, appliedAt)
+	}
+
+	return table.Flush()
+}
+
+func up(ctx context.Context, writer io.Writer, db *sql.DB) error {
+	applied, err := sqlite.Migrate(ctx, db)
+
+	for _, migration := range applied {
+		fmt.Fprintf(writer, "applied %04d %s\n", migration.Version, migration.Name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(writer, "up to date")
+	}
+
+	return nil
+}
+
+// dataSourceName converts SQLite URLs into data source names, and keeps
+// anything else as is.
+func dataSourceName(database string) string {
+	uri, err := url.Parse(database)
+	if err != nil || uri.Scheme != sqlite.Scheme {
+		return database
+	}
+
+	return sqlite.DataSourceName(uri)
+}
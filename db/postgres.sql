@@ -0,0 +1,63 @@
+-- name: GetCacheString :one
+SELECT value
+FROM cache
+WHERE key = @key
+AND (expires_at IS NULL OR expires_at > now())
+LIMIT 1;
+
+-- name: PutCacheString :exec
+INSERT INTO cache (key, value, expires_at)
+VALUES (@key, @value, now() + sqlc.narg(ttl)::INTERVAL)
+ON CONFLICT (key) DO UPDATE
+SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at;
+
+-- name: DeleteExpiredCache :exec
+DELETE FROM cache
+WHERE expires_at <= now();
+
+-- name: GetMedia :one
+SELECT * FROM medias
+WHERE source_id = @id
+LIMIT 1;
+
+-- name: GetMediaBulk :many
+SELECT * FROM medias
+WHERE source_id = ANY(@source_ids::TEXT[]);
+
+-- name: PutMedia :exec
+INSERT INTO medias (source_id, target_id, target_season)
+VALUES (@source_id, @target_id, @target_season)
+ON CONFLICT (source_id, target_id) DO UPDATE
+SET target_season = EXCLUDED.target_season;
+
+-- name: PutMedias :batchexec
+INSERT INTO medias (source_id, target_id, target_season)
+VALUES (@source_id, @target_id, @target_season)
+ON CONFLICT (source_id, target_id) DO UPDATE
+SET target_season = EXCLUDED.target_season;
+
+-- name: DeleteMedias :exec
+DELETE FROM medias;
+
+-- name: GetToken :one
+SELECT * FROM tokens
+WHERE user_id = @user_id
+LIMIT 1;
+
+-- name: GetTokenByName :one
+SELECT * FROM tokens
+WHERE lower(user_name) = lower(@user_name)
+LIMIT 1;
+
+-- name: DeleteTokenByName :exec
+DELETE FROM tokens
+WHERE lower(user_name) = lower(@user_name)
+AND user_id <> @user_id;
+
+-- name: PutToken :exec
+INSERT INTO tokens (user_id, user_name, access_token, expires_at)
+VALUES (@user_id, @user_name, @access_token, @expires_at)
+ON CONFLICT (user_id) DO UPDATE
+SET user_name = EXCLUDED.user_name,
+	access_token = EXCLUDED.access_token,
+	expires_at = EXCLUDED.expires_at;
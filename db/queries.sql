@@ -2,11 +2,16 @@
 SELECT value
 FROM cache
 WHERE key = @key
+AND (expires_at IS NULL OR expires_at > CAST(@now AS INTEGER))
 LIMIT 1;
 
 -- name: PutCacheString :exec
-REPLACE INTO cache (key, value)
-VALUES (@key, @value);
+REPLACE INTO cache (key, value, expires_at)
+VALUES (@key, @value, @expires_at);
+
+-- name: DeleteExpiredCache :exec
+DELETE FROM cache
+WHERE expires_at <= CAST(@now AS INTEGER);
 
 -- name: GetMedia :one
 SELECT * FROM medias
@@ -19,5 +24,22 @@ WHERE source_id
 IN (sqlc.slice(source_ids));
 
 -- name: PutMedia :exec
-REPLACE INTO medias (source_id, target_id)
-VALUES (@source_id, @target_id);
+REPLACE INTO medias (source_id, target_id, target_season)
+VALUES (@source_id, @target_id, @target_season);
+
+-- name: DeleteMedias :exec
+DELETE FROM medias;
+
+-- name: GetToken :one
+SELECT * FROM tokens
+WHERE user_id = @user_id
+LIMIT 1;
+
+-- name: GetTokenByName :one
+SELECT * FROM tokens
+WHERE user_name = @user_name
+LIMIT 1;
+
+-- name: PutToken :exec
+REPLACE INTO tokens (user_id, user_name, access_token, expires_at)
+VALUES (@user_id, @user_name, @access_token, @expires_at);
@@ -0,0 +1,237 @@
+// Package breakertracker provides a [usecases.Tracker] wrapper that stops
+// requesting an upstream tracker while it is failing.
+package breakertracker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	// Closed lets all requests through.
+	Closed State = iota
+	// Open fails all requests fast.
+	Open
+	// HalfOpen lets a single probe request through.
+	HalfOpen
+
+	// DefaultThreshold is the consecutive failures that open a breaker without a
+	// Threshold set.
+	DefaultThreshold = 5
+	// DefaultOpenTimeout is how long a breaker without an OpenTimeout set stays
+	// open before probing.
+	DefaultOpenTimeout = 30 * time.Second
+)
+
+var _ usecases.Tracker = (*BreakerTracker)(nil)
+
+// BreakerTracker is a meta-tracker that implements a circuit breaker.
+//
+// It is an [usecases.Tracker] drop-in replacement that wraps another tracker.
+// It opens after Threshold consecutive failures, and then fails requests fast
+// with [usecases.ErrStatusUnavailable] instead of waiting on the tracker. Once
+// OpenTimeout passes it half-opens, which lets a single probe request through.
+// The breaker closes if the probe succeeds, or opens again otherwise.
+//
+// Errors caused by the request itself, such as unknown users or invalid
+// arguments, are not failures. Requests whose caller gives up, or that rate
+// limits reject, count as neither, as those tell nothing about the tracker.
+//
+// Request spans have the breaker state as an attribute, and its changes as
+// events.
+//
+// Wrap it with a [cachedtracker.CachedTracker] to keep serving stale entries
+// while it is open.
+//
+// Now defaults to [time.Now].
+type BreakerTracker struct {
+	openedAt    time.Time
+	Tracker     usecases.Tracker
+	Now         func() time.Time
+	OpenTimeout time.Duration
+	Threshold   int
+	failures    int
+	mutex       sync.Mutex
+	state       State
+}
+
+// State is the state of a [BreakerTracker].
+type State int
+
+// GetUserID retrieves an user ID from the tracker, unless the breaker is open.
+func (breaker *BreakerTracker) GetUserID(ctx context.Context, name string) (string, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	err := breaker.allow(ctx)
+	if err != nil {
+		return "", span.Assert(err)
+	}
+
+	userID, err := breaker.Tracker.GetUserID(ctx, name)
+	breaker.record(ctx, err)
+
+	//nolint:wrapcheck // passthrough
+	return userID, span.Assert(err)
+}
+
+// GetMediaListIDs retrieves the list of medias for an user ID from the tracker,
+// unless the breaker is open.
+func (breaker *BreakerTracker) GetMediaListIDs(
+	ctx context.Context,
+	userID string,
+	statuses ...string,
+) ([]string, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	err := breaker.allow(ctx)
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	ids, err := breaker.Tracker.GetMediaListIDs(ctx, userID, statuses...)
+	breaker.record(ctx, err)
+
+	//nolint:wrapcheck // passthrough
+	return ids, span.Assert(err)
+}
+
+// Close terminates the tracker.
+func (breaker *BreakerTracker) Close() error {
+	//nolint:wrapcheck // passthrough
+	return breaker.Tracker.Close()
+}
+
+// State returns the current state of the breaker.
+func (breaker *BreakerTracker) State() State {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	return breaker.state
+}
+
+// String implements [fmt.Stringer].
+func (state State) String() string {
+	switch state {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// allow returns an error if the request must fail fast. Open breakers past
+// their timeout half-open and let this request through as the probe.
+func (breaker *BreakerTracker) allow(ctx context.Context) error {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	defer breaker.annotate(ctx)
+
+	switch breaker.state {
+	case Closed:
+		return nil
+	case Open:
+		if breaker.now().Sub(breaker.openedAt) >= breaker.openTimeout() {
+			breaker.transition(ctx, HalfOpen)
+
+			return nil
+		}
+	case HalfOpen:
+		// the probe is in flight already
+	}
+
+	return fmt.Errorf("%w: circuit breaker is %s", usecases.ErrStatusUnavailable, breaker.state)
+}
+
+// record updates the breaker state with the request result.
+func (breaker *BreakerTracker) record(ctx context.Context, err error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	defer breaker.annotate(ctx)
+
+	switch {
+	case usecases.ErrorIn(err, usecases.ErrStatusCancelled, context.Canceled, usecases.ErrStatusResourceExhausted):
+		// tells nothing about the tracker, so the next request probes it instead
+		if breaker.state == HalfOpen {
+			breaker.transition(ctx, Open)
+		}
+	case err == nil, usecases.ErrorIn(err, usecases.ErrStatusNotFound, usecases.ErrStatusInvalidArgument):
+		breaker.failures = 0
+
+		if breaker.state != Closed {
+			breaker.transition(ctx, Closed)
+		}
+	default:
+		breaker.failures++
+
+		if breaker.state == HalfOpen || breaker.failures >= breaker.threshold() {
+			breaker.openedAt = breaker.now()
+			breaker.transition(ctx, Open)
+		}
+	}
+}
+
+// transition changes the breaker state, reporting it. Callers must hold the
+// mutex.
+func (breaker *BreakerTracker) transition(ctx context.Context, state State) {
+	from := breaker.state
+	breaker.state = state
+
+	telemetry.SpanFromContext(ctx).AddEvent("circuit breaker state change", trace.WithAttributes(
+		attribute.Stringer("from", from),
+		attribute.Stringer("to", state),
+		attribute.Int("failures", breaker.failures),
+	))
+
+	telemetry.Logr(ctx).Info("circuit breaker state change",
+		"from", from.String(),
+		"to", state.String(),
+		"failures", breaker.failures,
+	)
+}
+
+// annotate sets the breaker state on the request span. Callers must hold the
+// mutex.
+func (breaker *BreakerTracker) annotate(ctx context.Context) {
+	telemetry.SpanFromContext(ctx).SetAttributes(
+		attribute.Stringer("circuit_breaker.state", breaker.state),
+		attribute.Int("circuit_breaker.failures", breaker.failures),
+	)
+}
+
+func (breaker *BreakerTracker) now() time.Time {
+	if breaker.Now == nil {
+		return time.Now()
+	}
+
+	return breaker.Now()
+}
+
+func (breaker *BreakerTracker) openTimeout() time.Duration {
+	if breaker.OpenTimeout <= 0 {
+		return DefaultOpenTimeout
+	}
+
+	return breaker.OpenTimeout
+}
+
+func (breaker *BreakerTracker) threshold() int {
+	if breaker.Threshold <= 0 {
+		return DefaultThreshold
+	}
+
+	return breaker.Threshold
+}
@@ -0,0 +1,314 @@
+package breakertracker_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/breakertracker"
+	"github.com/wwmoraes/anilistarr/internal/adapters/cachedtracker"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+var errUpstream = errors.New("upstream failure")
+
+// fakeClock tells a time that only changes when advanced.
+type fakeClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(delay time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(delay)
+}
+
+func TestBreakerTracker_opens(t *testing.T) {
+	t.Parallel()
+
+	tracker := test.NewMockTracker(t)
+	tracker.EXPECT().GetUserID(mock.Anything, "foo").Return("", errUpstream).Times(3)
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker:   tracker,
+		Threshold: 3,
+	}
+
+	for range 3 {
+		_, err := breaker.GetUserID(t.Context(), "foo")
+		require.ErrorIs(t, err, errUpstream)
+	}
+
+	assert.Equal(t, breakertracker.Open, breaker.State())
+
+	// fails fast without requesting the tracker
+	_, err := breaker.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+	_, err = breaker.GetMediaListIDs(t.Context(), "1")
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+}
+
+func TestBreakerTracker_consecutive(t *testing.T) {
+	t.Parallel()
+
+	tracker := test.NewMockTracker(t)
+	tracker.EXPECT().GetUserID(mock.Anything, "foo").Return("", errUpstream).Times(4)
+	tracker.EXPECT().GetUserID(mock.Anything, "bar").Return("1", nil).Once()
+	tracker.EXPECT().GetUserID(mock.Anything, "baz").Return("", usecases.ErrStatusNotFound).Once()
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker:   tracker,
+		Threshold: 3,
+	}
+
+	for _, name := range []string{"foo", "foo", "bar", "foo", "foo", "baz"} {
+		_, err := breaker.GetUserID(t.Context(), name)
+		require.NotErrorIs(t, err, usecases.ErrStatusUnavailable)
+	}
+
+	assert.Equal(t, breakertracker.Closed, breaker.State())
+}
+
+func TestBreakerTracker_rate_limited(t *testing.T) {
+	t.Parallel()
+
+	errLimited := errors.Join(usecases.ErrStatusResourceExhausted, errUpstream)
+
+	tracker := test.NewMockTracker(t)
+	tracker.EXPECT().GetUserID(mock.Anything, "foo").Return("", errLimited).Times(3)
+	tracker.EXPECT().GetUserID(mock.Anything, "bar").Return("", errUpstream).Once()
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker:   tracker,
+		Threshold: 2,
+	}
+
+	// rejections by the local rate limits are neither failures nor successes
+	for _, name := range []string{"bar", "foo", "foo", "foo"} {
+		_, err := breaker.GetUserID(t.Context(), name)
+		require.ErrorIs(t, err, errUpstream)
+	}
+
+	assert.Equal(t, breakertracker.Closed, breaker.State())
+}
+
+func TestBreakerTracker_state_attribute(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, root := provider.Tracer(t.Name()).Start(t.Context(), "root")
+	defer root.End()
+
+	tracker := test.NewMockTracker(t)
+	tracker.EXPECT().GetUserID(mock.Anything, "foo").Return("", errUpstream).Once()
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker:   tracker,
+		Threshold: 1,
+	}
+
+	for range 2 {
+		_, err := breaker.GetUserID(ctx, "foo")
+		require.Error(t, err)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	for _, span := range spans {
+		assert.Contains(t, span.Attributes(), attribute.String("circuit_breaker.state", "open"))
+	}
+}
+
+func TestBreakerTracker_half_open(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		probeErr  error
+		name      string
+		wantState breakertracker.State
+	}{
+		{
+			name:      "probe succeeds",
+			probeErr:  nil,
+			wantState: breakertracker.Closed,
+		},
+		{
+			name:      "probe fails",
+			probeErr:  errUpstream,
+			wantState: breakertracker.Open,
+		},
+		{
+			name:      "probe given up",
+			probeErr:  errors.Join(usecases.ErrStatusCancelled, context.Canceled),
+			wantState: breakertracker.Open,
+		},
+		{
+			name:      "probe rate limited",
+			probeErr:  usecases.ErrStatusResourceExhausted,
+			wantState: breakertracker.Open,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &fakeClock{now: time.Now()}
+
+			tracker := test.NewMockTracker(t)
+			tracker.EXPECT().GetMediaListIDs(mock.Anything, "1").Return(nil, errUpstream).Once()
+			tracker.EXPECT().GetMediaListIDs(mock.Anything, "1").Return([]string{"ID1"}, tt.probeErr).Once()
+
+			breaker := breakertracker.BreakerTracker{
+				Tracker:     tracker,
+				Now:         clock.Now,
+				OpenTimeout: time.Minute,
+				Threshold:   1,
+			}
+
+			_, err := breaker.GetMediaListIDs(t.Context(), "1")
+			require.ErrorIs(t, err, errUpstream)
+			require.Equal(t, breakertracker.Open, breaker.State())
+
+			clock.Advance(time.Minute - time.Second)
+
+			_, err = breaker.GetMediaListIDs(t.Context(), "1")
+			require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+			clock.Advance(time.Second)
+
+			_, err = breaker.GetMediaListIDs(t.Context(), "1")
+			require.ErrorIs(t, err, tt.probeErr)
+
+			assert.Equal(t, tt.wantState, breaker.State())
+		})
+	}
+}
+
+func TestBreakerTracker_single_probe(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Now()}
+	probing := make(chan struct{})
+	release := make(chan struct{})
+
+	tracker := test.NewMockTracker(t)
+	tracker.EXPECT().GetUserID(mock.Anything, "foo").Return("", errUpstream).Once()
+	tracker.EXPECT().GetUserID(mock.Anything, "foo").RunAndReturn(func(context.Context, string) (string, error) {
+		close(probing)
+		<-release
+
+		return "1", nil
+	}).Once()
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker:     tracker,
+		Now:         clock.Now,
+		OpenTimeout: time.Minute,
+		Threshold:   1,
+	}
+
+	_, err := breaker.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, errUpstream)
+
+	clock.Advance(time.Minute)
+
+	probeErr := make(chan error, 1)
+
+	go func() {
+		_, err := breaker.GetUserID(t.Context(), "foo")
+		probeErr <- err
+	}()
+
+	<-probing
+
+	assert.Equal(t, breakertracker.HalfOpen, breaker.State())
+
+	_, err = breaker.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+	close(release)
+
+	require.NoError(t, <-probeErr)
+	assert.Equal(t, breakertracker.Closed, breaker.State())
+}
+
+func TestBreakerTracker_stale_cache(t *testing.T) {
+	t.Parallel()
+
+	cacheKeyUserMedia := "anilist:user:1:media"
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	// opens the breaker, so the background refresh of the stale entry fails fast
+	tracker.EXPECT().GetMediaListIDs(mock.Anything, "2").Return(nil, errUpstream).Once()
+	cache.EXPECT().GetString(mock.Anything, cacheKeyUserMedia).Return("1;ID1|ID2", nil).Once()
+	cache.EXPECT().Close().Return(nil).Once()
+	tracker.EXPECT().Close().Return(nil).Once()
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker:   tracker,
+		Threshold: 1,
+	}
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: &breaker,
+		TTL: cachedtracker.TTLs{
+			MediaListIDs:      time.Hour,
+			MediaListIDsStale: 24 * time.Hour,
+		},
+	}
+
+	_, err := breaker.GetMediaListIDs(t.Context(), "2")
+	require.ErrorIs(t, err, errUpstream)
+
+	ctx, stale := usecases.WithStaleness(t.Context())
+
+	got, err := cachedTracker.GetMediaListIDs(ctx, "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ID1", "ID2"}, got)
+	assert.True(t, stale())
+
+	// waits for the background refresh
+	require.NoError(t, cachedTracker.Close())
+	assert.Equal(t, breakertracker.Open, breaker.State())
+}
+
+func TestBreakerTracker_Close(t *testing.T) {
+	t.Parallel()
+
+	tracker := test.NewMockTracker(t)
+	tracker.EXPECT().Close().Return(errUpstream).Once()
+
+	breaker := breakertracker.BreakerTracker{
+		Tracker: tracker,
+	}
+
+	require.ErrorIs(t, breaker.Close(), errUpstream)
+}
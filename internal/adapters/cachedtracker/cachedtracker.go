@@ -7,23 +7,37 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
 	telemetry "github.com/wwmoraes/gotell"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
 const (
-	cacheKeyUserID     string = "anilist:user:%s:id"
-	cacheKeyUserMedia  string = "anilist:user:%s:media"
+	cacheKeyUserID     string = "%s:user:%s:id"
+	cacheKeyUserMedia  string = "%s:user:%s:media"
+	cacheKeyStatuses   string = "%s:%s"
+	statusesSeparator  string = ","
 	mediaListSeparator string = "|"
+	staleAtSeparator   string = ";"
+	// userNotFound is the cached value of user names the tracker lacks.
+	userNotFound string = "!"
+
+	// DefaultNamespace is the cache key prefix used when none is set.
+	DefaultNamespace string = "anilist"
 )
 
-var _ usecases.Tracker = (*CachedTracker)(nil)
+var (
+	_ usecases.Tracker     = (*CachedTracker)(nil)
+	_ usecases.UserIDCache = (*CachedTracker)(nil)
+)
 
 // CachedTracker is a meta-tracker that provides cached responses.
 //
@@ -34,27 +48,44 @@ var _ usecases.Tracker = (*CachedTracker)(nil)
 // Misses automatically update the cache with the tracker response.
 //
 // It sets a TTL for each cached response for implementations that supports it.
+//
+// Cache keys are prefixed by Namespace, which allows multiple trackers to share
+// the same cache. It defaults to [DefaultNamespace] if empty. Media lists are
+// cached separately for each set of statuses requested.
+//
+// Concurrent misses of the same key share a single tracker request and its
+// result.
 type CachedTracker struct {
-	Cache   usecases.Cache
-	Tracker usecases.Tracker
-	TTL     TTLs
+	Cache      usecases.Cache
+	Tracker    usecases.Tracker
+	lookups    singleflight.Group
+	Namespace  string
+	TTL        TTLs
+	refreshing sync.WaitGroup
 }
 
 // TTLs contains the time-to-live for entries of each type handled by trackers.
+// MediaListIDsStale is how long media lists past their TTL get served as stale
+// while refreshing. UserIDNotFound is how long the tracker lacking an user
+// remains cached. Zero disables either.
 //
 // TODO refactor to use [adapters.CacheParams] instead of [time.Duration]
 type TTLs struct {
-	UserID       time.Duration
-	MediaListIDs time.Duration
+	UserID            time.Duration
+	MediaListIDs      time.Duration
+	MediaListIDsStale time.Duration
+	UserIDNotFound    time.Duration
 }
 
 // GetUserID retrieves an user ID for a given name. It returns a cache value if
 // available; otherwise it queries the tracker and caches it for future use.
+// Users the tracker lacks get cached as such, and result in
+// [usecases.ErrStatusNotFound] without querying it again.
 func (wrapper *CachedTracker) GetUserID(ctx context.Context, name string) (string, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
-	key := fmt.Sprintf(cacheKeyUserID, name)
+	key := fmt.Sprintf(cacheKeyUserID, wrapper.namespace(), name)
 
 	span.AddEvent("try cache")
 
@@ -63,6 +94,12 @@ func (wrapper *CachedTracker) GetUserID(ctx context.Context, name string) (strin
 		return "", span.Assert(errors.Join(usecases.ErrStatusUnknown, err))
 	}
 
+	if userID == userNotFound {
+		span.AddEvent("negative cache hit")
+
+		return "", span.Assert(fmt.Errorf("%w: user %q", usecases.ErrStatusNotFound, name))
+	}
+
 	if userID != "" {
 		span.AddEvent("cache hit")
 
@@ -71,32 +108,70 @@ func (wrapper *CachedTracker) GetUserID(ctx context.Context, name string) (strin
 
 	span.AddEvent("cache miss")
 
-	userID, err = wrapper.Tracker.GetUserID(ctx, name)
-	if err != nil {
-		return "", span.Assert(errors.Join(usecases.ErrStatusUnknown, err))
-	}
+	userID, err = coalesce(ctx, &wrapper.lookups, key, func(ctx context.Context) (string, error) {
+		userID, err := wrapper.Tracker.GetUserID(ctx, name)
+		if errors.Is(err, usecases.ErrStatusNotFound) && wrapper.TTL.UserIDNotFound > 0 {
+			err = errors.Join(err, wrapper.Cache.SetString(
+				ctx,
+				key,
+				userNotFound,
+				usecases.WithTTL(wrapper.TTL.UserIDNotFound),
+			))
+		}
 
-	err = wrapper.Cache.SetString(
-		ctx,
-		key,
-		userID,
-		usecases.WithTTL(wrapper.TTL.UserID),
-	)
+		if err != nil {
+			return "", errors.Join(usecases.ErrStatusUnknown, err)
+		}
+
+		//nolint:wrapcheck // caches are internal
+		return userID, wrapper.Cache.SetString(
+			ctx,
+			key,
+			userID,
+			usecases.WithTTL(wrapper.TTL.UserID),
+		)
+	})
 
 	return userID, span.Assert(err)
 }
 
+// PutUserID caches the ID of an user the tracker is known to have, replacing
+// any cached absence of them.
+func (wrapper *CachedTracker) PutUserID(ctx context.Context, name, userID string) error {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	return span.Assert(usecases.ErrorJoinIf(
+		usecases.ErrStatusUnknown,
+		wrapper.Cache.SetString(
+			ctx,
+			fmt.Sprintf(cacheKeyUserID, wrapper.namespace(), name),
+			userID,
+			usecases.WithTTL(wrapper.TTL.UserID),
+		),
+	))
+}
+
 // GetMediaListIDs retrieves the list of medias for an user ID. It returns a
 // cache value if available; otherwise it requests the tracker and caches it for
 // future use.
+//
+// Lists past their TTL remain cached for the stale TTL, if any. Those get
+// served as stale while a background request refreshes them. Failed refreshes
+// keep the last list until it expires for good.
 func (wrapper *CachedTracker) GetMediaListIDs(
 	ctx context.Context,
 	userID string,
+	statuses ...string,
 ) ([]string, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
-	key := fmt.Sprintf(cacheKeyUserMedia, userID)
+	key := mediaListKey(wrapper.namespace(), userID, statuses)
+
+	fetch := func(ctx context.Context) ([]string, error) {
+		return wrapper.fetchMediaListIDs(ctx, key, userID, statuses)
+	}
 
 	span.AddEvent("try cache")
 
@@ -106,30 +181,33 @@ func (wrapper *CachedTracker) GetMediaListIDs(
 	}
 
 	if cachedIDs != "" {
-		span.AddEvent("cache hit")
+		ids, staleAt := parseMediaList(cachedIDs)
+		if staleAt.IsZero() || time.Now().Before(staleAt) {
+			span.AddEvent("cache hit")
 
-		return strings.Split(cachedIDs, mediaListSeparator), span.Assert(err)
-	}
+			return ids, span.Assert(nil)
+		}
 
-	span.AddEvent("cache miss")
+		span.AddEvent("stale cache hit")
 
-	ids, err := wrapper.Tracker.GetMediaListIDs(ctx, userID)
-	if err != nil {
-		return nil, span.Assert(errors.Join(usecases.ErrStatusUnknown, err))
+		usecases.MarkStale(ctx)
+		wrapper.revalidate(ctx, key, fetch)
+
+		return ids, span.Assert(nil)
 	}
 
-	err = wrapper.Cache.SetString(
-		ctx,
-		key,
-		strings.Join(ids, mediaListSeparator),
-		usecases.WithTTL(wrapper.TTL.MediaListIDs),
-	)
+	span.AddEvent("cache miss")
+
+	ids, err := coalesce(ctx, &wrapper.lookups, key, fetch)
 
 	return ids, span.Assert(err)
 }
 
-// Close terminates the client and its connection to the cache.
+// Close waits for background refreshes, then terminates the client and its
+// connection to the cache.
 func (wrapper *CachedTracker) Close() error {
+	wrapper.refreshing.Wait()
+
 	closers := [...]io.Closer{
 		wrapper.Cache,
 		wrapper.Tracker,
@@ -167,3 +245,149 @@ func (wrapper *CachedTracker) Close() error {
 	//nolint:wrapcheck // components are internal
 	return multierror.Append(nil, errs...).ErrorOrNil()
 }
+
+// fetchMediaListIDs requests the list of medias from the tracker and caches it.
+func (wrapper *CachedTracker) fetchMediaListIDs(
+	ctx context.Context,
+	key, userID string,
+	statuses []string,
+) ([]string, error) {
+	ids, err := wrapper.Tracker.GetMediaListIDs(ctx, userID, statuses...)
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusUnknown, err)
+	}
+
+	ttl := wrapper.TTL.MediaListIDs
+
+	var staleAt time.Time
+
+	if ttl > 0 && wrapper.TTL.MediaListIDsStale > 0 {
+		staleAt = time.Now().Add(ttl)
+		ttl += wrapper.TTL.MediaListIDsStale
+	}
+
+	//nolint:wrapcheck // caches are internal
+	return ids, wrapper.Cache.SetString(
+		ctx,
+		key,
+		formatMediaList(ids, staleAt),
+		usecases.WithTTL(ttl),
+	)
+}
+
+// revalidate refreshes a stale entry in the background. It joins any lookup of
+// the same key in flight.
+func (wrapper *CachedTracker) revalidate(
+	ctx context.Context,
+	key string,
+	fetch func(ctx context.Context) ([]string, error),
+) {
+	ctx = context.WithoutCancel(ctx)
+
+	wrapper.refreshing.Add(1)
+
+	go func() {
+		defer wrapper.refreshing.Done()
+
+		_, err := coalesce(ctx, &wrapper.lookups, key, fetch)
+		if err != nil {
+			telemetry.Logr(ctx).Error(err, "failed to refresh stale entry", "key", key)
+		}
+	}()
+}
+
+func (wrapper *CachedTracker) namespace() string {
+	if wrapper.Namespace == "" {
+		return DefaultNamespace
+	}
+
+	return wrapper.Namespace
+}
+
+// coalesce runs fetch once for all concurrent callers with the same key, which
+// then share its result. The fetch outlives the cancellation of any caller, as
+// others may still wait for it; callers that give up stop waiting instead.
+func coalesce[T any](
+	ctx context.Context,
+	group *singleflight.Group,
+	key string,
+	fetch func(ctx context.Context) (T, error),
+) (T, error) {
+	results := group.DoChan(key, func() (any, error) {
+		return fetch(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+
+		return zero, errors.Join(usecases.ErrStatusCancelled, ctx.Err())
+	case result := <-results:
+		if result.Shared {
+			telemetry.SpanFromContext(ctx).AddEvent("shared lookup")
+		}
+
+		value, ok := result.Val.(T)
+		if !ok {
+			return value, errors.Join(usecases.ErrStatusInternal, result.Err)
+		}
+
+		//nolint:wrapcheck // fetch errors are wrapped already
+		return value, result.Err
+	}
+}
+
+// formatMediaList encodes a media list into a cache value. Lists that turn
+// stale have the Unix time they do so as prefix.
+func formatMediaList(ids []string, staleAt time.Time) string {
+	list := strings.Join(ids, mediaListSeparator)
+
+	if staleAt.IsZero() {
+		return list
+	}
+
+	return strconv.FormatInt(staleAt.Unix(), 10) + staleAtSeparator + list
+}
+
+// parseMediaList decodes a cache value into a media list and the time it turns
+// stale, which is zero if it never does. Invalid times mean it is stale
+// already.
+func parseMediaList(value string) ([]string, time.Time) {
+	prefix, list, found := strings.Cut(value, staleAtSeparator)
+	if !found {
+		return strings.Split(value, mediaListSeparator), time.Time{}
+	}
+
+	ids := []string{}
+	if list != "" {
+		ids = strings.Split(list, mediaListSeparator)
+	}
+
+	staleAt, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return ids, time.Unix(0, 0)
+	}
+
+	return ids, time.Unix(staleAt, 0)
+}
+
+// mediaListKey generates the cache key for a media list. The statuses are
+// upper-cased, sorted and deduplicated so equivalent sets share the same key.
+func mediaListKey(namespace, userID string, statuses []string) string {
+	key := fmt.Sprintf(cacheKeyUserMedia, namespace, userID)
+
+	if len(statuses) == 0 {
+		return key
+	}
+
+	normalized := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		normalized = append(normalized, strings.ToUpper(status))
+	}
+
+	slices.Sort(normalized)
+
+	statuses = slices.Compact(normalized)
+
+	return fmt.Sprintf(cacheKeyStatuses, key, strings.Join(statuses, statusesSeparator))
+}
@@ -1,9 +1,13 @@
 package cachedtracker_test
 
 import (
+	"context"
 	"errors"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -249,3 +253,445 @@ func TestCachedTracker_Tracker_error(t *testing.T) {
 
 	assert.Nil(t, gotMedias)
 }
+
+func TestCachedTracker_Namespace(t *testing.T) {
+	t.Parallel()
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	cache.EXPECT().GetString(
+		mock.Anything,
+		"mal:user:foo:id",
+	).Return("foo", nil).Once()
+	cache.EXPECT().GetString(
+		mock.Anything,
+		"mal:user:foo:media",
+	).Return("1|2", nil).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:     cache,
+		Tracker:   tracker,
+		Namespace: "mal",
+	}
+
+	gotUserID, err := cachedTracker.GetUserID(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", gotUserID)
+
+	gotMedias, err := cachedTracker.GetMediaListIDs(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "2"}, gotMedias)
+}
+
+func TestCachedTracker_GetMediaListIDs_statuses(t *testing.T) {
+	t.Parallel()
+
+	userID := "1"
+	medias := []string{"ID1", "ID2"}
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	cache.EXPECT().GetString(
+		mock.Anything,
+		"anilist:user:1:media:COMPLETED,PAUSED",
+	).Return("", usecases.ErrStatusNotFound).Once()
+	cache.EXPECT().SetString(
+		mock.Anything,
+		"anilist:user:1:media:COMPLETED,PAUSED",
+		"ID1|ID2",
+		mock.Anything,
+	).Return(nil).Once()
+	tracker.EXPECT().GetMediaListIDs(
+		mock.Anything,
+		userID,
+		[]string{"PAUSED", "COMPLETED", "PAUSED"},
+	).Return(medias, nil).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: tracker,
+	}
+
+	gotMediaListIDs, err := cachedTracker.GetMediaListIDs(
+		t.Context(),
+		userID,
+		"PAUSED",
+		"COMPLETED",
+		"PAUSED",
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, medias, gotMediaListIDs)
+}
+
+func TestCachedTracker_GetMediaListIDs_statusesCase(t *testing.T) {
+	t.Parallel()
+
+	userID := "1"
+	medias := []string{"ID1", "ID2"}
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	cache.EXPECT().GetString(
+		mock.Anything,
+		"anilist:user:1:media:CURRENT",
+	).Return("", usecases.ErrStatusNotFound).Once()
+	cache.EXPECT().SetString(
+		mock.Anything,
+		"anilist:user:1:media:CURRENT",
+		"ID1|ID2",
+		mock.Anything,
+	).Return(nil).Once()
+	cache.EXPECT().GetString(
+		mock.Anything,
+		"anilist:user:1:media:CURRENT",
+	).Return("ID1|ID2", nil).Once()
+	tracker.EXPECT().GetMediaListIDs(
+		mock.Anything,
+		userID,
+		[]string{"CURRENT"},
+	).Return(medias, nil).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: tracker,
+	}
+
+	gotMediaListIDs, err := cachedTracker.GetMediaListIDs(t.Context(), userID, "CURRENT")
+	require.NoError(t, err)
+	assert.Equal(t, medias, gotMediaListIDs)
+
+	gotMediaListIDs, err = cachedTracker.GetMediaListIDs(t.Context(), userID, "current")
+	require.NoError(t, err)
+	assert.Equal(t, medias, gotMediaListIDs)
+}
+
+func TestCachedTracker_GetMediaListIDs_coalesced(t *testing.T) {
+	t.Parallel()
+
+	const callers = 8
+
+	userID := "1"
+	medias := []string{"ID1", "ID2"}
+	cacheKeyUserMedia := "anilist:user:1:media"
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	var missed sync.WaitGroup
+
+	missed.Add(callers)
+
+	release := make(chan struct{})
+
+	cache.EXPECT().GetString(mock.Anything, cacheKeyUserMedia).
+		RunAndReturn(func(context.Context, string) (string, error) {
+			missed.Done()
+
+			return "", usecases.ErrStatusNotFound
+		}).Times(callers)
+	cache.EXPECT().SetString(mock.Anything, cacheKeyUserMedia, "ID1|ID2", mock.Anything).
+		Return(nil).Once()
+
+	// a single request upstream, which waits for all callers to miss
+	tracker.EXPECT().GetMediaListIDs(mock.Anything, userID).
+		RunAndReturn(func(context.Context, string, ...string) ([]string, error) {
+			<-release
+
+			return medias, nil
+		}).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: tracker,
+	}
+
+	var wg sync.WaitGroup
+
+	got := make([][]string, callers)
+	errs := make([]error, callers)
+
+	for index := range callers {
+		wg.Go(func() {
+			got[index], errs[index] = cachedTracker.GetMediaListIDs(t.Context(), userID)
+		})
+	}
+
+	missed.Wait()
+	// gives the callers that missed last time to join the lookup
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.NoError(t, errors.Join(errs...))
+
+	for _, ids := range got {
+		assert.Equal(t, medias, ids)
+	}
+}
+
+func TestCachedTracker_GetUserID_canceled(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	cacheKeyUserID := "anilist:user:foo:id"
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	release := make(chan struct{})
+	stored := make(chan struct{})
+
+	cache.EXPECT().GetString(mock.Anything, cacheKeyUserID).
+		Return("", usecases.ErrStatusNotFound).Once()
+	cache.EXPECT().SetString(mock.Anything, cacheKeyUserID, "1", mock.Anything).
+		RunAndReturn(func(context.Context, string, string, ...usecases.CacheOption) error {
+			close(stored)
+
+			return nil
+		}).Once()
+
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		RunAndReturn(func(ctx context.Context, _ string) (string, error) {
+			<-release
+
+			// the lookup outlives the caller
+			assert.NoError(t, ctx.Err())
+
+			return "1", nil
+		}).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: tracker,
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := cachedTracker.GetUserID(ctx, username)
+	require.ErrorIs(t, err, usecases.ErrStatusCancelled)
+
+	close(release)
+	<-stored
+}
+
+func TestCachedTracker_GetMediaListIDs_stale(t *testing.T) {
+	t.Parallel()
+
+	cacheKeyUserMedia := "anilist:user:1:media"
+	ttls := cachedtracker.TTLs{
+		MediaListIDs:      time.Hour,
+		MediaListIDsStale: 24 * time.Hour,
+	}
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		refreshError error
+		name         string
+		cached       string
+		want         []string
+		wantStale    bool
+	}{
+		{
+			name:   "fresh",
+			cached: future + ";ID1|ID2",
+			want:   []string{"ID1", "ID2"},
+		},
+		{
+			name:   "fresh empty",
+			cached: future + ";",
+			want:   []string{},
+		},
+		{
+			name:   "legacy",
+			cached: "ID1|ID2",
+			want:   []string{"ID1", "ID2"},
+		},
+		{
+			name:      "stale",
+			cached:    "1;ID1|ID2",
+			want:      []string{"ID1", "ID2"},
+			wantStale: true,
+		},
+		{
+			name:         "stale with refresh error",
+			cached:       "1;ID1|ID2",
+			refreshError: errors.New("qux"),
+			want:         []string{"ID1", "ID2"},
+			wantStale:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := test.NewMockCache(t)
+			tracker := test.NewMockTracker(t)
+
+			cache.EXPECT().GetString(mock.Anything, cacheKeyUserMedia).Return(tt.cached, nil).Once()
+			cache.EXPECT().Close().Return(nil).Once()
+			tracker.EXPECT().Close().Return(nil).Once()
+
+			if tt.wantStale {
+				tracker.EXPECT().GetMediaListIDs(mock.Anything, "1").Return([]string{"ID3"}, tt.refreshError).Once()
+			}
+
+			if tt.wantStale && tt.refreshError == nil {
+				cache.EXPECT().SetString(
+					mock.Anything,
+					cacheKeyUserMedia,
+					mock.MatchedBy(func(value string) bool {
+						return strings.HasSuffix(value, ";ID3")
+					}),
+					mock.Anything,
+				).RunAndReturn(func(_ context.Context, _, _ string, options ...usecases.CacheOption) error {
+					assert.Equal(t, 25*time.Hour, usecases.NewCacheOptions(options...).TTL)
+
+					return nil
+				}).Once()
+			}
+
+			cachedTracker := cachedtracker.CachedTracker{
+				Cache:   cache,
+				Tracker: tracker,
+				TTL:     ttls,
+			}
+
+			ctx, stale := usecases.WithStaleness(t.Context())
+
+			got, err := cachedTracker.GetMediaListIDs(ctx, "1")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStale, stale())
+
+			// waits for the background refresh
+			require.NoError(t, cachedTracker.Close())
+		})
+	}
+}
+
+func TestCachedTracker_GetUserID_notFound(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	cacheKeyUserID := "anilist:user:foo:id"
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	// the first lookup misses and caches the tracker outcome
+	cache.EXPECT().GetString(mock.Anything, cacheKeyUserID).
+		Return("", usecases.ErrStatusNotFound).Once()
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		Return("", usecases.ErrStatusNotFound).Once()
+	cache.EXPECT().SetString(mock.Anything, cacheKeyUserID, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, _ string, options ...usecases.CacheOption) error {
+			assert.Equal(t, time.Minute, usecases.NewCacheOptions(options...).TTL)
+
+			return nil
+		}).Once()
+	// the second one hits it without querying the tracker
+	cache.EXPECT().GetString(mock.Anything, cacheKeyUserID).
+		Return("!", nil).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: tracker,
+		TTL: cachedtracker.TTLs{
+			UserID:         time.Hour,
+			UserIDNotFound: time.Minute,
+		},
+	}
+
+	gotUserID, err := cachedTracker.GetUserID(t.Context(), username)
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	assert.Empty(t, gotUserID)
+
+	gotUserID, err = cachedTracker.GetUserID(t.Context(), username)
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	assert.Empty(t, gotUserID)
+}
+
+func TestCachedTracker_GetUserID_notFoundUncached(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+
+	cache := test.NewMockCache(t)
+	tracker := test.NewMockTracker(t)
+
+	// without a not found TTL every lookup queries the tracker
+	cache.EXPECT().GetString(mock.Anything, "anilist:user:foo:id").
+		Return("", usecases.ErrStatusNotFound).Twice()
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		Return("", usecases.ErrStatusNotFound).Twice()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: tracker,
+	}
+
+	for range 2 {
+		_, err := cachedTracker.GetUserID(t.Context(), username)
+		require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+	}
+}
+
+func TestCachedTracker_PutUserID(t *testing.T) {
+	t.Parallel()
+
+	cache := test.NewMockCache(t)
+
+	// the user ID replaces the cached absence of the user
+	cache.EXPECT().SetString(mock.Anything, "anilist:user:foo:id", "1", mock.Anything).
+		RunAndReturn(func(_ context.Context, _, _ string, options ...usecases.CacheOption) error {
+			assert.Equal(t, time.Hour, usecases.NewCacheOptions(options...).TTL)
+
+			return nil
+		}).Once()
+	cache.EXPECT().GetString(mock.Anything, "anilist:user:foo:id").
+		Return("1", nil).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: test.NewMockTracker(t),
+		TTL: cachedtracker.TTLs{
+			UserID:         time.Hour,
+			UserIDNotFound: time.Minute,
+		},
+	}
+
+	require.NoError(t, cachedTracker.PutUserID(t.Context(), "foo", "1"))
+
+	gotUserID, err := cachedTracker.GetUserID(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1", gotUserID)
+}
+
+func TestCachedTracker_PutUserID_error(t *testing.T) {
+	t.Parallel()
+
+	cache := test.NewMockCache(t)
+	cache.EXPECT().SetString(mock.Anything, "anilist:user:foo:id", "1", mock.Anything).
+		Return(usecases.ErrStatusUnavailable).Once()
+
+	cachedTracker := cachedtracker.CachedTracker{
+		Cache:   cache,
+		Tracker: test.NewMockTracker(t),
+	}
+
+	err := cachedTracker.PutUserID(t.Context(), "foo", "1")
+	require.ErrorIs(t, err, usecases.ErrStatusUnknown)
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+}
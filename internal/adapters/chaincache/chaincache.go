@@ -7,30 +7,62 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
+	telemetry "github.com/wwmoraes/gotell"
 
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
-var _ usecases.Cache = ChainCache(nil)
+const (
+	// WriteFirst sets values to the first cache only.
+	WriteFirst WritePolicy = iota
+	// WriteThrough sets values to all caches before returning.
+	WriteThrough
+	// WriteBehind sets values to the first cache before returning, and to the
+	// others in the background.
+	WriteBehind
+)
+
+var _ usecases.Cache = (*ChainCache)(nil)
+
+// ChainCache is a cache that chains multiple others, from the fastest to the
+// slowest. It resolves retrievals serially i.e. it stops at the first
+// underlying cache to return a nil error.
+//
+// Backfill sets values found on a cache to the earlier ones, which warms the
+// faster caches up. Backfilled entries keep the time left until they expire on
+// caches that report it i.e. [usecases.ExpiringCache] ones. Entries from other
+// caches get BackfillTTL instead, or are not backfilled if it is zero.
+//
+// Write tells which caches get the values set. It defaults to [WriteFirst].
+type ChainCache struct {
+	Caches      []usecases.Cache
+	pending     sync.WaitGroup
+	BackfillTTL time.Duration
+	Write       WritePolicy
+	Backfill    bool
+}
+
+// WritePolicy tells which caches of a [ChainCache] get the values set.
+type WritePolicy int
 
-// ChainCache is a cache that chains multiple others. It resolves all requests
-// serially i.e. it stops at the first underlying cache to return a nil error.
-type ChainCache []usecases.Cache
+// Close waits for pending background writes, then closes all underlying caches
+// concurrently. It returns either no error if they all close successfully or a
+// joined error from those caches that fail.
+func (chain *ChainCache) Close() error {
+	chain.pending.Wait()
 
-// Close closes all underlying caches concurrently, returning either no error
-// if they all close successfully or a joined error from those caches that fail.
-func (chain ChainCache) Close() error {
 	outErr := make(chan error)
 
 	// close each cache in a goroutine, sending its potential error to the channel
 	go func() {
 		var wg sync.WaitGroup
 
-		wg.Add(len(chain))
+		wg.Add(len(chain.Caches))
 
-		for _, cache := range chain {
+		for _, cache := range chain.Caches {
 			go func() {
 				outErr <- cache.Close()
 
@@ -45,7 +77,7 @@ func (chain ChainCache) Close() error {
 	}()
 
 	// collects all errors
-	errs := make([]error, 0, len(chain))
+	errs := make([]error, 0, len(chain.Caches))
 	for err := range outErr {
 		errs = append(errs, err)
 	}
@@ -58,9 +90,9 @@ func (chain ChainCache) Close() error {
 // It attempts all caches in order. Returns [usecases.ErrStatusNotFound] if no
 // cache has it. May short-circuit and return an [usecases.ErrStatusUnknown] if
 // a cache produces an unexpected error.
-func (chain ChainCache) GetString(ctx context.Context, key string) (string, error) {
-	for _, cache := range chain {
-		value, err := cache.GetString(ctx, key)
+func (chain *ChainCache) GetString(ctx context.Context, key string) (string, error) {
+	for index, cache := range chain.Caches {
+		value, ttl, err := chain.getString(ctx, cache, key)
 		if errors.Is(err, usecases.ErrStatusNotFound) {
 			continue
 		}
@@ -69,22 +101,96 @@ func (chain ChainCache) GetString(ctx context.Context, key string) (string, erro
 			return value, errors.Join(usecases.ErrStatusUnknown, err)
 		}
 
+		// earlier caches missed it, which only means those stay cold if setting
+		// it to them fails
+		if chain.Backfill && index > 0 && ttl >= 0 {
+			chain.setString(ctx, chain.Caches[:index], key, value, usecases.WithTTL(ttl))
+		}
+
 		return value, nil
 	}
 
 	return "", usecases.ErrStatusNotFound
 }
 
-// SetString sets they key-value pair to the first cache in the chain.
-func (chain ChainCache) SetString(
+// SetString sets the key-value pair to the caches the write policy tells.
+func (chain *ChainCache) SetString(
 	ctx context.Context,
 	key, value string,
 	options ...usecases.CacheOption,
 ) error {
-	if len(chain) == 0 {
+	if len(chain.Caches) == 0 {
 		return fmt.Errorf("%w: %s", usecases.ErrStatusFailedPrecondition, "no caches in the chain")
 	}
 
-	//nolint:wrapcheck // passthrough
-	return chain[0].SetString(ctx, key, value, options...)
+	switch chain.Write {
+	case WriteThrough:
+		errs := make([]error, 0, len(chain.Caches))
+
+		for _, cache := range chain.Caches {
+			errs = append(errs, cache.SetString(ctx, key, value, options...))
+		}
+
+		//nolint:wrapcheck // passthrough
+		return errors.Join(errs...)
+	case WriteBehind:
+		err := chain.Caches[0].SetString(ctx, key, value, options...)
+		if err != nil {
+			//nolint:wrapcheck // passthrough
+			return err
+		}
+
+		chain.pending.Add(1)
+
+		go func() {
+			defer chain.pending.Done()
+
+			chain.setString(context.WithoutCancel(ctx), chain.Caches[1:], key, value, options...)
+		}()
+
+		return nil
+	case WriteFirst:
+		fallthrough
+	default:
+		//nolint:wrapcheck // passthrough
+		return chain.Caches[0].SetString(ctx, key, value, options...)
+	}
+}
+
+// getString retrieves the value for key from cache, along with the TTL to
+// backfill it with. The TTL is negative if the entry must not be backfilled.
+func (chain *ChainCache) getString(
+	ctx context.Context,
+	cache usecases.Cache,
+	key string,
+) (string, time.Duration, error) {
+	expiring, ok := cache.(usecases.ExpiringCache)
+	if ok && chain.Backfill {
+		//nolint:wrapcheck // caches are internal
+		return expiring.GetStringTTL(ctx, key)
+	}
+
+	value, err := cache.GetString(ctx, key)
+	if chain.BackfillTTL <= 0 {
+		//nolint:wrapcheck // caches are internal
+		return value, -1, err
+	}
+
+	//nolint:wrapcheck // caches are internal
+	return value, chain.BackfillTTL, err
+}
+
+// setString sets the key-value pair to all caches, logging any failures.
+func (*ChainCache) setString(
+	ctx context.Context,
+	caches []usecases.Cache,
+	key, value string,
+	options ...usecases.CacheOption,
+) {
+	for _, cache := range caches {
+		err := cache.SetString(ctx, key, value, options...)
+		if err != nil {
+			telemetry.Logr(ctx).Error(err, "failed to set cache entry", "key", key)
+		}
+	}
 }
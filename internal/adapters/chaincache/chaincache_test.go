@@ -1,6 +1,7 @@
 package chaincache_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -25,35 +26,35 @@ func TestChainCache_Close(t *testing.T) {
 
 	tests := []struct {
 		assertError require.ErrorAssertionFunc
+		chain       *chaincache.ChainCache
 		name        string
-		chain       chaincache.ChainCache
 	}{
 		{
 			name:        "empty",
-			chain:       chaincache.ChainCache{},
+			chain:       &chaincache.ChainCache{},
 			assertError: require.NoError,
 		},
 		{
 			name: "single",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				cache,
-			},
+			}},
 			assertError: require.NoError,
 		},
 		{
 			name: "multi",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				cache,
 				cache,
-			},
+			}},
 			assertError: require.NoError,
 		},
 		{
 			name: "error",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				cache,
 				errorCache,
-			},
+			}},
 			assertError: require.Error,
 		},
 	}
@@ -87,60 +88,60 @@ func TestChainCache_GetString(t *testing.T) {
 		Return("", errors.New("qux")).Maybe()
 
 	tests := []struct {
+		wantError error
+		chain     *chaincache.ChainCache
 		name      string
 		want      string
-		wantError error
-		chain     chaincache.ChainCache
 	}{
 		{
 			name:      "no providers",
-			chain:     chaincache.ChainCache{},
+			chain:     &chaincache.ChainCache{},
 			want:      "",
 			wantError: usecases.ErrStatusNotFound,
 		},
 		{
 			name: "single empty",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				missCache,
-			},
+			}},
 			want:      "",
 			wantError: usecases.ErrStatusNotFound,
 		},
 		{
 			name: "multi empty",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				missCache,
 				missCache,
-			},
+			}},
 			want:      "",
 			wantError: usecases.ErrStatusNotFound,
 		},
 		{
 			name: "single match",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				hitCache,
 				skippedCache,
-			},
+			}},
 			want:      "bar",
 			wantError: nil,
 		},
 		{
 			name: "multi match second",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				missCache,
 				hitCache,
 				skippedCache,
-			},
+			}},
 			want:      "bar",
 			wantError: nil,
 		},
 		{
 			name: "cache error",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				missCache,
 				errorCache,
 				skippedCache,
-			},
+			}},
 			want:      "",
 			wantError: usecases.ErrStatusUnknown,
 		},
@@ -178,35 +179,35 @@ func TestChainCache_SetString(t *testing.T) {
 
 	tests := []struct {
 		assertError require.ErrorAssertionFunc
+		chain       *chaincache.ChainCache
 		name        string
-		chain       chaincache.ChainCache
 	}{
 		{
 			name:        "empty",
-			chain:       chaincache.ChainCache{},
+			chain:       &chaincache.ChainCache{},
 			assertError: require.Error,
 		},
 		{
 			name: "single",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				hitCache,
-			},
+			}},
 			assertError: require.NoError,
 		},
 		{
 			name: "multi",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				hitCache,
 				skippedCache,
-			},
+			}},
 			assertError: require.NoError,
 		},
 		{
 			name: "error",
-			chain: chaincache.ChainCache{
+			chain: &chaincache.ChainCache{Caches: []usecases.Cache{
 				errorCache,
 				skippedCache,
-			},
+			}},
 			assertError: require.Error,
 		},
 	}
@@ -225,3 +226,187 @@ func TestChainCache_SetString(t *testing.T) {
 		})
 	}
 }
+
+func TestChainCache_GetString_backfill(t *testing.T) {
+	t.Parallel()
+
+	key := "foo"
+	value := "bar"
+
+	tests := []struct {
+		hit         func(t *testing.T) usecases.Cache
+		name        string
+		wantTTL     time.Duration
+		backfillTTL time.Duration
+		wantSet     bool
+	}{
+		{
+			name: "remaining TTL",
+			hit: func(t *testing.T) usecases.Cache {
+				t.Helper()
+
+				cache := test.NewMockExpiringCache(t)
+				cache.EXPECT().GetStringTTL(mock.Anything, key).Return(value, time.Minute, nil)
+
+				return cache
+			},
+			wantTTL: time.Minute,
+			wantSet: true,
+		},
+		{
+			name: "no expiration",
+			hit: func(t *testing.T) usecases.Cache {
+				t.Helper()
+
+				cache := test.NewMockExpiringCache(t)
+				cache.EXPECT().GetStringTTL(mock.Anything, key).Return(value, 0, nil)
+
+				return cache
+			},
+			backfillTTL: time.Hour,
+			wantTTL:     0,
+			wantSet:     true,
+		},
+		{
+			name: "fallback TTL",
+			hit: func(t *testing.T) usecases.Cache {
+				t.Helper()
+
+				cache := test.NewMockCache(t)
+				cache.EXPECT().GetString(mock.Anything, key).Return(value, nil)
+
+				return cache
+			},
+			backfillTTL: time.Hour,
+			wantTTL:     time.Hour,
+			wantSet:     true,
+		},
+		{
+			name: "unknown TTL",
+			hit: func(t *testing.T) usecases.Cache {
+				t.Helper()
+
+				cache := test.NewMockCache(t)
+				cache.EXPECT().GetString(mock.Anything, key).Return(value, nil)
+
+				return cache
+			},
+			wantSet: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			missCache := test.NewMockCache(t)
+			missCache.EXPECT().GetString(mock.Anything, key).
+				Return("", usecases.ErrStatusNotFound)
+
+			if tt.wantSet {
+				missCache.EXPECT().SetString(mock.Anything, key, value, mock.Anything).
+					RunAndReturn(func(_ context.Context, _, _ string, options ...usecases.CacheOption) error {
+						assert.Equal(t, tt.wantTTL, usecases.NewCacheOptions(options...).TTL)
+
+						return nil
+					})
+			}
+
+			chain := &chaincache.ChainCache{
+				Caches:      []usecases.Cache{missCache, tt.hit(t)},
+				BackfillTTL: tt.backfillTTL,
+				Backfill:    true,
+			}
+
+			got, err := chain.GetString(t.Context(), key)
+			require.NoError(t, err)
+
+			assert.Equal(t, value, got)
+		})
+	}
+}
+
+func TestChainCache_SetString_policies(t *testing.T) {
+	t.Parallel()
+
+	key := "foo"
+	value := "bar"
+
+	tests := []struct {
+		name   string
+		policy chaincache.WritePolicy
+		want   int
+	}{
+		{
+			name:   "write first",
+			policy: chaincache.WriteFirst,
+			want:   1,
+		},
+		{
+			name:   "write through",
+			policy: chaincache.WriteThrough,
+			want:   3,
+		},
+		{
+			name:   "write behind",
+			policy: chaincache.WriteBehind,
+			want:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			caches := make([]usecases.Cache, 0, 3)
+
+			for index := range cap(caches) {
+				cache := test.NewMockCache(t)
+				cache.EXPECT().Close().Return(nil)
+
+				if index < tt.want {
+					cache.EXPECT().SetString(mock.Anything, key, value, mock.Anything).Return(nil)
+				}
+
+				caches = append(caches, cache)
+			}
+
+			chain := &chaincache.ChainCache{
+				Caches: caches,
+				Write:  tt.policy,
+			}
+
+			err := chain.SetString(t.Context(), key, value, usecases.WithTTL(time.Hour))
+			require.NoError(t, err)
+
+			// waits for background writes
+			require.NoError(t, chain.Close())
+		})
+	}
+}
+
+func TestChainCache_SetString_writeBehindError(t *testing.T) {
+	t.Parallel()
+
+	key := "foo"
+	value := "bar"
+
+	firstCache := test.NewMockCache(t)
+	slowCache := test.NewMockCache(t)
+
+	firstCache.EXPECT().SetString(mock.Anything, key, value).Return(nil)
+	firstCache.EXPECT().Close().Return(nil)
+	slowCache.EXPECT().SetString(mock.Anything, key, value).Return(errors.New("qux"))
+	slowCache.EXPECT().Close().Return(nil)
+
+	chain := &chaincache.ChainCache{
+		Caches: []usecases.Cache{firstCache, slowCache},
+		Write:  chaincache.WriteBehind,
+	}
+
+	// failures past the first cache happen in the background
+	err := chain.SetString(t.Context(), key, value)
+	require.NoError(t, err)
+
+	require.NoError(t, chain.Close())
+}
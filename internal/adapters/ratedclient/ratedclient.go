@@ -0,0 +1,86 @@
+// Package ratedclient provides a rate-limited [usecases.Doer] wrapper, which
+// allows consuming upstream resources with usage limits in a friendly way.
+package ratedclient
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+var _ usecases.Doer = (*RatedClient)(nil)
+
+// RatedClient is a rate-limited HTTP client. It suits upstreams that do not
+// report their limits through headers, as the local limiter is the only source
+// of truth.
+type RatedClient struct {
+	usecases.Doer
+
+	Limiter *rate.Limiter
+}
+
+// Do executes a HTTP request right away if its within the limits. Otherwise it
+// returns a 429 + Retry-After header with the seconds to wait for
+func (client *RatedClient) Do(req *http.Request) (*http.Response, error) {
+	span := telemetry.SpanFromContext(req.Context())
+
+	reservation := client.Limiter.Reserve()
+	if !reservation.OK() {
+		return nil, span.Assert(usecases.ErrStatusInternal)
+	}
+
+	if reservation.Delay() > 0 {
+		reservation.Cancel()
+
+		return NewRetryAfterResponse(req, reservation.Delay()), nil
+	}
+
+	resp, err := client.Doer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", usecases.ErrStatusUnknown, err)
+	}
+
+	return resp, span.Assert(nil)
+}
+
+// NewRetryAfterResponse returns a 429 to req that tells to retry after delay.
+func NewRetryAfterResponse(req *http.Request, delay time.Duration) *http.Response {
+	return newResponseFor(req, http.StatusTooManyRequests, nil, http.Header{
+		"Retry-After": []string{
+			strconv.FormatFloat(math.Ceil(delay.Seconds()), 'f', 0, 64),
+		},
+	})
+}
+
+func newResponseFor(
+	req *http.Request,
+	status int,
+	data []byte,
+	headers http.Header,
+) *http.Response {
+	span := telemetry.SpanFromContext(req.Context())
+
+	writer := httptest.NewRecorder()
+	maps.Copy(writer.Header(), headers)
+	writer.WriteHeader(status)
+
+	_, err := writer.Write(data)
+	if err != nil {
+		span.RecordError(err, trace.WithStackTrace(true))
+	}
+
+	res := writer.Result()
+	res.Request = req
+
+	return res
+}
@@ -0,0 +1,80 @@
+package ratedclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/ratedclient"
+	"github.com/wwmoraes/anilistarr/internal/test"
+)
+
+func TestRatedClient_200(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/foo",
+		http.NoBody,
+	)
+
+	recorder := httptest.NewRecorder()
+	recorder.WriteHeader(http.StatusOK)
+
+	res := recorder.Result()
+	defer res.Body.Close()
+
+	doer := test.NewMockDoer(t)
+
+	doer.EXPECT().Do(req).Return(res, nil).Once()
+
+	client := ratedclient.RatedClient{
+		Doer:    doer,
+		Limiter: rate.NewLimiter(rate.Every(time.Nanosecond), 1),
+	}
+
+	got, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer got.Body.Close()
+
+	assert.Equal(t, res, got)
+}
+
+func TestRatedClient_local_429(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/foo",
+		http.NoBody,
+	)
+
+	doer := test.NewMockDoer(t)
+
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	client := ratedclient.RatedClient{
+		Doer:    doer,
+		Limiter: limiter,
+	}
+
+	// consume limit before call
+	limiter.SetBurst(0)
+	limiter.SetBurstAt(time.Now().Add(time.Hour), 1)
+
+	got, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer got.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, got.StatusCode)
+	assert.Equal(t, "3600", got.Header.Get("Retry-After"))
+}
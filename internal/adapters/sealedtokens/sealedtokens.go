@@ -0,0 +1,138 @@
+// Package sealedtokens provides a [usecases.TokenStore] wrapper that encrypts
+// access tokens at rest.
+package sealedtokens
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// KeySize is the length in bytes of the keys [NewAEAD] takes.
+const KeySize = 32
+
+var _ usecases.TokenStore = (*SealedTokens)(nil)
+
+// SealedTokens is a token store that seals access tokens before storing them.
+//
+// It is an [usecases.TokenStore] drop-in replacement that wraps another store.
+// Access tokens are stored as the base64 of a random nonce followed by their
+// ciphertext, which AEAD produces. The user ID authenticates the token as well,
+// so entries swapped between users fail to open.
+//
+// Tokens that fail to open, e.g. after a key change, result in
+// [usecases.ErrStatusDataLoss]. Users then need to authorize again.
+type SealedTokens struct {
+	Store usecases.TokenStore
+	AEAD  cipher.AEAD
+}
+
+// NewAEAD creates an AES-256-GCM cipher with the key, which must be [KeySize]
+// bytes long.
+func NewAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: token key must be %d bytes long, got %d",
+			usecases.ErrStatusInvalidArgument, KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusInvalidArgument, err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusInternal, err)
+	}
+
+	return aead, nil
+}
+
+// GetToken retrieves and opens the token of an user by their ID.
+func (sealed *SealedTokens) GetToken(ctx context.Context, userID string) (*entities.Token, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	token, err := sealed.Store.GetToken(ctx, userID)
+	if err != nil {
+		//nolint:wrapcheck // passthrough
+		return nil, span.Assert(err)
+	}
+
+	token, err = sealed.open(token)
+
+	return token, span.Assert(err)
+}
+
+// GetTokenByName retrieves and opens the token of an user by their name.
+func (sealed *SealedTokens) GetTokenByName(ctx context.Context, name string) (*entities.Token, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	token, err := sealed.Store.GetTokenByName(ctx, name)
+	if err != nil {
+		//nolint:wrapcheck // passthrough
+		return nil, span.Assert(err)
+	}
+
+	token, err = sealed.open(token)
+
+	return token, span.Assert(err)
+}
+
+// PutToken seals and stores the token of an user. The token itself is left
+// untouched.
+func (sealed *SealedTokens) PutToken(ctx context.Context, token *entities.Token) error {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if !token.Valid() {
+		return span.Assert(usecases.ErrStatusInvalidArgument)
+	}
+
+	nonce := make([]byte, sealed.AEAD.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return span.Assert(errors.Join(usecases.ErrStatusInternal, err))
+	}
+
+	ciphertext := sealed.AEAD.Seal(nonce, nonce, []byte(token.AccessToken), []byte(token.UserID))
+
+	sealedToken := *token
+	sealedToken.AccessToken = base64.RawStdEncoding.EncodeToString(ciphertext)
+
+	//nolint:wrapcheck // passthrough
+	return span.Assert(sealed.Store.PutToken(ctx, &sealedToken))
+}
+
+// open decrypts the access token of a sealed token in place.
+func (sealed *SealedTokens) open(token *entities.Token) (*entities.Token, error) {
+	data, err := base64.RawStdEncoding.DecodeString(token.AccessToken)
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusDataLoss, err)
+	}
+
+	nonceSize := sealed.AEAD.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("%w: sealed token is too short", usecases.ErrStatusDataLoss)
+	}
+
+	plaintext, err := sealed.AEAD.Open(nil, data[:nonceSize], data[nonceSize:], []byte(token.UserID))
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusDataLoss, err)
+	}
+
+	token.AccessToken = string(plaintext)
+
+	return token, nil
+}
@@ -0,0 +1,189 @@
+package sealedtokens_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/sealedtokens"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+func newAEAD(t *testing.T, fill byte) cipher.AEAD {
+	t.Helper()
+
+	aead, err := sealedtokens.NewAEAD(bytes.Repeat([]byte{fill}, sealedtokens.KeySize))
+	require.NoError(t, err)
+
+	return aead
+}
+
+func TestNewAEAD(t *testing.T) {
+	t.Parallel()
+
+	_, err := sealedtokens.NewAEAD([]byte("short"))
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
+
+func TestSealedTokens(t *testing.T) {
+	t.Parallel()
+
+	token := entities.Token{
+		UserID:      "1",
+		UserName:    "foo",
+		AccessToken: "bar",
+	}
+
+	var stored *entities.Token
+
+	store := test.NewMockTokenStore(t)
+	store.EXPECT().PutToken(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, token *entities.Token) error {
+			stored = token
+
+			return nil
+		}).Once()
+	store.EXPECT().GetToken(mock.Anything, "1").
+		RunAndReturn(func(context.Context, string) (*entities.Token, error) {
+			clone := *stored
+
+			return &clone, nil
+		}).Once()
+	store.EXPECT().GetTokenByName(mock.Anything, "foo").
+		RunAndReturn(func(context.Context, string) (*entities.Token, error) {
+			clone := *stored
+
+			return &clone, nil
+		}).Once()
+
+	sealed := sealedtokens.SealedTokens{
+		Store: store,
+		AEAD:  newAEAD(t, 1),
+	}
+
+	require.NoError(t, sealed.PutToken(t.Context(), &token))
+
+	assert.Equal(t, "bar", token.AccessToken)
+	assert.Equal(t, "1", stored.UserID)
+	assert.NotContains(t, stored.AccessToken, "bar")
+
+	got, err := sealed.GetToken(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, &token, got)
+
+	got, err = sealed.GetTokenByName(t.Context(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, &token, got)
+}
+
+func TestSealedTokens_GetToken_data_loss(t *testing.T) {
+	t.Parallel()
+
+	var stored *entities.Token
+
+	store := test.NewMockTokenStore(t)
+	store.EXPECT().PutToken(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, token *entities.Token) error {
+			stored = token
+
+			return nil
+		}).Once()
+
+	sealed := sealedtokens.SealedTokens{
+		Store: store,
+		AEAD:  newAEAD(t, 1),
+	}
+
+	require.NoError(t, sealed.PutToken(t.Context(), &entities.Token{
+		UserID:      "1",
+		UserName:    "foo",
+		AccessToken: "bar",
+	}))
+
+	tests := []struct {
+		token *entities.Token
+		aead  cipher.AEAD
+		name  string
+	}{
+		{
+			name:  "other key",
+			token: stored,
+			aead:  newAEAD(t, 2),
+		},
+		{
+			name: "other user",
+			token: &entities.Token{
+				UserID:      "2",
+				UserName:    "baz",
+				AccessToken: stored.AccessToken,
+			},
+			aead: newAEAD(t, 1),
+		},
+		{
+			name: "plaintext",
+			token: &entities.Token{
+				UserID:      "1",
+				UserName:    "foo",
+				AccessToken: "bar-baz",
+			},
+			aead: newAEAD(t, 1),
+		},
+		{
+			name: "too short",
+			token: &entities.Token{
+				UserID:      "1",
+				UserName:    "foo",
+				AccessToken: "YmFy",
+			},
+			aead: newAEAD(t, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clone := *tt.token
+
+			store := test.NewMockTokenStore(t)
+			store.EXPECT().GetToken(mock.Anything, clone.UserID).Return(&clone, nil).Once()
+
+			sealed := sealedtokens.SealedTokens{
+				Store: store,
+				AEAD:  tt.aead,
+			}
+
+			_, err := sealed.GetToken(t.Context(), clone.UserID)
+			require.ErrorIs(t, err, usecases.ErrStatusDataLoss)
+		})
+	}
+}
+
+func TestSealedTokens_passthrough(t *testing.T) {
+	t.Parallel()
+
+	store := test.NewMockTokenStore(t)
+	store.EXPECT().GetToken(mock.Anything, "1").Return(nil, usecases.ErrStatusNotFound).Once()
+	store.EXPECT().GetTokenByName(mock.Anything, "foo").Return(nil, usecases.ErrStatusNotFound).Once()
+
+	sealed := sealedtokens.SealedTokens{
+		Store: store,
+		AEAD:  newAEAD(t, 1),
+	}
+
+	_, err := sealed.GetToken(t.Context(), "1")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	_, err = sealed.GetTokenByName(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	err = sealed.PutToken(t.Context(), &entities.Token{UserID: "1"})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
@@ -0,0 +1,207 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+var (
+	_ usecases.Source           = (*Priority)(nil)
+	_ usecases.ConflictReporter = (*Priority)(nil)
+)
+
+// Priority merges the metadata of multiple sources in order. Earlier sources
+// take precedence, so later ones only fill the source IDs missing from those.
+// Entries that map a source ID to another target are dropped, logged and kept
+// as conflicts until the next fetch.
+//
+// It errors only if all sources fail, which allows the remaining ones to still
+// provide mappings. If only some fail, it returns the merged metadata along
+// with [usecases.ErrPartialFetch], as the mappings of those are missing.
+type Priority struct {
+	Sources   []usecases.Source
+	conflicts []entities.MappingConflict
+	mutex     sync.RWMutex
+}
+
+// merger accumulates metadata from sources in priority order.
+type merger struct {
+	// targets contains the target in use for each source ID
+	targets map[entities.SourceID]entities.MappingTarget
+	// indexes contains the conflict position of each source ID
+	indexes   map[entities.SourceID]int
+	entries   []usecases.Metadata
+	conflicts []entities.MappingConflict
+}
+
+// String returns the URIs of all sources.
+func (source *Priority) String() string {
+	providers := make([]string, 0, len(source.Sources))
+	for _, provider := range source.Sources {
+		providers = append(providers, provider.String())
+	}
+
+	return strings.Join(providers, ",")
+}
+
+// Fetch retrieves and merges the metadata from all sources. Sources that fail
+// are skipped, which makes it a partial fetch.
+func (source *Priority) Fetch(
+	ctx context.Context,
+	client usecases.Getter,
+) ([]usecases.Metadata, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	log := telemetry.Logr(ctx)
+
+	merged := merger{
+		targets:   map[entities.SourceID]entities.MappingTarget{},
+		indexes:   map[entities.SourceID]int{},
+		entries:   []usecases.Metadata{},
+		conflicts: []entities.MappingConflict{},
+	}
+
+	errs := make([]error, 0, len(source.Sources))
+	failed := 0
+
+	for _, provider := range source.Sources {
+		data, err := provider.Fetch(ctx, client)
+		if err != nil {
+			logSkipped(log, provider, err)
+
+			errs = append(errs, err)
+		}
+
+		// partial sources still provide the mappings they fetched
+		if err != nil && !errors.Is(err, usecases.ErrPartialFetch) {
+			failed++
+
+			continue
+		}
+
+		merged.add(provider.String(), data)
+	}
+
+	if failed > 0 && failed == len(source.Sources) {
+		return nil, span.Assert(errors.Join(errs...))
+	}
+
+	for _, conflict := range merged.conflicts {
+		log.V(1).Info("mapping conflict", "sourceID", conflict.SourceID, "targets", conflict.Targets)
+	}
+
+	log.Info("merged sources", "entries", len(merged.entries), "conflicts", len(merged.conflicts))
+
+	span.SetAttributes(
+		attribute.Int("entries", len(merged.entries)),
+		attribute.Int("conflicts", len(merged.conflicts)),
+	)
+
+	source.mutex.Lock()
+	source.conflicts = merged.conflicts
+	source.mutex.Unlock()
+
+	if len(errs) > 0 {
+		return merged.entries, span.Assert(fmt.Errorf("%w: %w", usecases.ErrPartialFetch, errors.Join(errs...)))
+	}
+
+	return merged.entries, span.Assert(nil)
+}
+
+// Conflicts returns the conflicts found during the last fetch, followed by
+// those of the sources that report any, such as nested ones.
+func (source *Priority) Conflicts() []entities.MappingConflict {
+	source.mutex.RLock()
+	conflicts := slices.Clone(source.conflicts)
+	source.mutex.RUnlock()
+
+	for _, provider := range source.Sources {
+		reporter, ok := provider.(usecases.ConflictReporter)
+		if !ok {
+			continue
+		}
+
+		conflicts = append(conflicts, reporter.Conflicts()...)
+	}
+
+	return conflicts
+}
+
+// add merges the valid entries from a provider, which has a lower priority than
+// the ones added before.
+func (merged *merger) add(provider string, data []usecases.Metadata) {
+	for _, entry := range data {
+		if !entry.Valid() {
+			continue
+		}
+
+		target := entities.MappingTarget{
+			Provider: provider,
+			TargetID: entry.GetTargetID(),
+		}
+
+		current, ok := merged.targets[entry.GetSourceID()]
+		if !ok {
+			merged.targets[entry.GetSourceID()] = target
+			merged.entries = append(merged.entries, entry)
+
+			continue
+		}
+
+		if current.TargetID != target.TargetID {
+			merged.conflict(entry.GetSourceID(), current, target)
+		}
+	}
+}
+
+// conflict records a target that conflicts with the current one in use.
+func (merged *merger) conflict(
+	sourceID entities.SourceID,
+	current, target entities.MappingTarget,
+) {
+	index, ok := merged.indexes[sourceID]
+	if !ok {
+		index = len(merged.conflicts)
+		merged.indexes[sourceID] = index
+
+		merged.conflicts = append(merged.conflicts, entities.MappingConflict{
+			SourceID: sourceID,
+			Targets:  []entities.MappingTarget{current},
+		})
+	}
+
+	merged.conflicts[index].Targets = append(merged.conflicts[index].Targets, target)
+}
+
+// logSkipped logs why a source was skipped, or only partially merged.
+// Unmodified sources are expected during refreshes, so those are logged as
+// debug only.
+func logSkipped(log logr.Logger, provider usecases.Source, err error) {
+	log = log.WithValues("provider", provider.String())
+
+	if errors.Is(err, usecases.ErrNotModified) {
+		log.V(1).Info("skipping unmodified source")
+
+		return
+	}
+
+	if errors.Is(err, usecases.ErrPartialFetch) {
+		log.Error(err, "merging partial source")
+
+		return
+	}
+
+	log.Error(err, "skipping failed source")
+}
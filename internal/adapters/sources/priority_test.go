@@ -0,0 +1,185 @@
+package sources_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/sources"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+func TestPriority_Fetch(t *testing.T) {
+	t.Parallel()
+
+	getter := test.NewMockGetter(t)
+	primary := test.NewMockSource(t)
+	secondary := test.NewMockSource(t)
+	broken := test.NewMockSource(t)
+
+	primary.EXPECT().String().Return("primary")
+	secondary.EXPECT().String().Return("secondary")
+	broken.EXPECT().String().Return("broken")
+
+	primary.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "91"},
+		test.Metadata{SourceID: "2", TargetID: "92"},
+		test.Metadata{SourceID: "3", TargetID: "0"},
+	}, nil).Once()
+	broken.EXPECT().Fetch(mock.Anything, getter).
+		Return(nil, usecases.ErrStatusUnavailable).Once()
+	secondary.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "91"},
+		test.Metadata{SourceID: "2", TargetID: "82"},
+		test.Metadata{SourceID: "3", TargetID: "93"},
+	}, nil).Once()
+
+	source := sources.Priority{
+		Sources: []usecases.Source{primary, broken, secondary},
+	}
+
+	assert.Equal(t, "primary,broken,secondary", source.String())
+	assert.Empty(t, source.Conflicts())
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.ErrorIs(t, err, usecases.ErrPartialFetch)
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+	assert.Equal(t, []usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "91"},
+		test.Metadata{SourceID: "2", TargetID: "92"},
+		test.Metadata{SourceID: "3", TargetID: "93"},
+	}, got)
+	assert.Equal(t, []entities.MappingConflict{
+		{
+			SourceID: "2",
+			Targets: []entities.MappingTarget{
+				{Provider: "primary", TargetID: "92"},
+				{Provider: "secondary", TargetID: "82"},
+			},
+		},
+	}, source.Conflicts())
+}
+
+func TestPriority_Fetch_error(t *testing.T) {
+	t.Parallel()
+
+	errFoo := errors.New("foo")
+	errBar := errors.New("bar")
+
+	getter := test.NewMockGetter(t)
+	primary := test.NewMockSource(t)
+	secondary := test.NewMockSource(t)
+
+	primary.EXPECT().String().Return("primary")
+	secondary.EXPECT().String().Return("secondary")
+
+	primary.EXPECT().Fetch(mock.Anything, getter).Return(nil, errFoo).Once()
+	secondary.EXPECT().Fetch(mock.Anything, getter).Return(nil, errBar).Once()
+
+	source := sources.Priority{
+		Sources: []usecases.Source{primary, secondary},
+	}
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.ErrorIs(t, err, errFoo)
+	require.ErrorIs(t, err, errBar)
+
+	assert.Nil(t, got)
+}
+
+func TestPriority_Fetch_partial(t *testing.T) {
+	t.Parallel()
+
+	getter := test.NewMockGetter(t)
+	primary := test.NewMockSource(t)
+	secondary := test.NewMockSource(t)
+
+	primary.EXPECT().String().Return("primary")
+	secondary.EXPECT().String().Return("secondary")
+
+	primary.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "91"},
+	}, usecases.ErrPartialFetch).Once()
+	secondary.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "2", TargetID: "92"},
+	}, nil).Once()
+
+	source := sources.Priority{
+		Sources: []usecases.Source{primary, secondary},
+	}
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.ErrorIs(t, err, usecases.ErrPartialFetch)
+
+	assert.Equal(t, []usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "91"},
+		test.Metadata{SourceID: "2", TargetID: "92"},
+	}, got)
+}
+
+func TestPriority_Conflicts_nested(t *testing.T) {
+	t.Parallel()
+
+	getter := test.NewMockGetter(t)
+	primary := test.NewMockSource(t)
+	secondary := test.NewMockSource(t)
+	// nested sources report the conflicts of their own merges
+	nested := struct {
+		*test.MockSource
+		*test.MockConflictReporter
+	}{
+		MockSource:           test.NewMockSource(t),
+		MockConflictReporter: test.NewMockConflictReporter(t),
+	}
+
+	primary.EXPECT().String().Return("primary")
+	secondary.EXPECT().String().Return("secondary")
+	nested.MockSource.EXPECT().String().Return("nested")
+
+	primary.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "91"},
+	}, nil).Once()
+	nested.MockSource.EXPECT().Fetch(mock.Anything, getter).Return(nil, nil).Once()
+	secondary.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "1", TargetID: "81"},
+	}, nil).Once()
+	nested.MockConflictReporter.EXPECT().Conflicts().Return([]entities.MappingConflict{
+		{
+			SourceID: "2",
+			Targets: []entities.MappingTarget{
+				{Provider: "fribb", TargetID: "92"},
+				{Provider: "manami", TargetID: "82"},
+			},
+		},
+	}).Once()
+
+	source := sources.Priority{
+		Sources: []usecases.Source{primary, nested, secondary},
+	}
+
+	_, err := source.Fetch(t.Context(), getter)
+	require.NoError(t, err)
+
+	assert.Equal(t, []entities.MappingConflict{
+		{
+			SourceID: "1",
+			Targets: []entities.MappingTarget{
+				{Provider: "primary", TargetID: "91"},
+				{Provider: "secondary", TargetID: "81"},
+			},
+		},
+		{
+			SourceID: "2",
+			Targets: []entities.MappingTarget{
+				{Provider: "fribb", TargetID: "92"},
+				{Provider: "manami", TargetID: "82"},
+			},
+		},
+	}, source.Conflicts())
+}
@@ -6,6 +6,7 @@ package api
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -15,33 +16,139 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	AdminTokenScopes = "AdminToken.Scopes"
+)
+
+// Defines values for Tracker.
+const (
+	Anilist Tracker = "anilist"
+	Kitsu   Tracker = "kitsu"
+	Mal     Tracker = "mal"
 )
 
 // CustomList defines model for CustomList.
 type CustomList = []struct {
 	TvdbID *float32 `json:"TvdbID,omitempty"`
+
+	// Seasons seasons to monitor; all of them if absent
+	Seasons *[]float32 `json:"seasons,omitempty"`
+}
+
+// MappingConflicts defines model for MappingConflicts.
+type MappingConflicts = []struct {
+	SourceID *string `json:"sourceID,omitempty"`
+	Targets  *[]struct {
+		Provider *string `json:"provider,omitempty"`
+		TargetID *string `json:"targetID,omitempty"`
+	} `json:"targets,omitempty"`
+}
+
+// MovieList defines model for MovieList.
+type MovieList = []struct {
+	ImdbId *string  `json:"imdb_id,omitempty"`
+	TmdbId *float32 `json:"tmdbId,omitempty"`
+}
+
+// Tracker defines model for Tracker.
+type Tracker string
+
+// Status defines model for Status.
+type Status = []string
+
+// GetMappingConflictsParams defines parameters for GetMappingConflicts.
+type GetMappingConflictsParams struct {
+	// Tracker upstream tracker to fetch the user data from
+	Tracker *Tracker `form:"tracker,omitempty" json:"tracker,omitempty"`
+}
+
+// AuthorizeAnilistCallbackParams defines parameters for AuthorizeAnilistCallback.
+type AuthorizeAnilistCallbackParams struct {
+	Code  string `form:"code" json:"code"`
+	State string `form:"state" json:"state"`
+}
+
+// GetUserIDParams defines parameters for GetUserID.
+type GetUserIDParams struct {
+	// Tracker upstream tracker to fetch the user data from
+	Tracker *Tracker `form:"tracker,omitempty" json:"tracker,omitempty"`
+}
+
+// GetUserMediaParams defines parameters for GetUserMedia.
+type GetUserMediaParams struct {
+	// Tracker upstream tracker to fetch the user data from
+	Tracker *Tracker `form:"tracker,omitempty" json:"tracker,omitempty"`
+
+	// Status comma-separated list statuses to include, using the tracker values (e.g.
+	// COMPLETED,PAUSED on Anilist). Defaults to the current and planning ones
+	Status *Status `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetUserMoviesParams defines parameters for GetUserMovies.
+type GetUserMoviesParams struct {
+	// Tracker upstream tracker to fetch the user data from
+	Tracker *Tracker `form:"tracker,omitempty" json:"tracker,omitempty"`
+
+	// Status comma-separated list statuses to include, using the tracker values (e.g.
+	// COMPLETED,PAUSED on Anilist). Defaults to the current and planning ones
+	Status *Status `form:"status,omitempty" json:"status,omitempty"`
 }
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// (GET /admin/conflicts)
+	GetMappingConflicts(w http.ResponseWriter, r *http.Request, params GetMappingConflictsParams)
+
+	// (GET /auth/anilist)
+	AuthorizeAnilist(w http.ResponseWriter, r *http.Request)
+
+	// (GET /auth/anilist/callback)
+	AuthorizeAnilistCallback(w http.ResponseWriter, r *http.Request, params AuthorizeAnilistCallbackParams)
+
 	// (GET /user/{name}/id)
-	GetUserID(w http.ResponseWriter, r *http.Request, name string)
+	GetUserID(w http.ResponseWriter, r *http.Request, name string, params GetUserIDParams)
 
 	// (GET /user/{name}/media)
-	GetUserMedia(w http.ResponseWriter, r *http.Request, name string)
+	GetUserMedia(w http.ResponseWriter, r *http.Request, name string, params GetUserMediaParams)
+
+	// (GET /user/{name}/movies)
+	GetUserMovies(w http.ResponseWriter, r *http.Request, name string, params GetUserMoviesParams)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// (GET /admin/conflicts)
+func (_ Unimplemented) GetMappingConflicts(w http.ResponseWriter, r *http.Request, params GetMappingConflictsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /auth/anilist)
+func (_ Unimplemented) AuthorizeAnilist(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /auth/anilist/callback)
+func (_ Unimplemented) AuthorizeAnilistCallback(w http.ResponseWriter, r *http.Request, params AuthorizeAnilistCallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /user/{name}/id)
-func (_ Unimplemented) GetUserID(w http.ResponseWriter, r *http.Request, name string) {
+func (_ Unimplemented) GetUserID(w http.ResponseWriter, r *http.Request, name string, params GetUserIDParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
 // (GET /user/{name}/media)
-func (_ Unimplemented) GetUserMedia(w http.ResponseWriter, r *http.Request, name string) {
+func (_ Unimplemented) GetUserMedia(w http.ResponseWriter, r *http.Request, name string, params GetUserMediaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /user/{name}/movies)
+func (_ Unimplemented) GetUserMovies(w http.ResponseWriter, r *http.Request, name string, params GetUserMoviesParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -54,15 +161,119 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
+// GetMappingConflicts operation middleware
+func (siw *ServerInterfaceWrapper) GetMappingConflicts(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMappingConflictsParams
+
+	// ------------- Optional query parameter "tracker" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tracker", r.URL.Query(), &params.Tracker)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tracker", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMappingConflicts(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AuthorizeAnilist operation middleware
+func (siw *ServerInterfaceWrapper) AuthorizeAnilist(w http.ResponseWriter, r *http.Request) {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AuthorizeAnilist(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AuthorizeAnilistCallback operation middleware
+func (siw *ServerInterfaceWrapper) AuthorizeAnilistCallback(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AuthorizeAnilistCallbackParams
+
+	// ------------- Required query parameter "code" -------------
+
+	if paramValue := r.URL.Query().Get("code"); paramValue != "" {
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "code"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "code", r.URL.Query(), &params.Code)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "code", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "state" -------------
+
+	if paramValue := r.URL.Query().Get("state"); paramValue != "" {
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "state"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "state", r.URL.Query(), &params.State)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AuthorizeAnilistCallback(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // GetUserID operation middleware
 func (siw *ServerInterfaceWrapper) GetUserID(w http.ResponseWriter, r *http.Request) {
+	var err error
+
 	// ------------- Path parameter "name" -------------
 	var name string
 
 	name = chi.URLParam(r, "name")
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserIDParams
+
+	// ------------- Optional query parameter "tracker" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tracker", r.URL.Query(), &params.Tracker)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tracker", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUserID(w, r, name)
+		siw.Handler.GetUserID(w, r, name, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -74,13 +285,73 @@ func (siw *ServerInterfaceWrapper) GetUserID(w http.ResponseWriter, r *http.Requ
 
 // GetUserMedia operation middleware
 func (siw *ServerInterfaceWrapper) GetUserMedia(w http.ResponseWriter, r *http.Request) {
+	var err error
+
 	// ------------- Path parameter "name" -------------
 	var name string
 
 	name = chi.URLParam(r, "name")
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserMediaParams
+
+	// ------------- Optional query parameter "tracker" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tracker", r.URL.Query(), &params.Tracker)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tracker", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUserMedia(w, r, name)
+		siw.Handler.GetUserMedia(w, r, name, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUserMovies operation middleware
+func (siw *ServerInterfaceWrapper) GetUserMovies(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	name = chi.URLParam(r, "name")
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserMoviesParams
+
+	// ------------- Optional query parameter "tracker" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tracker", r.URL.Query(), &params.Tracker)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tracker", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUserMovies(w, r, name, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -203,30 +474,61 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/conflicts", wrapper.GetMappingConflicts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/anilist", wrapper.AuthorizeAnilist)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/anilist/callback", wrapper.AuthorizeAnilistCallback)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/user/{name}/id", wrapper.GetUserID)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/user/{name}/media", wrapper.GetUserMedia)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/user/{name}/movies", wrapper.GetUserMovies)
+	})
 
 	return r
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
-	"H4sIAAAAAAAC/+RVS4/jRBD+K62GE/K4s7tw8Qk0WVCk3b2wSEjsHCp2Ja7B/aC6nMxo5P+OupyQCWEI",
-	"HJCQ9hS73PX4Hl15sm30KQYMkm3zZHuEDlkff775LtBAWW5+ysg3q64EO8wtUxKKwTb2cMCMGdlQh0Fo",
-	"Q8i2srnt0UPJkMeEtrFZmMLWTlP158IfwOOV0gE8uh5CN1wpPh0/KoLbMUv07yhLeSNBr+HEMSELob59",
-	"3HXr1fJZsTD6NbJOeojE9T22Yk8BYIbHuR0+CHKAYRnbfIniewqdiaMYHxkNrMuj9GgSRy1Z2ZEH29he",
-	"JDXObUn6cV230bv93kcGzA5mIoC5DEBhE0ubNgaBVnGhByo1Tge/BRbkWHe4KznnI93GsEOWbCCYM5L3",
-	"IG1PYWs0JNGAaZXAObCJ7EHMvqe2N18B86cAKWWTx5QiS20rO1CLIauYQUW171cfzzDmxjmGfT0DLW0L",
-	"EAzyEmbnIQuye7e6ffvhx7eqAcmAJ4cUYiq7Q84zvkX9ql6UczFhgES2sW80VNkE0qtIrnR2T2XIyZEa",
-	"e4tKZnEGFKaK3e0PKMWiq6UmM3gUvRy/zAJg0BzBB3FpAAoqxwP4pAMe4aiVykcdwFZHcvSnsoy/jcTY",
-	"2UZ4xOmuRHKKIc/+fL1YHAX/+36vXr/5erqQW6XdxDF0trp6u79k3NjGfuFOS8EdctxlwstX+V/U0RS9",
-	"Sd/8U6B2AzRgVyy6xYN5V8vG1HX9KdhLCpCkRzZgGATNQJ7ERDZRo5TziGZP0uvFHFMWRvBGGNpfkU0P",
-	"KWHArsw4VefO8dgRXDPPez30f/IPpDRQq4O6+xy162ml/pV2h53qni3U6ZJnpeOPZaF0bmmHQRX6HNz3",
-	"n3qv/LUh747+Od+pp4VZb4bHefHfTb8HAAD//xOdQxbdBwAA",
+	"H4sIAAAAAAAC/+xYW2/juhH+KwRboBcoVvZyXtyXBkl6YCCbLjZeoMAmKMbSyOKJRGqHI2fThf97MaTk",
+	"SyzHztluuwXOU2KKnOv3zQz5VWeubpxFy16Pv+oSIUcK//7j5Myayng++eiRTia5LOboMzING2f1WHcb",
+	"VOuRlMnRsikMkk60z0qsQU7wY4N6rD2TsXO9XCZPBV9DjQdEW6gxLcHm1RHCbxiqAYEeWbFTTC2qhxKt",
+	"4hJVUJG5Gr0qyNUKVAZZiQot06NqwLMy7NV0epWoh9JkpSqhadB6+VWhMqwIC0JfoleOlCmCWCbI7iUk",
+	"XrUWFmAqmFU4ZPnMuQrB6qXY3gBBjdzF/4aBW7/rSObqGk48ym7GPPrgw2b04qKxWdXmmKjWGzvfMmgB",
+	"VYte/RFH89GtPf/7u/dXl9PLi+T92cebywvlrOri/qeRusAC2oqDSJGRtURoWYHNVVOBtSLcWfQ60fgF",
+	"6kairs8/fvhweT1N3l+dXV9Prn8OH5vK5ajHBVQeE23Ei88t0qNOtA3Z19H+rQgZxtoPJDnpF4AIHuW3",
+	"58egu3BUy+9p9HY3dG3jmRDqVTzYqQI5K4ODAWo5MAQs6GFDu5Nblv6esNBj/bt0zaU0fvVpb0tIcLco",
+	"Z85bz66+Mp63fG3INUhsMPyaLvLZ5GIjBratZyIs0R7BO+uHcB4+iGu1s4Yd/UVBVSkXoFkLRGHm0bJO",
+	"1mp3xG+HeL3gZr9gxkNJeAdNY+z83NmiMlmsJnvc8q6lDCcXw8kFmuOzxxtyC5MjPXN8UPZhL47y0y0M",
+	"HsibqfPZP00+bGCdzyb5QNCPUb6F7EBP+RopK0yzba3HnzZW7g37Vie6hkrfJQMR8Zi1ZPjxRqAZrT/L",
+	"a2On7h6t/AqYDaUKgQLwOyElcxNhjV8YyUJ14bIBQP7N2Fy5llXtCBXM5F+hW0MuuJnolqpO3jhN54bL",
+	"djbKXJ0+PNSOAH3a+QMUsGls4URN5ixDFvKANZhqHQog+isQI7lRjgs5s23SubMLJPYKVgUvsv8BOCul",
+	"roUldtIQAlHjghQY4K4T/BmIbi00jVe+bRpHPNKJrkyG1of+01WMd5Pplo9+nKYED6PoqKgVR9DyPp/T",
+	"GjwjpVeT88vrm8uAC8Oh4p2tNulEL5B89O909Gp0KvtcgxYao8f6TViSHsNlSFIKkuY02+TrHHk3f6LA",
+	"h4xF3qrJhfwEVnWkfLfuVW48zAlR+gj4ruIYUhV4vrVdoxypaWS4AkJlrGrIOEGgcpQjJaG/iLbCkGdp",
+	"MMp42dd6HN3aD/i5NYTRoOCCYoFqoh4Ml67ts2NYmWjRrMJch1AQiEtCPv0z8k7B2m7An4br+nrLurTf",
+	"JZrQN876SKDXp6c9PtGGiELTVCYL6tNfvFsT62D/2LEyMG47Q30eVrnsQr9qc12CBBFvd2xj/MJpU4EJ",
+	"Vq07ubELqEyugOZtLXt3FYsOws8tehlENqYe67gnBeZR7asj1bYWWi7RssQL8z1aNzIv+mrjw6zjSHVm",
+	"R6Vvj1Qq9hautUPqoiq0eeOM7WDb40pgziUS/kF83rRK9P/0AqeN/Cdhxny8kUhRJsYR9rE8Mgm5Q79x",
+	"ci1yq+oHmG/W+093yzvZkEoS+hq0tzQQ5oYwizNiX0gLR6GYhsVMSGG5myENCdsXwKH6FzJBg81vbSwx",
+	"M5QUEkLeD9uCA7VWMoPsfjWNQlXJ7x1mn7VcOjL/wrNVE9wi55vT1/sd6aX3vvTmNzBHnWzejq5cpPP+",
+	"m8vmWfXxw9WBi8sN8sm5c/dm6O7CErJNt1VWYnbvFczBWM/Pyl5+CxR7b6ALa3C6p7iAysxbGsTl8Sd3",
+	"AJeusrsPefglK8HOuz6QuXydsyd4kbYQMAlWQZah913ZcMWt7cf+2HU8O0KvDB8E1fkafU96xtCdQcwL",
+	"MAytK9djuYQ+i4avey9JLxN0uDPtg0GfNcyfTEjdfDKQ8PB9fe7bus34CW4iBWrjaxnRBrSjkUIc55Sw",
+	"d1UBwwnl4rcAlTBQrNrEj8wNse718Z2zf2oYq9FotD9IvfouJpEPAf2qAFOtSSkpTb8K/JapyTfYuDNN",
+	"yVPO5GKXD0fYvQUp+RiG1DXsw5+nqF8mw3PTr5vP9pj26vWbt3twHqeF5OCD2ZCN3Zl098D+17EXyAlH",
+	"lstvot83DXs/Ha02ok267hy7AjO5COC9tfvhC4qE4JWpTcCwC6vG+xZjuQ9l/elbT3y5G8Z2jbmBQ/B+",
+	"Fzb9kAg/vLV7Tfyud5WNN62hW4qEb3WPDjmamwWGix39yEzaelR+/nTc9p/n3kZz26WgE0bEB9TvRsf/",
+	"Khnlhc0fZGPc9Rsd9z8drF4qh9goH49l4/83+n/ldBdhuLqBbwh96RW8O9pJfNlQ9z+iZHgjoEVPqu3X",
+	"y/XT5KioHuMT693y3wMAchif90scAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -1,33 +1,86 @@
 package api
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/goccy/go-json"
 	telemetry "github.com/wwmoraes/gotell"
 
+	"github.com/wwmoraes/anilistarr/internal/entities"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
-var _ ServerInterface = (*Service)(nil)
+const (
+	headerContentType = "Content-Type"
+	headerStale       = "X-Stale"
+	contentTypeJSON   = "application/json; charset=utf-8"
+	contentTypeText   = "text/plain; charset=utf-8"
+
+	// stateCookie holds the state of an authorization in progress, which binds
+	// the callback to the browser that started it.
+	stateCookie     = "anilistarr_state"
+	stateCookiePath = "/auth/anilist"
+	stateTTL        = 10 * time.Minute
+)
+
+var (
+	_ ServerInterface = (*Service)(nil)
+
+	errUnconfiguredAuthorization = fmt.Errorf(
+		"%w: Anilist authorization is not configured",
+		usecases.ErrStatusUnimplemented,
+	)
+)
 
 // Service implements handlers to serve media lister as a REST API.
+//
+// Requests select a media lister through their tracker parameter. Those
+// without one use MediaLister as the default.
+//
+// Anilist users authorize access to their private data through Authorizer,
+// which grants tokens that get stored in Tokens. Both are optional. UserIDs, if
+// set, records the authorized users so lookups no longer miss those.
+//
+// State cookies are secure unless InsecureCookies is set, which plain HTTP
+// redirect URLs require. Requests over TLS get secure ones regardless.
 type Service struct {
 	Unimplemented
 
-	MediaLister usecases.MediaLister
+	MediaLister     usecases.MediaLister
+	MediaListers    map[Tracker]usecases.MediaLister
+	Authorizer      usecases.Authorizer
+	Tokens          usecases.TokenStore
+	UserIDs         usecases.UserIDCache
+	InsecureCookies bool
 }
 
 // GetUserID retrieves an user ID for a given name. Responds with:
 //   - 200 + plain-text ID + headers on success
+//   - 400 if the tracker is not supported
 //   - 404 if media lister cannot find the user
 //   - 500 for any other errors
-func (service *Service) GetUserID(w http.ResponseWriter, r *http.Request, name string) {
+func (service *Service) GetUserID(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	params GetUserIDParams,
+) {
 	span := telemetry.SpanFromContext(r.Context())
 
-	userID, err := service.MediaLister.GetUserID(r.Context(), name)
+	mediaLister, err := service.mediaListerFor(params.Tracker)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	userID, err := mediaLister.GetUserID(r.Context(), name)
 	if errors.Is(err, usecases.ErrStatusNotFound) {
 		span.RecordError(err)
 		http.Error(w, err.Error(), http.StatusNotFound)
@@ -44,7 +97,7 @@ func (service *Service) GetUserID(w http.ResponseWriter, r *http.Request, name s
 
 	w.Header().Add("X-Anilist-User-Name", name)
 	w.Header().Add("X-Anilist-User-Id", userID)
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set(headerContentType, contentTypeText)
 	w.WriteHeader(http.StatusOK)
 
 	// false positive: non-HTML content type already set and sent above
@@ -52,12 +105,36 @@ func (service *Service) GetUserID(w http.ResponseWriter, r *http.Request, name s
 	fmt.Fprintln(w, userID)
 }
 
-// GetUserMedia retrieves media information from an user. Returns 200 on success
-// with a marshaled [entities.CustomList] as JSON or a 502 otherwise.
-func (service *Service) GetUserMedia(w http.ResponseWriter, r *http.Request, name string) {
+// GetUserMedia retrieves media information from an user, optionally filtered by
+// list statuses. Returns 200 on success with a marshaled [entities.CustomList]
+// as JSON, 400 if either the tracker or a status is not supported or a 502
+// otherwise. Lists served from stale cache entries have the X-Stale header.
+func (service *Service) GetUserMedia(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	params GetUserMediaParams,
+) {
 	span := telemetry.SpanFromContext(r.Context())
 
-	customList, err := service.MediaLister.Generate(r.Context(), name)
+	mediaLister, err := service.mediaListerFor(params.Tracker)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx, stale := usecases.WithStaleness(r.Context())
+
+	customList, err := mediaLister.Generate(ctx, name, statusesOf(params.Status)...)
+	if errors.Is(err, usecases.ErrStatusInvalidArgument) {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
 	if err != nil {
 		span.RecordError(err)
 		http.Error(w, err.Error(), http.StatusBadGateway)
@@ -67,7 +144,11 @@ func (service *Service) GetUserMedia(w http.ResponseWriter, r *http.Request, nam
 
 	data, _ := json.Marshal(customList)
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if stale() {
+		w.Header().Set(headerStale, "true")
+	}
+
+	w.Header().Set(headerContentType, contentTypeJSON)
 	w.WriteHeader(http.StatusOK)
 
 	// false positive: non-HTML content type already set and sent above
@@ -76,3 +157,243 @@ func (service *Service) GetUserMedia(w http.ResponseWriter, r *http.Request, nam
 
 	span.RecordError(err)
 }
+
+// GetUserMovies retrieves movies from an user, optionally filtered by list
+// statuses. Returns 200 on success with a marshaled [entities.MovieList] as
+// JSON, 400 if either the tracker or a status is not supported, 501 if the
+// tracker has no movie support or a 502 otherwise. Lists served from stale
+// cache entries have the X-Stale header.
+func (service *Service) GetUserMovies(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	params GetUserMoviesParams,
+) {
+	span := telemetry.SpanFromContext(r.Context())
+
+	mediaLister, err := service.mediaListerFor(params.Tracker)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx, stale := usecases.WithStaleness(r.Context())
+
+	movieList, err := mediaLister.GenerateMovies(ctx, name, statusesOf(params.Status)...)
+	if errors.Is(err, usecases.ErrStatusInvalidArgument) {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if errors.Is(err, usecases.ErrStatusUnimplemented) {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+
+		return
+	}
+
+	data, _ := json.Marshal(movieList)
+
+	if stale() {
+		w.Header().Set(headerStale, "true")
+	}
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+
+	// false positive: non-HTML content type already set and sent above
+	// nosemgrep: no-direct-write-to-responsewriter
+	_, err = w.Write(data)
+
+	span.RecordError(err)
+}
+
+// GetMappingConflicts retrieves the mapping conflicts found during the last
+// refresh of a tracker sources. Returns 200 on success with a marshaled list of
+// [entities.MappingConflict] as JSON, 400 if the tracker is not supported or
+// 501 if its media lister does not report conflicts.
+func (service *Service) GetMappingConflicts(
+	w http.ResponseWriter,
+	r *http.Request,
+	params GetMappingConflictsParams,
+) {
+	span := telemetry.SpanFromContext(r.Context())
+
+	mediaLister, err := service.mediaListerFor(params.Tracker)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	reporter, ok := mediaLister.(usecases.ConflictReporter)
+	if !ok {
+		err = fmt.Errorf("%w: conflicts are not reported", usecases.ErrStatusUnimplemented)
+
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+
+		return
+	}
+
+	data, _ := json.Marshal(reporter.Conflicts())
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+
+	// false positive: non-HTML content type already set and sent above
+	// nosemgrep: no-direct-write-to-responsewriter
+	_, err = w.Write(data)
+
+	span.RecordError(err)
+}
+
+// AuthorizeAnilist starts the authorization of an Anilist user. Responds with:
+//   - 302 to the Anilist consent page, along with a state cookie
+//   - 501 if authorization is not configured
+func (service *Service) AuthorizeAnilist(w http.ResponseWriter, r *http.Request) {
+	span := telemetry.SpanFromContext(r.Context())
+
+	if service.Authorizer == nil || service.Tokens == nil {
+		span.RecordError(errUnconfiguredAuthorization)
+		http.Error(w, errUnconfiguredAuthorization.Error(), http.StatusNotImplemented)
+
+		return
+	}
+
+	state := rand.Text()
+
+	http.SetCookie(w, service.newStateCookie(r, state, int(stateTTL.Seconds())))
+	http.Redirect(w, r, service.Authorizer.AuthCodeURL(state), http.StatusFound)
+}
+
+// AuthorizeAnilistCallback finishes the authorization of an Anilist user,
+// storing their token. Responds with:
+//   - 200 + plain-text user name on success
+//   - 400 if either the state does not match or the code is invalid
+//   - 501 if authorization is not configured
+//   - 502 for any other errors
+func (service *Service) AuthorizeAnilistCallback(
+	w http.ResponseWriter,
+	r *http.Request,
+	params AuthorizeAnilistCallbackParams,
+) {
+	span := telemetry.SpanFromContext(r.Context())
+
+	if service.Authorizer == nil || service.Tokens == nil {
+		span.RecordError(errUnconfiguredAuthorization)
+		http.Error(w, errUnconfiguredAuthorization.Error(), http.StatusNotImplemented)
+
+		return
+	}
+
+	// states are single-use
+	http.SetCookie(w, service.newStateCookie(r, "", -1))
+
+	token, err := service.authorize(r, params)
+	if errors.Is(err, usecases.ErrStatusInvalidArgument) {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+
+		return
+	}
+
+	w.Header().Set(headerContentType, contentTypeText)
+	w.WriteHeader(http.StatusOK)
+
+	// false positive: non-HTML content type already set and sent above
+	// nosemgrep: no-fprintf-to-responsewriter, no-direct-write-to-responsewriter
+	fmt.Fprintln(w, "authorized Anilist user", token.UserName)
+}
+
+// authorize checks the callback state against the one the request cookie
+// holds, and then exchanges its code for a token that it stores along with the
+// user ID.
+func (service *Service) authorize(
+	r *http.Request,
+	params AuthorizeAnilistCallbackParams,
+) (*entities.Token, error) {
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(params.State)) != 1 {
+		return nil, fmt.Errorf("%w: authorization state mismatch", usecases.ErrStatusInvalidArgument)
+	}
+
+	token, err := service.Authorizer.Authorize(r.Context(), params.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize: %w", err)
+	}
+
+	err = service.Tokens.PutToken(r.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store token: %w", err)
+	}
+
+	if service.UserIDs == nil {
+		return token, nil
+	}
+
+	err = service.UserIDs.PutUserID(r.Context(), token.UserName, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store user ID: %w", err)
+	}
+
+	return token, nil
+}
+
+// mediaListerFor returns the media lister registered for tracker. It returns
+// the default one if tracker is nil, or [usecases.ErrStatusInvalidArgument] if
+// there's no media lister for it.
+func (service *Service) mediaListerFor(tracker *Tracker) (usecases.MediaLister, error) {
+	if tracker == nil {
+		return service.MediaLister, nil
+	}
+
+	mediaLister, ok := service.MediaListers[*tracker]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported tracker %q", usecases.ErrStatusInvalidArgument, *tracker)
+	}
+
+	return mediaLister, nil
+}
+
+// newStateCookie returns the cookie that holds the state of an authorization
+// for maxAge seconds. Negative values delete it instead.
+func (service *Service) newStateCookie(r *http.Request, state string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     stateCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || !service.InsecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// statusesOf dereferences an optional status parameter.
+func statusesOf(status *Status) []string {
+	if status == nil {
+		return nil
+	}
+
+	return *status
+}
@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -45,7 +46,7 @@ func TestService_GetUserID(t *testing.T) {
 	).WithContext(ctx)
 	w := httptest.NewRecorder()
 
-	service.GetUserID(w, r, username)
+	service.GetUserID(w, r, username, api.GetUserIDParams{})
 
 	res := w.Result()
 	defer res.Body.Close()
@@ -117,7 +118,7 @@ func TestService_GetUserID_error(t *testing.T) {
 			).WithContext(ctx)
 			w := httptest.NewRecorder()
 
-			service.GetUserID(w, r, username)
+			service.GetUserID(w, r, username, api.GetUserIDParams{})
 
 			res := w.Result()
 			defer res.Body.Close()
@@ -165,7 +166,7 @@ func TestService_GetUserMedia(t *testing.T) {
 		MediaLister: &mediaLister,
 	}
 
-	service.GetUserMedia(resWriter, r, username)
+	service.GetUserMedia(resWriter, r, username, api.GetUserMediaParams{})
 
 	res := resWriter.Result()
 	defer res.Body.Close()
@@ -186,6 +187,47 @@ func TestService_GetUserMedia(t *testing.T) {
 	mediaLister.AssertExpectations(t)
 }
 
+func TestService_GetUserMedia_stale(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	medias := entities.CustomList{
+		entities.CustomEntry{
+			TvdbID: 91,
+		},
+	}
+
+	mediaLister := test.NewMockMediaLister(t)
+
+	mediaLister.EXPECT().Generate(mock.Anything, username).
+		RunAndReturn(func(ctx context.Context, _ string, _ ...string) (entities.CustomList, error) {
+			usecases.MarkStale(ctx)
+
+			return medias, nil
+		}).
+		Once()
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+	resWriter := httptest.NewRecorder()
+
+	service := api.Service{
+		MediaLister: mediaLister,
+	}
+
+	service.GetUserMedia(resWriter, r, username, api.GetUserMediaParams{})
+
+	res := resWriter.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "true", res.Header.Get("X-Stale"))
+}
+
 func TestService_GetUserMedia_error(t *testing.T) {
 	t.Parallel()
 
@@ -214,7 +256,7 @@ func TestService_GetUserMedia_error(t *testing.T) {
 		MediaLister: &mediaLister,
 	}
 
-	service.GetUserMedia(resWriter, r, username)
+	service.GetUserMedia(resWriter, r, username, api.GetUserMediaParams{})
 
 	res := resWriter.Result()
 	defer res.Body.Close()
@@ -227,3 +269,617 @@ func TestService_GetUserMedia_error(t *testing.T) {
 	assert.Equal(t, http.StatusBadGateway, res.StatusCode)
 	assert.Equal(t, wantErr.Error(), gotMessage)
 }
+
+func TestService_GetUserMedia_tracker(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	medias := entities.CustomList{
+		entities.CustomEntry{
+			TvdbID: 91,
+		},
+	}
+	tracker := api.Mal
+
+	defaultMediaLister := test.NewMockMediaLister(t)
+	malMediaLister := test.NewMockMediaLister(t)
+
+	malMediaLister.EXPECT().Generate(mock.Anything, username).
+		Return(medias, nil).Once()
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+	resWriter := httptest.NewRecorder()
+
+	service := api.Service{
+		MediaLister: defaultMediaLister,
+		MediaListers: map[api.Tracker]usecases.MediaLister{
+			api.Mal: malMediaLister,
+		},
+	}
+
+	service.GetUserMedia(resWriter, r, username, api.GetUserMediaParams{
+		Tracker: &tracker,
+	})
+
+	res := resWriter.Result()
+	defer res.Body.Close()
+
+	gotBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	var gotMedias entities.CustomList
+
+	err = json.Unmarshal(gotBody, &gotMedias)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, medias, gotMedias)
+}
+
+func TestService_GetUserMedia_status(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	statuses := api.Status{"COMPLETED", "PAUSED"}
+
+	mediaLister := test.NewMockMediaLister(t)
+
+	mediaLister.EXPECT().Generate(mock.Anything, username, statuses).
+		Return(entities.CustomList{}, nil).Once()
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+	resWriter := httptest.NewRecorder()
+
+	service := api.Service{
+		MediaLister: mediaLister,
+	}
+
+	service.GetUserMedia(resWriter, r, username, api.GetUserMediaParams{
+		Status: &statuses,
+	})
+
+	res := resWriter.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestService_GetUserMedia_invalid_status(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	statuses := api.Status{"bar"}
+
+	mediaLister := test.NewMockMediaLister(t)
+
+	mediaLister.EXPECT().Generate(mock.Anything, username, statuses).
+		Return(nil, usecases.ErrStatusInvalidArgument).Once()
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+	resWriter := httptest.NewRecorder()
+
+	service := api.Service{
+		MediaLister: mediaLister,
+	}
+
+	service.GetUserMedia(resWriter, r, username, api.GetUserMediaParams{
+		Status: &statuses,
+	})
+
+	res := resWriter.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestService_GetUserMovies(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	movies := entities.MovieList{
+		{TmdbID: 129, ImdbID: "tt0245429"},
+	}
+
+	mediaLister := test.NewMockMediaLister(t)
+
+	mediaLister.EXPECT().GenerateMovies(mock.Anything, username).
+		Return(movies, nil).Once()
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+	resWriter := httptest.NewRecorder()
+
+	service := api.Service{
+		MediaLister: mediaLister,
+	}
+
+	service.GetUserMovies(resWriter, r, username, api.GetUserMoviesParams{})
+
+	res := resWriter.Result()
+	defer res.Body.Close()
+
+	gotBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Subset(t, res.Header, http.Header{
+		"Content-Type": []string{"application/json; charset=utf-8"},
+	})
+	assert.JSONEq(t, `[{"tmdbId":129,"imdb_id":"tt0245429"}]`, string(gotBody))
+}
+
+func TestService_GetUserMovies_error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wantError  error
+		name       string
+		wantStatus int
+	}{
+		{
+			name:       "invalid argument",
+			wantError:  usecases.ErrStatusInvalidArgument,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unimplemented",
+			wantError:  usecases.ErrStatusUnimplemented,
+			wantStatus: http.StatusNotImplemented,
+		},
+		{
+			name:       "unknown",
+			wantError:  errors.New("bar"),
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			username := "foo"
+
+			mediaLister := test.NewMockMediaLister(t)
+
+			mediaLister.EXPECT().GenerateMovies(mock.Anything, username).
+				Return(nil, tt.wantError).Once()
+
+			r := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"http://example.com/",
+				http.NoBody,
+			)
+			resWriter := httptest.NewRecorder()
+
+			service := api.Service{
+				MediaLister: mediaLister,
+			}
+
+			service.GetUserMovies(resWriter, r, username, api.GetUserMoviesParams{})
+
+			res := resWriter.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestService_unsupported_tracker(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	tracker := api.Tracker("bar")
+
+	service := api.Service{
+		MediaLister: test.NewMockMediaLister(t),
+	}
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+
+	userIDWriter := httptest.NewRecorder()
+	service.GetUserID(userIDWriter, r, username, api.GetUserIDParams{
+		Tracker: &tracker,
+	})
+
+	userIDRes := userIDWriter.Result()
+	defer userIDRes.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, userIDRes.StatusCode)
+
+	mediaWriter := httptest.NewRecorder()
+	service.GetUserMedia(mediaWriter, r, username, api.GetUserMediaParams{
+		Tracker: &tracker,
+	})
+
+	mediaRes := mediaWriter.Result()
+	defer mediaRes.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, mediaRes.StatusCode)
+}
+
+func TestService_GetMappingConflicts(t *testing.T) {
+	t.Parallel()
+
+	reporter := test.NewMockConflictReporter(t)
+
+	reporter.EXPECT().Conflicts().Return([]entities.MappingConflict{
+		{
+			SourceID: "1",
+			Targets: []entities.MappingTarget{
+				{Provider: "foo", TargetID: "91"},
+				{Provider: "bar", TargetID: "92"},
+			},
+		},
+	}).Once()
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+	resWriter := httptest.NewRecorder()
+
+	service := api.Service{
+		MediaLister: struct {
+			*test.MockMediaLister
+			*test.MockConflictReporter
+		}{
+			test.NewMockMediaLister(t),
+			reporter,
+		},
+	}
+
+	service.GetMappingConflicts(resWriter, r, api.GetMappingConflictsParams{})
+
+	res := resWriter.Result()
+	defer res.Body.Close()
+
+	gotBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Subset(t, res.Header, http.Header{
+		"Content-Type": []string{"application/json; charset=utf-8"},
+	})
+	assert.JSONEq(t, `[{
+		"sourceID": "1",
+		"targets": [
+			{"provider": "foo", "targetID": "91"},
+			{"provider": "bar", "targetID": "92"}
+		]
+	}]`, string(gotBody))
+}
+
+func TestService_GetMappingConflicts_error(t *testing.T) {
+	t.Parallel()
+
+	tracker := api.Tracker("bar")
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/",
+		http.NoBody,
+	)
+
+	service := api.Service{
+		MediaLister: test.NewMockMediaLister(t),
+	}
+
+	unimplementedWriter := httptest.NewRecorder()
+	service.GetMappingConflicts(unimplementedWriter, r, api.GetMappingConflictsParams{})
+
+	unimplementedRes := unimplementedWriter.Result()
+	defer unimplementedRes.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, unimplementedRes.StatusCode)
+
+	trackerWriter := httptest.NewRecorder()
+	service.GetMappingConflicts(trackerWriter, r, api.GetMappingConflictsParams{
+		Tracker: &tracker,
+	})
+
+	trackerRes := trackerWriter.Result()
+	defer trackerRes.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, trackerRes.StatusCode)
+}
+
+func TestService_AuthorizeAnilist(t *testing.T) {
+	t.Parallel()
+
+	var state string
+
+	authorizer := test.NewMockAuthorizer(t)
+	authorizer.EXPECT().AuthCodeURL(mock.Anything).RunAndReturn(func(value string) string {
+		state = value
+
+		return "https://anilist.co/api/v2/oauth/authorize?state=" + value
+	}).Once()
+
+	service := api.Service{
+		Authorizer: authorizer,
+		Tokens:     test.NewMockTokenStore(t),
+	}
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/auth/anilist",
+		http.NoBody,
+	)
+	w := httptest.NewRecorder()
+
+	service.AuthorizeAnilist(w, r)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	assert.Equal(t, "https://anilist.co/api/v2/oauth/authorize?state="+state, res.Header.Get("Location"))
+
+	cookies := res.Cookies()
+	require.Len(t, cookies, 1)
+	require.NotEmpty(t, state)
+
+	assert.Equal(t, state, cookies[0].Value)
+	assert.Equal(t, "/auth/anilist", cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+}
+
+func TestService_AuthorizeAnilist_insecure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		wantSecure bool
+	}{
+		{
+			name:   "plain HTTP",
+			target: "http://example.com/auth/anilist",
+		},
+		{
+			name:       "TLS",
+			target:     "https://example.com/auth/anilist",
+			wantSecure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			authorizer := test.NewMockAuthorizer(t)
+			authorizer.EXPECT().AuthCodeURL(mock.Anything).Return("https://anilist.co").Once()
+
+			service := api.Service{
+				Authorizer:      authorizer,
+				Tokens:          test.NewMockTokenStore(t),
+				InsecureCookies: true,
+			}
+
+			// requests to HTTPS targets have a TLS state
+			r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, tt.target, http.NoBody)
+			w := httptest.NewRecorder()
+
+			service.AuthorizeAnilist(w, r)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			cookies := res.Cookies()
+			require.Len(t, cookies, 1)
+
+			assert.Equal(t, tt.wantSecure, cookies[0].Secure)
+		})
+	}
+}
+
+func TestService_AuthorizeAnilistCallback(t *testing.T) {
+	t.Parallel()
+
+	token := &entities.Token{
+		UserID:      "1",
+		UserName:    "foo",
+		AccessToken: "bar",
+	}
+
+	authorizer := test.NewMockAuthorizer(t)
+	authorizer.EXPECT().Authorize(mock.Anything, "baz").Return(token, nil).Once()
+
+	tokens := test.NewMockTokenStore(t)
+	tokens.EXPECT().PutToken(mock.Anything, token).Return(nil).Once()
+
+	userIDs := test.NewMockUserIDCache(t)
+	userIDs.EXPECT().PutUserID(mock.Anything, "foo", "1").Return(nil).Once()
+
+	service := api.Service{
+		Authorizer: authorizer,
+		Tokens:     tokens,
+		UserIDs:    userIDs,
+	}
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/auth/anilist/callback?code=baz&state=qux",
+		http.NoBody,
+	)
+	r.AddCookie(&http.Cookie{Name: "anilistarr_state", Value: "qux"})
+
+	w := httptest.NewRecorder()
+
+	service.AuthorizeAnilistCallback(w, r, api.AuthorizeAnilistCallbackParams{
+		Code:  "baz",
+		State: "qux",
+	})
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	gotBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "authorized Anilist user foo\n", string(gotBody))
+
+	// the state is single-use
+	cookies := res.Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "anilistarr_state", cookies[0].Name)
+	assert.Negative(t, cookies[0].MaxAge)
+}
+
+func TestService_AuthorizeAnilistCallback_error(t *testing.T) {
+	t.Parallel()
+
+	errAuthorize := errors.New("authorize failure")
+
+	tests := []struct {
+		authorizeErr error
+		putErr       error
+		putUserErr   error
+		name         string
+		state        string
+		wantStatus   int
+	}{
+		{
+			name:       "missing state",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "state mismatch",
+			state:      "quux",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid code",
+			state:        "qux",
+			authorizeErr: usecases.ErrStatusInvalidArgument,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "upstream failure",
+			state:        "qux",
+			authorizeErr: errAuthorize,
+			wantStatus:   http.StatusBadGateway,
+		},
+		{
+			name:       "store failure",
+			state:      "qux",
+			putErr:     usecases.ErrStatusUnavailable,
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "user ID failure",
+			state:      "qux",
+			putUserErr: usecases.ErrStatusUnavailable,
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := &entities.Token{UserID: "1", UserName: "foo", AccessToken: "bar"}
+
+			authorizer := test.NewMockAuthorizer(t)
+			tokens := test.NewMockTokenStore(t)
+
+			authorizer.EXPECT().Authorize(mock.Anything, "baz").Return(token, tt.authorizeErr).Maybe()
+			tokens.EXPECT().PutToken(mock.Anything, token).Return(tt.putErr).Maybe()
+
+			userIDs := test.NewMockUserIDCache(t)
+			userIDs.EXPECT().PutUserID(mock.Anything, "foo", "1").Return(tt.putUserErr).Maybe()
+
+			service := api.Service{
+				Authorizer: authorizer,
+				Tokens:     tokens,
+				UserIDs:    userIDs,
+			}
+
+			r := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"http://example.com/auth/anilist/callback?code=baz&state=qux",
+				http.NoBody,
+			)
+
+			if tt.state != "" {
+				r.AddCookie(&http.Cookie{Name: "anilistarr_state", Value: tt.state})
+			}
+
+			w := httptest.NewRecorder()
+
+			service.AuthorizeAnilistCallback(w, r, api.AuthorizeAnilistCallbackParams{
+				Code:  "baz",
+				State: "qux",
+			})
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestService_AuthorizeAnilist_unconfigured(t *testing.T) {
+	t.Parallel()
+
+	service := api.Service{}
+
+	r := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"http://example.com/auth/anilist",
+		http.NoBody,
+	)
+
+	startWriter := httptest.NewRecorder()
+	service.AuthorizeAnilist(startWriter, r)
+
+	startRes := startWriter.Result()
+	defer startRes.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, startRes.StatusCode)
+
+	callbackWriter := httptest.NewRecorder()
+	service.AuthorizeAnilistCallback(callbackWriter, r, api.AuthorizeAnilistCallbackParams{})
+
+	callbackRes := callbackWriter.Result()
+	defer callbackRes.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, callbackRes.StatusCode)
+}
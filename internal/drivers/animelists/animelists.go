@@ -4,8 +4,13 @@ package animelists
 
 import (
 	"strconv"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
 )
 
+// TypeMovie is the [Metadata] type of movie entries.
+const TypeMovie = "MOVIE"
+
 // Metadata represents a media with IDs for multiple services.
 //
 // It expects an entry in the anime-lists project format. See:
@@ -16,17 +21,27 @@ import (
 //nolint:tagliatelle // JSON tags must match the upstream naming convention
 type Metadata struct {
 	// AnimePlanetID string `json:"anime,omitempty"`
-	// ImdbID        string `json:"imdb_id,omitempty"`
+
+	ImdbID string `json:"imdb_id,omitempty"`
+
 	// NotifyMoeID   string `json:"notify,omitempty"`
-	// AnidbID       uint64 `json:"anidb_id,omitempty"`
+
+	// Type is the media format, such as TV, MOVIE, OVA or SPECIAL.
+	Type string `json:"type,omitempty"`
+
+	AnidbID uint64 `json:"anidb_id,omitempty"`
 
 	AnilistID uint64 `json:"anilist_id,omitempty"`
 
 	// AnisearchID   uint64 `json:"anisearch_id,omitempty"`
-	// KitsuID       uint64 `json:"kitsu_id,omitempty"`
+
+	KitsuID uint64 `json:"kitsu_id,omitempty"`
+
 	// LiveChartID   uint64 `json:"livechart_id,omitempty"`
-	// MalID         uint64 `json:"mal_id,omitempty"`
-	// TheMovieDbID  uint64 `json:"themoviedb_id,omitempty"`
+
+	MalID uint64 `json:"mal_id,omitempty"`
+
+	TheMovieDbID uint64 `json:"themoviedb_id,omitempty"`
 
 	TvdbID uint64 `json:"thetvdb_id,omitempty"`
 }
@@ -35,6 +50,32 @@ type Metadata struct {
 // Anilist and TVDB IDs, mapping the former as source and the latter as target.
 type Anilist2TVDBMetadata Metadata
 
+// MAL2TVDBMetadata represents an anime lists entry with both MyAnimeList and
+// TVDB IDs, mapping the former as source and the latter as target.
+type MAL2TVDBMetadata Metadata
+
+// Kitsu2TVDBMetadata represents an anime lists entry with both Kitsu and TVDB
+// IDs, mapping the former as source and the latter as target.
+type Kitsu2TVDBMetadata Metadata
+
+// Anilist2TMDBMetadata represents an anime lists movie entry with both Anilist
+// and TMDB IDs, mapping the former as source and the latter as target. Targets
+// also carry the IMDb ID if available.
+type Anilist2TMDBMetadata Metadata
+
+// Anilist2AniDBMetadata represents an anime lists entry with both Anilist and
+// AniDB IDs, mapping the former as source and the latter as target. It allows
+// sources based on AniDB IDs to resolve those to Anilist ones, such as [XML].
+type Anilist2AniDBMetadata Metadata
+
+// MAL2AniDBMetadata represents an anime lists entry with both MyAnimeList and
+// AniDB IDs, mapping the former as source and the latter as target.
+type MAL2AniDBMetadata Metadata
+
+// Kitsu2AniDBMetadata represents an anime lists entry with both Kitsu and AniDB
+// IDs, mapping the former as source and the latter as target.
+type Kitsu2AniDBMetadata Metadata
+
 // GetTargetID retrieves the target ID of this metadata entry.
 func (entry Anilist2TVDBMetadata) GetTargetID() string {
 	return strconv.FormatUint(entry.TvdbID, 10)
@@ -49,3 +90,97 @@ func (entry Anilist2TVDBMetadata) GetSourceID() string {
 func (entry Anilist2TVDBMetadata) Valid() bool {
 	return entry.AnilistID > 0 && entry.TvdbID > 0
 }
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry MAL2TVDBMetadata) GetTargetID() string {
+	return strconv.FormatUint(entry.TvdbID, 10)
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry MAL2TVDBMetadata) GetSourceID() string {
+	return strconv.FormatUint(entry.MalID, 10)
+}
+
+// Valid returns true if both source and target IDs are non-zero.
+func (entry MAL2TVDBMetadata) Valid() bool {
+	return entry.MalID > 0 && entry.TvdbID > 0
+}
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry Kitsu2TVDBMetadata) GetTargetID() string {
+	return strconv.FormatUint(entry.TvdbID, 10)
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry Kitsu2TVDBMetadata) GetSourceID() string {
+	return strconv.FormatUint(entry.KitsuID, 10)
+}
+
+// Valid returns true if both source and target IDs are non-zero.
+func (entry Kitsu2TVDBMetadata) Valid() bool {
+	return entry.KitsuID > 0 && entry.TvdbID > 0
+}
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry Anilist2TMDBMetadata) GetTargetID() string {
+	return entities.MovieEntry{
+		ImdbID: entry.ImdbID,
+		TmdbID: entry.TheMovieDbID,
+	}.TargetID()
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry Anilist2TMDBMetadata) GetSourceID() string {
+	return strconv.FormatUint(entry.AnilistID, 10)
+}
+
+// Valid returns true if this is a movie and both source and target IDs are
+// non-zero. TMDB IDs of other types refer to TV shows, which Radarr rejects.
+func (entry Anilist2TMDBMetadata) Valid() bool {
+	return entry.Type == TypeMovie && entry.AnilistID > 0 && entry.TheMovieDbID > 0
+}
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry Anilist2AniDBMetadata) GetTargetID() string {
+	return strconv.FormatUint(entry.AnidbID, 10)
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry Anilist2AniDBMetadata) GetSourceID() string {
+	return strconv.FormatUint(entry.AnilistID, 10)
+}
+
+// Valid returns true if both source and target IDs are non-zero.
+func (entry Anilist2AniDBMetadata) Valid() bool {
+	return entry.AnilistID > 0 && entry.AnidbID > 0
+}
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry MAL2AniDBMetadata) GetTargetID() string {
+	return strconv.FormatUint(entry.AnidbID, 10)
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry MAL2AniDBMetadata) GetSourceID() string {
+	return strconv.FormatUint(entry.MalID, 10)
+}
+
+// Valid returns true if both source and target IDs are non-zero.
+func (entry MAL2AniDBMetadata) Valid() bool {
+	return entry.MalID > 0 && entry.AnidbID > 0
+}
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry Kitsu2AniDBMetadata) GetTargetID() string {
+	return strconv.FormatUint(entry.AnidbID, 10)
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry Kitsu2AniDBMetadata) GetSourceID() string {
+	return strconv.FormatUint(entry.KitsuID, 10)
+}
+
+// Valid returns true if both source and target IDs are non-zero.
+func (entry Kitsu2AniDBMetadata) Valid() bool {
+	return entry.KitsuID > 0 && entry.AnidbID > 0
+}
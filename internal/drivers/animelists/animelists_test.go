@@ -80,3 +80,176 @@ func TestAnilist2TVDBMetadata_Valid(t *testing.T) {
 		})
 	}
 }
+
+func TestMAL2TVDBMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantSource string
+		wantTarget string
+		entry      animelists.MAL2TVDBMetadata
+		wantValid  bool
+	}{
+		{
+			name: "valid",
+			entry: animelists.MAL2TVDBMetadata{
+				AnilistID: 1,
+				MalID:     2,
+				TvdbID:    91,
+			},
+			wantSource: "2",
+			wantTarget: "91",
+			wantValid:  true,
+		},
+		{
+			name: "empty source",
+			entry: animelists.MAL2TVDBMetadata{
+				AnilistID: 1,
+				TvdbID:    91,
+			},
+			wantSource: "0",
+			wantTarget: "91",
+			wantValid:  false,
+		},
+		{
+			name: "empty target",
+			entry: animelists.MAL2TVDBMetadata{
+				MalID: 2,
+			},
+			wantSource: "2",
+			wantTarget: "0",
+			wantValid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantSource, tt.entry.GetSourceID())
+			assert.Equal(t, tt.wantTarget, tt.entry.GetTargetID())
+			assert.Equal(t, tt.wantValid, tt.entry.Valid())
+		})
+	}
+}
+
+func TestKitsu2TVDBMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantSource string
+		wantTarget string
+		entry      animelists.Kitsu2TVDBMetadata
+		wantValid  bool
+	}{
+		{
+			name: "valid",
+			entry: animelists.Kitsu2TVDBMetadata{
+				AnilistID: 1,
+				KitsuID:   3,
+				TvdbID:    91,
+			},
+			wantSource: "3",
+			wantTarget: "91",
+			wantValid:  true,
+		},
+		{
+			name: "empty source",
+			entry: animelists.Kitsu2TVDBMetadata{
+				AnilistID: 1,
+				TvdbID:    91,
+			},
+			wantSource: "0",
+			wantTarget: "91",
+			wantValid:  false,
+		},
+		{
+			name: "empty target",
+			entry: animelists.Kitsu2TVDBMetadata{
+				KitsuID: 3,
+			},
+			wantSource: "3",
+			wantTarget: "0",
+			wantValid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantSource, tt.entry.GetSourceID())
+			assert.Equal(t, tt.wantTarget, tt.entry.GetTargetID())
+			assert.Equal(t, tt.wantValid, tt.entry.Valid())
+		})
+	}
+}
+
+func TestAnilist2TMDBMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantSource string
+		wantTarget string
+		entry      animelists.Anilist2TMDBMetadata
+		wantValid  bool
+	}{
+		{
+			name: "valid",
+			entry: animelists.Anilist2TMDBMetadata{
+				AnilistID:    1,
+				ImdbID:       "tt0245429",
+				TheMovieDbID: 129,
+				Type:         animelists.TypeMovie,
+			},
+			wantSource: "1",
+			wantTarget: "129/tt0245429",
+			wantValid:  true,
+		},
+		{
+			name: "valid without IMDb",
+			entry: animelists.Anilist2TMDBMetadata{
+				AnilistID:    1,
+				TheMovieDbID: 129,
+				Type:         animelists.TypeMovie,
+			},
+			wantSource: "1",
+			wantTarget: "129",
+			wantValid:  true,
+		},
+		{
+			name: "not a movie",
+			entry: animelists.Anilist2TMDBMetadata{
+				AnilistID:    1,
+				TheMovieDbID: 129,
+				Type:         "TV",
+			},
+			wantSource: "1",
+			wantTarget: "129",
+			wantValid:  false,
+		},
+		{
+			name: "empty target",
+			entry: animelists.Anilist2TMDBMetadata{
+				AnilistID: 1,
+				Type:      animelists.TypeMovie,
+			},
+			wantSource: "1",
+			wantTarget: "0",
+			wantValid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantSource, tt.entry.GetSourceID())
+			assert.Equal(t, tt.wantTarget, tt.entry.GetTargetID())
+			assert.Equal(t, tt.wantValid, tt.entry.Valid())
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package animelists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// ManamiEntry constrains the metadata types a [Manami] source results in. The
+// anime-offline-database has no TVDB nor TMDB IDs, so only entries that map to
+// AniDB are ever valid; those are meant as IDs for an [XML] source.
+type ManamiEntry interface {
+	usecases.Metadata
+	Anilist2AniDBMetadata | MAL2AniDBMetadata | Kitsu2AniDBMetadata
+}
+
+// ManamiDatabase is the root object of the anime-offline-database JSON. See
+// https://github.com/manami-project/anime-offline-database
+type ManamiDatabase struct {
+	Data []ManamiAnime `json:"data"`
+}
+
+// ManamiAnime is an anime-offline-database entry. Instead of ID fields it has
+// the URLs of the entry on each service it is listed in.
+type ManamiAnime struct {
+	Title   string   `json:"title"`
+	Type    string   `json:"type"`
+	Sources []string `json:"sources"`
+}
+
+// Manami is a URI to an anime-offline-database JSON. It results in entries of
+// the F type, which defines the services it maps from and to.
+type Manami[F ManamiEntry] string
+
+// Metadata extracts the service IDs from the entry source URLs. It ignores
+// URLs of unknown services and the ones without a numeric ID.
+func (anime ManamiAnime) Metadata() Metadata {
+	var metadata Metadata
+
+	metadata.Type = anime.Type
+
+	for _, source := range anime.Sources {
+		uri, err := url.Parse(source)
+		if err != nil || path.Dir(uri.Path) != "/anime" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(path.Base(uri.Path), 10, 0)
+		if err != nil {
+			continue
+		}
+
+		switch strings.TrimPrefix(uri.Hostname(), "www.") {
+		case "anidb.net":
+			metadata.AnidbID = id
+		case "anilist.co":
+			metadata.AnilistID = id
+		case "kitsu.app", "kitsu.io":
+			metadata.KitsuID = id
+		case "myanimelist.net":
+			metadata.MalID = id
+		}
+	}
+
+	return metadata
+}
+
+// String returns the provider URI.
+func (source Manami[F]) String() string {
+	return string(source)
+}
+
+// Fetch retrieves and parses the anime-offline-database JSON. It results in one
+// entry of type F per anime, including invalid ones.
+func (source Manami[F]) Fetch(
+	ctx context.Context,
+	client usecases.Getter,
+) ([]usecases.Metadata, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if client == nil {
+		return nil, fmt.Errorf("%w: no getter set", usecases.ErrStatusInternal)
+	}
+
+	data, err := client.Get(ctx, source.String())
+	if err != nil {
+		return nil, span.Assert(
+			errors.Join(usecases.ErrStatusUnavailable, fmt.Errorf("failed to get JSON: %w", err)),
+		)
+	}
+
+	var database ManamiDatabase
+
+	err = json.Unmarshal(data, &database)
+	if err != nil {
+		return nil, span.Assert(errors.Join(
+			usecases.ErrStatusFailedPrecondition,
+			fmt.Errorf("failed to unmarshal JSON: %w", err),
+		))
+	}
+
+	entries := make([]usecases.Metadata, 0, len(database.Data))
+	for _, anime := range database.Data {
+		entries = append(entries, F(anime.Metadata()))
+	}
+
+	return entries, span.Assert(nil)
+}
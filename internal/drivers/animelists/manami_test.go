@@ -0,0 +1,144 @@
+package animelists_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/animelists"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const manamiURI = "mem://anime-offline-database.json"
+
+func TestManami_Fetch(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "anime-offline-database.json"))
+	require.NoError(t, err)
+
+	getter := test.NewMockGetter(t)
+	getter.EXPECT().Get(mock.Anything, manamiURI).Return(data, nil).Once()
+
+	source := animelists.Manami[animelists.Anilist2AniDBMetadata](manamiURI)
+
+	assert.Equal(t, manamiURI, source.String())
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.NoError(t, err)
+
+	assert.Equal(t, []usecases.Metadata{
+		animelists.Anilist2AniDBMetadata{
+			Type:      "TV",
+			AnidbID:   23,
+			AnilistID: 1,
+			KitsuID:   1,
+			MalID:     1,
+		},
+		animelists.Anilist2AniDBMetadata{
+			Type:      "TV",
+			AnidbID:   18429,
+			AnilistID: 163270,
+			KitsuID:   47291,
+		},
+		animelists.Anilist2AniDBMetadata{
+			Type:      "ONA",
+			AnilistID: 170000,
+		},
+	}, got)
+
+	assert.True(t, got[0].Valid())
+	assert.Equal(t, "1", got[0].GetSourceID())
+	assert.Equal(t, "23", got[0].GetTargetID())
+	assert.True(t, got[1].Valid())
+	assert.False(t, got[2].Valid())
+}
+
+func TestManami_Fetch_error(t *testing.T) {
+	t.Parallel()
+
+	errGet := errors.New("foo")
+
+	tests := []struct {
+		wantError error
+		setup     func(getter *test.MockGetter)
+		name      string
+	}{
+		{
+			name: "get error",
+			setup: func(getter *test.MockGetter) {
+				getter.EXPECT().Get(mock.Anything, manamiURI).
+					Return(nil, errGet).Once()
+			},
+			wantError: errGet,
+		},
+		{
+			name: "invalid JSON",
+			setup: func(getter *test.MockGetter) {
+				getter.EXPECT().Get(mock.Anything, manamiURI).
+					Return([]byte(`{"data":`), nil).Once()
+			},
+			wantError: usecases.ErrStatusFailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			getter := test.NewMockGetter(t)
+
+			tt.setup(getter)
+
+			source := animelists.Manami[animelists.Kitsu2AniDBMetadata](manamiURI)
+
+			got, err := source.Fetch(t.Context(), getter)
+			require.ErrorIs(t, err, tt.wantError)
+
+			assert.Nil(t, got)
+		})
+	}
+
+	got, err := animelists.Manami[animelists.MAL2AniDBMetadata](manamiURI).
+		Fetch(t.Context(), nil)
+	require.ErrorIs(t, err, usecases.ErrStatusInternal)
+
+	assert.Nil(t, got)
+}
+
+func TestManamiAnime_Metadata(t *testing.T) {
+	t.Parallel()
+
+	anime := animelists.ManamiAnime{
+		Type: "MOVIE",
+		Sources: []string{
+			"https://anidb.net/anime/5",
+			"https://www.anilist.co/anime/6",
+			"https://kitsu.io/anime/7",
+			"https://myanimelist.net/anime/8",
+			"https://myanimelist.net/manga/9",
+			"https://livechart.me/anime/10",
+			"://invalid",
+		},
+	}
+
+	assert.Equal(t, animelists.Metadata{
+		Type:      "MOVIE",
+		AnidbID:   5,
+		AnilistID: 6,
+		KitsuID:   7,
+		MalID:     8,
+	}, anime.Metadata())
+
+	assert.Equal(t, "8", animelists.MAL2AniDBMetadata(anime.Metadata()).GetSourceID())
+	assert.Equal(t, "7", animelists.Kitsu2AniDBMetadata(anime.Metadata()).GetSourceID())
+	assert.Equal(t, "5", animelists.Kitsu2AniDBMetadata(anime.Metadata()).GetTargetID())
+	assert.True(t, animelists.MAL2AniDBMetadata(anime.Metadata()).Valid())
+	assert.True(t, animelists.Kitsu2AniDBMetadata(anime.Metadata()).Valid())
+}
@@ -0,0 +1,227 @@
+package animelists
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+var (
+	_ usecases.Source           = (*XML)(nil)
+	_ usecases.ConflictReporter = (*XML)(nil)
+	_ usecases.SeasonMetadata   = (*AnimeMetadata)(nil)
+)
+
+// AnimeList is the root element of the anime-lists XML mappings. See
+// https://github.com/Anime-Lists/anime-lists/
+type AnimeList struct {
+	XMLName xml.Name `xml:"anime-list"`
+	Anime   []Anime  `xml:"anime"`
+}
+
+// Anime maps an AniDB entry to a TVDB series. AniDB splits series in one entry
+// per season, so each one has the TVDB season it matches to by default plus an
+// episode offset within it. The mapping list contains exceptions for specific
+// episodes.
+//
+// TVDB IDs are either numeric or a marker of entries TVDB lacks, such as movie
+// or unknown. Default seasons are either numeric or "a" for absolute episode
+// numbering.
+type Anime struct {
+	Name              string    `xml:"name"`
+	AnidbID           string    `xml:"anidbid,attr"`
+	TvdbID            string    `xml:"tvdbid,attr"`
+	DefaultTvdbSeason string    `xml:"defaulttvdbseason,attr"`
+	EpisodeOffset     string    `xml:"episodeoffset,attr"`
+	TmdbID            string    `xml:"tmdbid,attr"`
+	ImdbID            string    `xml:"imdbid,attr"`
+	Mappings          []Mapping `xml:"mapping-list>mapping"`
+}
+
+// Mapping maps AniDB episodes of a season to TVDB ones. Episodes is either a
+// list of AniDB-TVDB episode pairs such as ;1-5;2-6; or empty, in which case
+// the episodes from Start to End are shifted by Offset.
+type Mapping struct {
+	Episodes    string `xml:",chardata"`
+	AnidbSeason uint64 `xml:"anidbseason,attr"`
+	TvdbSeason  uint64 `xml:"tvdbseason,attr"`
+	Start       uint64 `xml:"start,attr"`
+	End         uint64 `xml:"end,attr"`
+	Offset      int64  `xml:"offset,attr"`
+}
+
+// AnimeMetadata represents an anime-lists XML entry with its AniDB ID resolved
+// to a tracker one, mapping the latter as source and the TVDB ID as target.
+type AnimeMetadata struct {
+	SourceID string
+	Anime
+}
+
+// XML is a [usecases.Source] for the anime-lists XML mappings. Those use AniDB
+// IDs, so it resolves them through IDs, a source that maps tracker IDs to
+// AniDB ones such as the Fribb lists with [Anilist2AniDBMetadata] entries.
+type XML struct {
+	IDs usecases.Source
+	URI string
+}
+
+// GetTargetSeason returns the default TVDB season of this entry, or nil if it
+// uses absolute episode numbering.
+func (anime Anime) GetTargetSeason() *uint64 {
+	season, err := strconv.ParseUint(anime.DefaultTvdbSeason, 10, 0)
+	if err != nil {
+		return nil
+	}
+
+	return &season
+}
+
+// GetEpisodeOffset returns how many episodes precede this entry within its
+// default TVDB season.
+func (anime Anime) GetEpisodeOffset() int64 {
+	//nolint:errcheck // an empty offset means there's none
+	offset, _ := strconv.ParseInt(anime.EpisodeOffset, 10, 0)
+
+	return offset
+}
+
+// Valid returns true if both AniDB and TVDB IDs are numeric and non-zero.
+func (anime Anime) Valid() bool {
+	anidbID, err := strconv.ParseUint(anime.AnidbID, 10, 0)
+	if err != nil || anidbID == 0 {
+		return false
+	}
+
+	tvdbID, err := strconv.ParseUint(anime.TvdbID, 10, 0)
+
+	return err == nil && tvdbID > 0
+}
+
+// GetTargetID retrieves the target ID of this metadata entry.
+func (entry AnimeMetadata) GetTargetID() string {
+	return entry.TvdbID
+}
+
+// GetSourceID retrieves the source ID of this metadata entry.
+func (entry AnimeMetadata) GetSourceID() string {
+	return entry.SourceID
+}
+
+// Valid returns true if both source and target IDs are non-zero.
+func (entry AnimeMetadata) Valid() bool {
+	return entry.SourceID != "" && entry.SourceID != "0" && entry.Anime.Valid()
+}
+
+// String returns the provider URI.
+func (source *XML) String() string {
+	return source.URI
+}
+
+// Fetch retrieves and parses the anime-lists XML and resolves its entries to
+// tracker IDs. It results in one [AnimeMetadata] per tracker ID, and skips
+// entries without one. It is a partial fetch if the IDs fetch is.
+//
+// The XML gets requested even if the IDs are not modified, as it changes on its
+// own. It fails without them then, which has refreshes fetch both again if the
+// XML did change.
+func (source *XML) Fetch(
+	ctx context.Context,
+	client usecases.Getter,
+) ([]usecases.Metadata, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if client == nil {
+		return nil, fmt.Errorf("%w: no getter set", usecases.ErrStatusInternal)
+	}
+
+	if source.IDs == nil {
+		return nil, fmt.Errorf("%w: no ID source set", usecases.ErrStatusFailedPrecondition)
+	}
+
+	idEntries, idsErr := source.IDs.Fetch(ctx, client)
+
+	unmodifiedIDs := errors.Is(idsErr, usecases.ErrNotModified)
+	if idsErr != nil && !unmodifiedIDs && !errors.Is(idsErr, usecases.ErrPartialFetch) {
+		return nil, span.Assert(fmt.Errorf("failed to get IDs: %w", idsErr))
+	}
+
+	data, err := client.Get(ctx, source.String())
+	if err != nil {
+		return nil, span.Assert(
+			errors.Join(usecases.ErrStatusUnavailable, fmt.Errorf("failed to get XML: %w", err)),
+		)
+	}
+
+	if unmodifiedIDs {
+		return nil, span.Assert(fmt.Errorf("failed to get IDs: %w", idsErr))
+	}
+
+	var animeList AnimeList
+
+	err = xml.Unmarshal(data, &animeList)
+	if err != nil {
+		return nil, span.Assert(errors.Join(
+			usecases.ErrStatusFailedPrecondition,
+			fmt.Errorf("failed to unmarshal XML: %w", err),
+		))
+	}
+
+	entries := resolveIDs(animeList, idEntries)
+
+	if idsErr != nil {
+		return entries, span.Assert(fmt.Errorf("failed to get all IDs: %w", idsErr))
+	}
+
+	return entries, span.Assert(nil)
+}
+
+// resolveIDs returns one entry per tracker ID of each anime, which idEntries
+// map to AniDB ones.
+func resolveIDs(animeList AnimeList, idEntries []usecases.Metadata) []usecases.Metadata {
+	anidbIDs := reverseIDs(idEntries)
+	entries := make([]usecases.Metadata, 0, len(animeList.Anime))
+
+	for _, anime := range animeList.Anime {
+		for _, sourceID := range anidbIDs[anime.AnidbID] {
+			entries = append(entries, AnimeMetadata{
+				SourceID: sourceID,
+				Anime:    anime,
+			})
+		}
+	}
+
+	return entries
+}
+
+// Conflicts returns the conflicts of IDs, if it reports any.
+func (source *XML) Conflicts() []entities.MappingConflict {
+	reporter, ok := source.IDs.(usecases.ConflictReporter)
+	if !ok {
+		return nil
+	}
+
+	return reporter.Conflicts()
+}
+
+// reverseIDs maps the target IDs of valid entries to all their source IDs.
+func reverseIDs(entries []usecases.Metadata) map[string][]string {
+	ids := make(map[string][]string, len(entries))
+
+	for _, entry := range entries {
+		if !entry.Valid() {
+			continue
+		}
+
+		ids[entry.GetTargetID()] = append(ids[entry.GetTargetID()], entry.GetSourceID())
+	}
+
+	return ids
+}
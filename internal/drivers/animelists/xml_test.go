@@ -0,0 +1,387 @@
+package animelists_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/sources"
+	"github.com/wwmoraes/anilistarr/internal/drivers/animelists"
+	"github.com/wwmoraes/anilistarr/internal/drivers/memory"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const xmlURI = "mem://anime-list.xml"
+
+func TestXML_Fetch(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "anime-list.xml"))
+	require.NoError(t, err)
+
+	getter := test.NewMockGetter(t)
+	ids := test.NewMockSource(t)
+
+	ids.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "101", TargetID: "1"},
+		test.Metadata{SourceID: "102", TargetID: "2"},
+		test.Metadata{SourceID: "103", TargetID: "2"},
+		test.Metadata{SourceID: "169", TargetID: "69"},
+		test.Metadata{SourceID: "199", TargetID: "99"},
+		test.Metadata{SourceID: "0", TargetID: "5"},
+	}, nil).Once()
+	getter.EXPECT().Get(mock.Anything, xmlURI).Return(data, nil).Once()
+
+	source := animelists.XML{
+		IDs: ids,
+		URI: xmlURI,
+	}
+
+	assert.Equal(t, xmlURI, source.String())
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.NoError(t, err)
+
+	type want struct {
+		season   *uint64
+		sourceID string
+		targetID string
+	}
+
+	seasonOne := uint64(1)
+	seasonTwo := uint64(2)
+	wants := []want{
+		{sourceID: "101", targetID: "72025", season: &seasonOne},
+		{sourceID: "102", targetID: "72025", season: &seasonTwo},
+		{sourceID: "103", targetID: "72025", season: &seasonTwo},
+		{sourceID: "169", targetID: "81797", season: nil},
+	}
+
+	require.Len(t, got, len(wants))
+
+	for index, entry := range got {
+		seasonEntry, ok := entry.(usecases.SeasonMetadata)
+		require.True(t, ok)
+
+		assert.True(t, entry.Valid())
+		assert.Equal(t, wants[index].sourceID, entry.GetSourceID())
+		assert.Equal(t, wants[index].targetID, entry.GetTargetID())
+		assert.Equal(t, wants[index].season, seasonEntry.GetTargetSeason())
+	}
+
+	first, ok := got[0].(animelists.AnimeMetadata)
+	require.True(t, ok)
+
+	assert.Equal(t, []animelists.Mapping{
+		{AnidbSeason: 0, TvdbSeason: 0, Episodes: ";1-4;"},
+	}, first.Mappings)
+
+	second, ok := got[1].(animelists.AnimeMetadata)
+	require.True(t, ok)
+
+	assert.Equal(t, []animelists.Mapping{
+		{AnidbSeason: 1, TvdbSeason: 2, Start: 1, End: 10, Offset: -13},
+	}, second.Mappings)
+}
+
+func TestXML_Fetch_error(t *testing.T) {
+	t.Parallel()
+
+	errGet := errors.New("foo")
+
+	tests := []struct {
+		setup     func(getter *test.MockGetter, ids *test.MockSource)
+		wantError error
+		name      string
+	}{
+		{
+			name: "IDs error",
+			setup: func(getter *test.MockGetter, ids *test.MockSource) {
+				ids.EXPECT().Fetch(mock.Anything, getter).
+					Return(nil, usecases.ErrStatusUnavailable).Once()
+			},
+			wantError: usecases.ErrStatusUnavailable,
+		},
+		{
+			name: "get error",
+			setup: func(getter *test.MockGetter, ids *test.MockSource) {
+				ids.EXPECT().Fetch(mock.Anything, getter).
+					Return(nil, nil).Once()
+				getter.EXPECT().Get(mock.Anything, xmlURI).
+					Return(nil, errGet).Once()
+			},
+			wantError: errGet,
+		},
+		{
+			name: "invalid XML",
+			setup: func(getter *test.MockGetter, ids *test.MockSource) {
+				ids.EXPECT().Fetch(mock.Anything, getter).
+					Return(nil, nil).Once()
+				getter.EXPECT().Get(mock.Anything, xmlURI).
+					Return([]byte("<anime-list>"), nil).Once()
+			},
+			wantError: usecases.ErrStatusFailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			getter := test.NewMockGetter(t)
+			ids := test.NewMockSource(t)
+
+			tt.setup(getter, ids)
+
+			source := animelists.XML{
+				IDs: ids,
+				URI: xmlURI,
+			}
+
+			got, err := source.Fetch(t.Context(), getter)
+			require.ErrorIs(t, err, tt.wantError)
+
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestXML_Fetch_partial(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "anime-list.xml"))
+	require.NoError(t, err)
+
+	getter := test.NewMockGetter(t)
+	ids := test.NewMockSource(t)
+
+	ids.EXPECT().Fetch(mock.Anything, getter).Return([]usecases.Metadata{
+		test.Metadata{SourceID: "169", TargetID: "69"},
+	}, usecases.ErrPartialFetch).Once()
+	getter.EXPECT().Get(mock.Anything, xmlURI).Return(data, nil).Once()
+
+	source := animelists.XML{
+		IDs: ids,
+		URI: xmlURI,
+	}
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.ErrorIs(t, err, usecases.ErrPartialFetch)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "169", got[0].GetSourceID())
+	assert.Equal(t, "81797", got[0].GetTargetID())
+}
+
+func TestXML_Fetch_unmodifiedIDs(t *testing.T) {
+	t.Parallel()
+
+	getter := test.NewMockGetter(t)
+	ids := test.NewMockSource(t)
+
+	// the XML gets requested regardless, which lets refreshes find its changes
+	ids.EXPECT().Fetch(mock.Anything, getter).Return(nil, usecases.ErrNotModified).Once()
+	getter.EXPECT().Get(mock.Anything, xmlURI).Return([]byte("<anime-list/>"), nil).Once()
+
+	source := animelists.XML{
+		IDs: ids,
+		URI: xmlURI,
+	}
+
+	got, err := source.Fetch(t.Context(), getter)
+	require.ErrorIs(t, err, usecases.ErrNotModified)
+
+	assert.Nil(t, got)
+}
+
+func TestXML_Fetch_refresh(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+
+	files := map[string]string{
+		"/ids.json":       `[{"anilist_id":101,"anidb_id":1,"thetvdb_id":72025}]`,
+		"/anime-list.xml": `<anime-list><anime anidbid="1" tvdbid="72025" defaulttvdbseason="1"/></anime-list>`,
+	}
+
+	// serves files with their content as ETag
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		data := files[r.URL.Path]
+		mutex.Unlock()
+
+		etag := strconv.Quote(data)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+
+		_, err := w.Write([]byte(data))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	idsURI := server.URL + "/ids.json"
+	store := test.NewMockStore(t)
+	seasonOne := uint64(1)
+	seasonTwo := uint64(2)
+
+	store.EXPECT().ReplaceMediaBulk(mock.Anything, []*entities.Media{
+		{SourceID: "101", TargetID: "72025", TargetSeason: &seasonOne},
+	}).Return(nil).Once()
+	store.EXPECT().ReplaceMediaBulk(mock.Anything, []*entities.Media{
+		{SourceID: "101", TargetID: "72025", TargetSeason: &seasonTwo},
+	}).Return(nil).Once()
+
+	// the TVDB source shares the IDs URI, as the Fribb lists do
+	mediaLister := usecases.MediaList{
+		Tracker: test.NewMockTracker(t),
+		Source: &sources.Priority{
+			Sources: []usecases.Source{
+				&animelists.XML{
+					IDs: &sources.Priority{
+						Sources: []usecases.Source{
+							sources.JSON[animelists.Anilist2AniDBMetadata](idsURI),
+						},
+					},
+					URI: server.URL + "/anime-list.xml",
+				},
+				sources.JSON[animelists.Anilist2TVDBMetadata](idsURI),
+			},
+		},
+		Store: store,
+		Prune: true,
+	}
+
+	getter := usecases.ConditionalHTTPGetter(server.Client(), memory.New(), "anilist")
+
+	require.NoError(t, mediaLister.Refresh(t.Context(), getter))
+
+	// nothing changed
+	require.NoError(t, mediaLister.Refresh(t.Context(), getter))
+
+	// only the XML changed
+	mutex.Lock()
+	files["/anime-list.xml"] = `<anime-list><anime anidbid="1" tvdbid="72025" defaulttvdbseason="2"/></anime-list>`
+	mutex.Unlock()
+
+	require.NoError(t, mediaLister.Refresh(t.Context(), getter))
+}
+
+func TestXML_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	conflicts := []entities.MappingConflict{
+		{
+			SourceID: "101",
+			Targets: []entities.MappingTarget{
+				{Provider: "fribb", TargetID: "1"},
+				{Provider: "manami", TargetID: "2"},
+			},
+		},
+	}
+
+	ids := struct {
+		*test.MockSource
+		*test.MockConflictReporter
+	}{
+		MockSource:           test.NewMockSource(t),
+		MockConflictReporter: test.NewMockConflictReporter(t),
+	}
+	ids.MockConflictReporter.EXPECT().Conflicts().Return(conflicts).Once()
+
+	source := animelists.XML{IDs: ids, URI: xmlURI}
+	assert.Equal(t, conflicts, source.Conflicts())
+
+	// ID sources that merge nothing have no conflicts
+	source = animelists.XML{IDs: test.NewMockSource(t), URI: xmlURI}
+	assert.Empty(t, source.Conflicts())
+}
+
+func TestXML_Fetch_invalid(t *testing.T) {
+	t.Parallel()
+
+	source := animelists.XML{URI: xmlURI}
+
+	got, err := source.Fetch(t.Context(), nil)
+	require.ErrorIs(t, err, usecases.ErrStatusInternal)
+
+	assert.Nil(t, got)
+
+	got, err = source.Fetch(t.Context(), test.NewMockGetter(t))
+	require.ErrorIs(t, err, usecases.ErrStatusFailedPrecondition)
+
+	assert.Nil(t, got)
+}
+
+func TestAnime(t *testing.T) {
+	t.Parallel()
+
+	season := uint64(2)
+
+	tests := []struct {
+		wantSeason *uint64
+		name       string
+		anime      animelists.Anime
+		wantOffset int64
+		wantValid  bool
+	}{
+		{
+			name: "season",
+			anime: animelists.Anime{
+				AnidbID:           "2",
+				TvdbID:            "72025",
+				DefaultTvdbSeason: "2",
+				EpisodeOffset:     "13",
+			},
+			wantSeason: &season,
+			wantOffset: 13,
+			wantValid:  true,
+		},
+		{
+			name: "absolute",
+			anime: animelists.Anime{
+				AnidbID:           "69",
+				TvdbID:            "81797",
+				DefaultTvdbSeason: "a",
+			},
+			wantSeason: nil,
+			wantOffset: 0,
+			wantValid:  true,
+		},
+		{
+			name: "movie",
+			anime: animelists.Anime{
+				AnidbID: "5",
+				TvdbID:  "movie",
+			},
+			wantSeason: nil,
+			wantOffset: 0,
+			wantValid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantSeason, tt.anime.GetTargetSeason())
+			assert.Equal(t, tt.wantOffset, tt.anime.GetEpisodeOffset())
+			assert.Equal(t, tt.wantValid, tt.anime.Valid())
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
+	"github.com/goccy/go-json"
 	telemetry "github.com/wwmoraes/gotell"
 
 	"github.com/wwmoraes/anilistarr/internal/entities"
@@ -14,9 +17,21 @@ import (
 	"github.com/wwmoraes/anilistarr/pkg/with"
 )
 
+const (
+	// metaMedia marks store entries, which tells those apart from cache ones
+	// that share the instance.
+	metaMedia byte = 1
+	// metaToken marks token entries.
+	metaToken byte = 2
+
+	tokenIDPrefix   = "token:id:"
+	tokenNamePrefix = "token:name:"
+)
+
 var (
-	_ usecases.Cache = (*Badger)(nil)
-	_ usecases.Store = (*Badger)(nil)
+	_ usecases.ExpiringCache = (*Badger)(nil)
+	_ usecases.Store         = (*Badger)(nil)
+	_ usecases.TokenStore    = (*Badger)(nil)
 )
 
 // Options re-exports the upstream [badger.Options] so consumers don't
@@ -26,10 +41,18 @@ type Options = badger.Options
 // Badger provides a BadgerDB-backed driver that implements [adapters.Cache]
 // and [usecases.Store], making it easier to store and retrieve typed entries.
 //
-// Its store part uses [entities.Media.SourceID] as key. For its cache part it
+// Its store part uses [entities.Media.SourceID] as key and the target ID as
+// value. Medias with a target season are stored as JSON instead, which keeps
+// values written before seasons existed readable. For its cache part it
 // makes no assumptions about keys, using whatever the caller passes as the key
 // parameter. Thus a single instance may serve as both cache and store as long
-// as the caller prevents key conflicts.
+// as the caller prevents key conflicts. Store entries carry a marker, so that
+// replacing medias leaves cache entries alone; entries stored by versions
+// before the marker existed get dropped only after [Badger.MarkMedia] marks
+// those.
+//
+// Tokens are stored as JSON keyed by the user ID, along with an entry that
+// points the lowercase user name to it.
 type Badger struct {
 	db *badger.DB
 }
@@ -78,10 +101,22 @@ func (client *Badger) Close() error {
 // May rarely return [usecases.ErrStatusInternal] if the underlying badgerDB has
 // any problems.
 func (client *Badger) GetString(ctx context.Context, key string) (string, error) {
+	value, _, err := client.GetStringTTL(ctx, key)
+
+	return value, err
+}
+
+// GetStringTTL retrieves value for key and the time left until it expires, if
+// it is set. Returns [usecases.ErrStatusNotFound] otherwise. Expiration times
+// have a granularity of seconds.
+func (client *Badger) GetStringTTL(ctx context.Context, key string) (string, time.Duration, error) {
 	_, span := telemetry.Start(ctx)
 	defer span.End()
 
-	var value string
+	var (
+		value string
+		ttl   time.Duration
+	)
 
 	err := client.db.View(func(txn *badger.Txn) error {
 		data, err := txn.Get([]byte(key))
@@ -89,12 +124,23 @@ func (client *Badger) GetString(ctx context.Context, key string) (string, error)
 			return convertError(err)
 		}
 
+		if expiresAt := data.ExpiresAt(); expiresAt > 0 {
+			//nolint:gosec // Unix times are way below the int64 limit
+			ttl = time.Until(time.Unix(int64(expiresAt), 0))
+			if ttl <= 0 {
+				return usecases.ErrStatusNotFound
+			}
+		}
+
 		value = itemValueAsString(data)
 
 		return nil
 	})
+	if err != nil {
+		return "", 0, span.Assert(err)
+	}
 
-	return value, span.Assert(err)
+	return value, ttl, span.Assert(nil)
 }
 
 // SetString stores value for key. It overrides any previously stored value.
@@ -174,8 +220,7 @@ func (client *Badger) PutMedia(ctx context.Context, media *entities.Media) error
 	}
 
 	return span.Assert(client.db.Update(func(txn *badger.Txn) error {
-		// TODO join with usecases errors
-		return txn.Set([]byte(media.SourceID), []byte(media.TargetID))
+		return setMedia(txn, media)
 	}))
 }
 
@@ -187,14 +232,39 @@ func (client *Badger) PutMediaBulk(ctx context.Context, medias []*entities.Media
 	defer span.End()
 
 	return span.Assert(client.db.Update(func(txn *badger.Txn) error {
-		var err error
+		for _, media := range medias {
+			err := setMedia(txn, media)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+}
+
+// ReplaceMediaBulk stores multiple media entries and deletes all others in the
+// store. This happens within a single transaction, so either the previous or
+// the new entries are visible at any time. An error means no changes were made
+// to the data.
+func (client *Badger) ReplaceMediaBulk(ctx context.Context, medias []*entities.Media) error {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	return span.Assert(client.db.Update(func(txn *badger.Txn) error {
+		keep := make(map[string]bool, len(medias))
 
 		for _, media := range medias {
-			if !media.Valid() {
-				return usecases.ErrStatusInvalidArgument
+			err := setMedia(txn, media)
+			if err != nil {
+				return err
 			}
 
-			err = txn.Set([]byte(media.SourceID), []byte(media.TargetID))
+			keep[media.SourceID] = true
+		}
+
+		for _, key := range staleMediaKeys(txn, keep) {
+			err := txn.Delete(key)
 			if err != nil {
 				return convertError(err)
 			}
@@ -204,6 +274,181 @@ func (client *Badger) PutMediaBulk(ctx context.Context, medias []*entities.Media
 	}))
 }
 
+// MarkMedia marks all unmarked entries as store ones, which are those stored by
+// versions before the marker existed. This allows replacing those as well. It
+// must be used on store-only instances, as it marks any cache entries as well.
+func (client *Badger) MarkMedia(ctx context.Context) error {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	var entries []*badger.Entry
+
+	err := client.db.View(func(txn *badger.Txn) error {
+		var err error
+
+		entries, err = unmarkedEntries(txn)
+
+		return err
+	})
+	if err != nil || len(entries) == 0 {
+		return span.Assert(err)
+	}
+
+	// legacy stores may be too big for a single transaction
+	batch := client.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	for _, entry := range entries {
+		err = batch.SetEntry(entry)
+		if err != nil {
+			return span.Assert(convertError(err))
+		}
+	}
+
+	return span.Assert(convertError(batch.Flush()))
+}
+
+// GetToken retrieves the token of an user by their ID.
+func (client *Badger) GetToken(ctx context.Context, userID string) (*entities.Token, error) {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	var token entities.Token
+
+	err := client.db.View(tokenGetter(userID, &token))
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	return &token, span.Assert(nil)
+}
+
+// GetTokenByName retrieves the token of an user by their name.
+func (client *Badger) GetTokenByName(ctx context.Context, name string) (*entities.Token, error) {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	var token entities.Token
+
+	err := client.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(tokenNameKey(name))
+		if err != nil {
+			return convertError(err)
+		}
+
+		return tokenGetter(itemValueAsString(item), &token)(txn)
+	})
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	return &token, span.Assert(nil)
+}
+
+// PutToken stores the token of an user, replacing any previous one. It errors
+// if the token is invalid.
+func (client *Badger) PutToken(ctx context.Context, token *entities.Token) error {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if !token.Valid() {
+		return span.Assert(usecases.ErrStatusInvalidArgument)
+	}
+
+	value, err := json.Marshal(token)
+	if err != nil {
+		return span.Assert(errors.Join(usecases.ErrStatusInvalidArgument, err))
+	}
+
+	return span.Assert(client.db.Update(func(txn *badger.Txn) error {
+		// users may have renamed themselves since
+		var previous entities.Token
+
+		err := tokenGetter(token.UserID, &previous)(txn)
+		if err == nil {
+			err = convertError(txn.Delete(tokenNameKey(previous.UserName)))
+		}
+
+		if err != nil && !errors.Is(err, usecases.ErrStatusNotFound) {
+			return err
+		}
+
+		err = txn.SetEntry(badger.NewEntry([]byte(tokenIDPrefix+token.UserID), value).WithMeta(metaToken))
+		if err != nil {
+			return convertError(err)
+		}
+
+		entry := badger.NewEntry(tokenNameKey(token.UserName), []byte(token.UserID)).WithMeta(metaToken)
+
+		return convertError(txn.SetEntry(entry))
+	}))
+}
+
+// setMedia validates and stores a media within a transaction.
+func setMedia(txn *badger.Txn, media *entities.Media) error {
+	if !media.Valid() {
+		return usecases.ErrStatusInvalidArgument
+	}
+
+	value, err := mediaValue(media)
+	if err != nil {
+		return err
+	}
+
+	entry := badger.NewEntry([]byte(media.SourceID), value).WithMeta(metaMedia)
+
+	return convertError(txn.SetEntry(entry))
+}
+
+// staleMediaKeys lists the keys of store entries that are not in keep.
+func staleMediaKeys(txn *badger.Txn, keep map[string]bool) [][]byte {
+	options := badger.DefaultIteratorOptions
+	options.PrefetchValues = false
+
+	iterator := txn.NewIterator(options)
+	defer iterator.Close()
+
+	keys := [][]byte{}
+
+	for iterator.Rewind(); iterator.Valid(); iterator.Next() {
+		item := iterator.Item()
+
+		if item.UserMeta() != metaMedia || keep[string(item.Key())] {
+			continue
+		}
+
+		keys = append(keys, item.KeyCopy(nil))
+	}
+
+	return keys
+}
+
+// unmarkedEntries lists copies of the entries without a marker, marked as
+// store ones.
+func unmarkedEntries(txn *badger.Txn) ([]*badger.Entry, error) {
+	iterator := txn.NewIterator(badger.DefaultIteratorOptions)
+	defer iterator.Close()
+
+	entries := []*badger.Entry{}
+
+	for iterator.Rewind(); iterator.Valid(); iterator.Next() {
+		item := iterator.Item()
+
+		if item.UserMeta() != 0 {
+			continue
+		}
+
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		entries = append(entries, badger.NewEntry(item.KeyCopy(nil), value).WithMeta(metaMedia))
+	}
+
+	return entries, nil
+}
+
 func mediaGetter(id string, media *entities.Media) func(txn *badger.Txn) error {
 	return func(txn *badger.Txn) error {
 		item, err := txn.Get([]byte(id))
@@ -211,13 +456,63 @@ func mediaGetter(id string, media *entities.Media) func(txn *badger.Txn) error {
 			return convertError(err)
 		}
 
+		value := itemValueAsString(item)
+
+		if strings.HasPrefix(value, "{") {
+			err = json.Unmarshal([]byte(value), media)
+			if err != nil {
+				return errors.Join(usecases.ErrStatusDataLoss, err)
+			}
+		} else {
+			media.TargetID = value
+		}
+
 		media.SourceID = id
-		media.TargetID = itemValueAsString(item)
 
 		return nil
 	}
 }
 
+func tokenGetter(userID string, token *entities.Token) func(txn *badger.Txn) error {
+	return func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(tokenIDPrefix + userID))
+		if err != nil {
+			return convertError(err)
+		}
+
+		err = json.Unmarshal([]byte(itemValueAsString(item)), token)
+		if err != nil {
+			return errors.Join(usecases.ErrStatusDataLoss, err)
+		}
+
+		return nil
+	}
+}
+
+func tokenNameKey(name string) []byte {
+	return []byte(tokenNamePrefix + strings.ToLower(name))
+}
+
+// mediaValue encodes the store value of a media. It is the plain target ID
+// unless the media has a target season.
+func mediaValue(media *entities.Media) ([]byte, error) {
+	if media.TargetSeason == nil {
+		return []byte(media.TargetID), nil
+	}
+
+	value, err := json.Marshal(&entities.Media{
+		// the source ID is the key already
+		SourceID:     "",
+		TargetID:     media.TargetID,
+		TargetSeason: media.TargetSeason,
+	})
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusInvalidArgument, err)
+	}
+
+	return value, nil
+}
+
 func itemValueAsString(item *badger.Item) string {
 	var value string
 
@@ -20,6 +20,7 @@ func TestBadger(t *testing.T) {
 	t.Parallel()
 
 	ctx := t.Context()
+	season := uint64(2)
 	mediaA := entities.Media{
 		SourceID: "foo",
 		TargetID: "bar",
@@ -29,8 +30,9 @@ func TestBadger(t *testing.T) {
 		TargetID: "qux",
 	}
 	mediaC := entities.Media{
-		SourceID: "quux",
-		TargetID: "corge",
+		SourceID:     "quux",
+		TargetID:     "corge",
+		TargetSeason: &season,
 	}
 	bulkMedia := []*entities.Media{
 		&mediaB,
@@ -104,6 +106,36 @@ func TestBadger(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestBadger_GetStringTTL(t *testing.T) {
+	t.Parallel()
+
+	client, err := badger.New(
+		filepath.Join(t.TempDir(), "badger"),
+		badger.WithLogger(&badger.Logr{
+			Logger: logr.Discard(),
+		}),
+	)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	err = client.SetString(t.Context(), "fresh", "foo", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	err = client.SetString(t.Context(), "forever", "bar")
+	require.NoError(t, err)
+
+	got, ttl, err := client.GetStringTTL(t.Context(), "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Minute))
+
+	got, ttl, err = client.GetStringTTL(t.Context(), "forever")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+	assert.Zero(t, ttl)
+}
+
 func TestNew_error(t *testing.T) {
 	t.Parallel()
 
@@ -231,6 +263,95 @@ func TestBadger_PutMediaBulk_error(t *testing.T) {
 	}
 }
 
+func TestBadger_ReplaceMediaBulk(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	client, err := badger.New(t.TempDir(), badger.WithInMemory(true))
+	require.NoError(t, err)
+
+	err = client.PutMediaBulk(ctx, []*entities.Media{
+		{SourceID: "1", TargetID: "90"},
+		{SourceID: "2", TargetID: "92"},
+	})
+	require.NoError(t, err)
+
+	err = client.SetString(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	err = client.ReplaceMediaBulk(ctx, []*entities.Media{
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "3", TargetID: "93"},
+	})
+	require.NoError(t, err)
+
+	got, err := client.GetMediaBulk(ctx, []string{"1", "3"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*entities.Media{
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "3", TargetID: "93"},
+	}, got)
+
+	_, err = client.GetMedia(ctx, "2")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	// cache entries are not medias
+	gotString, err := client.GetString(ctx, "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", gotString)
+
+	// invalid entries roll back the whole replacement
+	err = client.ReplaceMediaBulk(ctx, []*entities.Media{
+		{SourceID: "4", TargetID: "94"},
+		{SourceID: "5", TargetID: ""},
+	})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	got, err = client.GetMediaBulk(ctx, []string{"1", "3"})
+	require.NoError(t, err)
+
+	assert.Len(t, got, 2)
+
+	require.NoError(t, client.Close())
+}
+
+func TestBadger_MarkMedia(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	client, err := badger.New(t.TempDir(), badger.WithInMemory(true))
+	require.NoError(t, err)
+
+	// previous versions stored medias without a marker
+	require.NoError(t, client.SetString(ctx, "1", "91"))
+	require.NoError(t, client.SetString(ctx, "2", "92"))
+
+	require.NoError(t, client.PutToken(ctx, &entities.Token{
+		UserID:      "1",
+		UserName:    "foo",
+		AccessToken: "bar",
+	}))
+
+	require.NoError(t, client.MarkMedia(ctx))
+
+	err = client.ReplaceMediaBulk(ctx, []*entities.Media{{SourceID: "1", TargetID: "91"}})
+	require.NoError(t, err)
+
+	_, err = client.GetMedia(ctx, "2")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	got, err := client.GetToken(ctx, "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", got.AccessToken)
+
+	require.NoError(t, client.Close())
+}
+
 func TestBadger_GetMedia(t *testing.T) {
 	t.Parallel()
 
@@ -271,3 +392,56 @@ func TestBadger_GetMedia(t *testing.T) {
 		})
 	}
 }
+
+func TestBadger_Token(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	token := entities.Token{
+		ExpiresAt:   time.Now().Add(time.Hour).UTC(),
+		UserID:      "1",
+		UserName:    "Foo",
+		AccessToken: "bar",
+	}
+
+	client, err := badger.New(t.TempDir(), badger.WithInMemory(true))
+	require.NoError(t, err)
+
+	_, err = client.GetToken(ctx, token.UserID)
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	_, err = client.GetTokenByName(ctx, token.UserName)
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	err = client.PutToken(ctx, &entities.Token{UserID: "2"})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	require.NoError(t, client.PutToken(ctx, &token))
+
+	got, err := client.GetToken(ctx, token.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, &token, got)
+
+	got, err = client.GetTokenByName(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, &token, got)
+
+	// tokens are not medias
+	require.NoError(t, client.ReplaceMediaBulk(ctx, nil))
+
+	// renamed users no longer resolve by their previous name
+	renamed := token
+	renamed.UserName = "baz"
+	renamed.AccessToken = "qux"
+
+	require.NoError(t, client.PutToken(ctx, &renamed))
+
+	_, err = client.GetTokenByName(ctx, "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	got, err = client.GetTokenByName(ctx, "BAZ")
+	require.NoError(t, err)
+	assert.Equal(t, &renamed, got)
+
+	require.NoError(t, client.Close())
+}
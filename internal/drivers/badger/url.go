@@ -0,0 +1,44 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+)
+
+// Scheme is the URL scheme of BadgerDB drivers.
+const Scheme = "badger"
+
+// Register adds BadgerDB as both a store and cache driver.
+func Register(drivers *registry.Drivers) {
+	registry.Register(&drivers.Stores, Scheme, StoreFromURL)
+	registry.Register(&drivers.Caches, Scheme, FromURL)
+}
+
+// FromURL opens a BadgerDB at the directory within uri, e.g.
+// badger:///var/lib/anilistarr/cache. It logs through the context logger.
+func FromURL(ctx context.Context, uri *url.URL) (*Badger, error) {
+	return New(registry.Path(uri), WithLogger(&Logr{
+		Logger: telemetry.Logr(ctx),
+	}))
+}
+
+// StoreFromURL opens a BadgerDB store as [FromURL] does, and marks the media
+// entries stored by previous versions, which allows replacing those.
+func StoreFromURL(ctx context.Context, uri *url.URL) (*Badger, error) {
+	client, err := FromURL(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.MarkMedia(ctx)
+	if err != nil {
+		return nil, errors.Join(err, client.Close())
+	}
+
+	return client, nil
+}
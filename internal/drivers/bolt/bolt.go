@@ -2,9 +2,13 @@
 package bolt
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	telemetry "github.com/wwmoraes/gotell"
 	"go.etcd.io/bbolt"
@@ -12,11 +16,19 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
-// BucketName is the internal bucket name that the driver uses. Consumers should
-// avoid re-using the same bucket name.
-const BucketName = "anilistarr"
+const (
+	// BucketName is the internal bucket name that the driver uses. Consumers
+	// should avoid re-using the same bucket name.
+	BucketName = "anilistarr"
+	// ExpiryBucketName is the internal bucket that holds the expiration time of
+	// entries with a TTL. Consumers should avoid re-using the same bucket name.
+	ExpiryBucketName = "anilistarr-expiry"
 
-var _ usecases.Cache = (*Bolt)(nil)
+	expirySize    = 8
+	sweepInterval = 10 * time.Minute
+)
+
+var _ usecases.ExpiringCache = (*Bolt)(nil)
 
 // Options re-rexports the upstream [bbolt.Options] so consumers don't need
 // to have an extra import.
@@ -24,8 +36,16 @@ type Options = bbolt.Options
 
 // Bolt provides a BoltDB-backed cache driver. It uses a single constant bucket
 // name defined by [BucketName].
+//
+// Entries with a TTL have their expiration time in the [ExpiryBucketName]
+// bucket, which keeps values in the former format. Expired entries are
+// missing on retrieval, and a background sweeper deletes them periodically
+// unless the database is read-only.
 type Bolt struct {
-	db *bbolt.DB
+	db       *bbolt.DB
+	done     chan struct{}
+	sweeping sync.WaitGroup
+	stopping sync.Once
 }
 
 // New creates a Bolt-based Cache
@@ -41,17 +61,38 @@ func New(path string, options *Options) (*Bolt, error) {
 		db.Update(func(tx *bbolt.Tx) error {
 			//nolint:errcheck // known bucket name + open and RW transaction
 			tx.CreateBucketIfNotExists([]byte(BucketName))
+			//nolint:errcheck // known bucket name + open and RW transaction
+			tx.CreateBucketIfNotExists([]byte(ExpiryBucketName))
 
 			return nil
 		})
 	}
 
-	return &Bolt{db}, nil
+	cache := &Bolt{
+		db:       db,
+		done:     make(chan struct{}),
+		sweeping: sync.WaitGroup{},
+		stopping: sync.Once{},
+	}
+
+	if !db.IsReadOnly() {
+		cache.sweeping.Add(1)
+
+		go cache.sweep(sweepInterval)
+	}
+
+	return cache, nil
 }
 
-// Close closes the underlying BoltDB handler, finishing up transactions and
-// cleaning up resources.
+// Close stops the sweeper and closes the underlying BoltDB handler, finishing
+// up transactions and cleaning up resources.
+// It is safe to call more than once.
 func (cache *Bolt) Close() error {
+	cache.stopping.Do(func() {
+		close(cache.done)
+		cache.sweeping.Wait()
+	})
+
 	return usecases.ErrorJoinIf(
 		usecases.ErrStatusUnknown,
 		cache.db.Close(),
@@ -59,41 +100,66 @@ func (cache *Bolt) Close() error {
 }
 
 // GetString retrieves a value stored in the underlying BoltDB. It returns
-// [usecases.ErrStatusNotFound] if key isn't in the cache.
+// [usecases.ErrStatusNotFound] if key isn't in the cache or expired.
 func (cache *Bolt) GetString(ctx context.Context, key string) (string, error) {
+	value, _, err := cache.GetStringTTL(ctx, key)
+
+	return value, err
+}
+
+// GetStringTTL retrieves value for key and the time left until it expires, if
+// it is set and not expired. Returns [usecases.ErrStatusNotFound] otherwise.
+func (cache *Bolt) GetStringTTL(ctx context.Context, key string) (string, time.Duration, error) {
 	_, span := telemetry.Start(ctx)
 	defer span.End()
 
-	var value string
+	var (
+		value string
+		ttl   time.Duration
+	)
+
+	now := time.Now()
 
 	err := cache.db.View(func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
+		// databases from former versions lack the expiry bucket until written
+		expiry := tx.Bucket([]byte(ExpiryBucketName))
+
+		var expiresAt []byte
+
+		if expiry != nil {
+			expiresAt = expiry.Get([]byte(key))
+		}
 
 		data := bucket.Get([]byte(key))
-		if data == nil {
+		if data == nil || expired(expiresAt, now) {
 			return usecases.ErrStatusNotFound
 		}
 
 		value = string(data)
+		ttl = remaining(expiresAt, now)
 
 		return nil
 	})
 	if err != nil {
-		return "", span.Assert(fmt.Errorf("failed to get string: %w", err))
+		return "", 0, span.Assert(fmt.Errorf("failed to get string: %w", err))
 	}
 
-	return value, span.Assert(nil)
+	return value, ttl, span.Assert(nil)
 }
 
-// SetString stores a key and value in the underlying BoltDB. It returns
+// SetString stores a key and value in the underlying BoltDB. Set an expiration
+// time by using [usecases.WithTTL] option.
 func (cache *Bolt) SetString(
 	ctx context.Context,
 	key, value string,
-	_ ...usecases.CacheOption,
+	options ...usecases.CacheOption,
 ) error {
 	_, span := telemetry.Start(ctx)
 	defer span.End()
 
+	params := usecases.NewCacheOptions(options...)
+
 	return span.Assert(cache.db.Update(func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket([]byte(BucketName))
 
@@ -102,6 +168,102 @@ func (cache *Bolt) SetString(
 			return errors.Join(usecases.ErrStatusInvalidArgument, err)
 		}
 
-		return nil
+		expiry, err := tx.CreateBucketIfNotExists([]byte(ExpiryBucketName))
+		if err != nil {
+			return errors.Join(usecases.ErrStatusFailedPrecondition, err)
+		}
+
+		if params.TTL <= 0 {
+			return usecases.ErrorJoinIf(usecases.ErrStatusAborted, expiry.Delete([]byte(key)))
+		}
+
+		//nolint:gosec // expiration times are after the epoch
+		expiresAt := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(params.TTL).UnixNano()))
+
+		return usecases.ErrorJoinIf(usecases.ErrStatusAborted, expiry.Put([]byte(key), expiresAt))
 	}))
 }
+
+// Sweep deletes all expired entries.
+func (cache *Bolt) Sweep(ctx context.Context) error {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	now := time.Now()
+
+	return span.Assert(cache.db.Update(func(tx *bbolt.Tx) error {
+		return deleteExpired(tx, now)
+	}))
+}
+
+// deleteExpired deletes the entries that expired up to now.
+func deleteExpired(tx *bbolt.Tx, now time.Time) error {
+	expiry := tx.Bucket([]byte(ExpiryBucketName))
+	if expiry == nil {
+		return nil
+	}
+
+	bucket := tx.Bucket([]byte(BucketName))
+	cursor := expiry.Cursor()
+
+	for key, expiresAt := cursor.First(); key != nil; {
+		if !expired(expiresAt, now) {
+			key, expiresAt = cursor.Next()
+
+			continue
+		}
+
+		// deleting leaves the cursor off its position, so the sweep resumes by
+		// seeking the key
+		key = bytes.Clone(key)
+
+		err := bucket.Delete(key)
+		if err != nil {
+			return errors.Join(usecases.ErrStatusAborted, err)
+		}
+
+		err = cursor.Delete()
+		if err != nil {
+			return errors.Join(usecases.ErrStatusAborted, err)
+		}
+
+		key, expiresAt = cursor.Seek(key)
+	}
+
+	return nil
+}
+
+// sweep deletes expired entries every interval until the cache closes.
+func (cache *Bolt) sweep(interval time.Duration) {
+	defer cache.sweeping.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cache.done:
+			return
+		case <-ticker.C:
+			//nolint:errcheck // failures are traced and retried on the next tick
+			_ = cache.Sweep(context.Background())
+		}
+	}
+}
+
+// expired returns true if expiresAt is an expiration time up to now. Entries
+// without one never expire.
+func expired(expiresAt []byte, now time.Time) bool {
+	return len(expiresAt) == expirySize && remaining(expiresAt, now) <= 0
+}
+
+// remaining returns the time left from now until an expiration time, or zero
+// if there is none.
+func remaining(expiresAt []byte, now time.Time) time.Duration {
+	if len(expiresAt) != expirySize {
+		return 0
+	}
+
+	//nolint:gosec // expiration times are after the epoch
+	return time.Unix(0, int64(binary.BigEndian.Uint64(expiresAt))).Sub(now)
+}
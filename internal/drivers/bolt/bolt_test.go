@@ -7,6 +7,7 @@ import (
 	"io"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -226,6 +227,116 @@ func TestBolt_SetString(t *testing.T) {
 	}
 }
 
+func TestBolt_SetString_ttl(t *testing.T) {
+	t.Parallel()
+
+	cache, err := bolt.New(path.Join(t.TempDir(), "ttl"), nil)
+	require.NoError(t, err)
+
+	defer closeValue(t, cache)
+
+	err = cache.SetString(t.Context(), "expired", "foo", usecases.WithTTL(time.Nanosecond))
+	require.NoError(t, err)
+
+	err = cache.SetString(t.Context(), "fresh", "bar", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	err = cache.SetString(t.Context(), "forever", "baz")
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	_, err = cache.GetString(t.Context(), "expired")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	got, err := cache.GetString(t.Context(), "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+
+	// setting without a TTL makes it last forever
+	err = cache.SetString(t.Context(), "expired", "foo")
+	require.NoError(t, err)
+
+	got, err = cache.GetString(t.Context(), "expired")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got)
+}
+
+func TestBolt_GetStringTTL(t *testing.T) {
+	t.Parallel()
+
+	cache, err := bolt.New(path.Join(t.TempDir(), "ttl"), nil)
+	require.NoError(t, err)
+
+	defer closeValue(t, cache)
+
+	err = cache.SetString(t.Context(), "fresh", "foo", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	err = cache.SetString(t.Context(), "forever", "bar")
+	require.NoError(t, err)
+
+	got, ttl, err := cache.GetStringTTL(t.Context(), "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Minute))
+
+	got, ttl, err = cache.GetStringTTL(t.Context(), "forever")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+	assert.Zero(t, ttl)
+
+	_, _, err = cache.GetStringTTL(t.Context(), "missing")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+}
+
+func TestBolt_Sweep(t *testing.T) {
+	t.Parallel()
+
+	cachePath := path.Join(t.TempDir(), "sweep")
+
+	cache, err := bolt.New(cachePath, nil)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c"} {
+		err = cache.SetString(t.Context(), key, key, usecases.WithTTL(time.Nanosecond))
+		require.NoError(t, err)
+	}
+
+	err = cache.SetString(t.Context(), "fresh", "bar", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	err = cache.Sweep(t.Context())
+	require.NoError(t, err)
+
+	closeValue(t, cache)
+
+	db, err := bbolt.Open(cachePath, 0o600, nil)
+	require.NoError(t, err)
+
+	defer closeValue(t, db)
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		assert.Equal(t, 1, tx.Bucket([]byte(bolt.BucketName)).Stats().KeyN)
+		assert.Equal(t, 1, tx.Bucket([]byte(bolt.ExpiryBucketName)).Stats().KeyN)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestBolt_Close_twice(t *testing.T) {
+	t.Parallel()
+
+	cache, err := bolt.New(path.Join(t.TempDir(), "bolt.db"), nil)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Close())
+	require.NoError(t, cache.Close())
+}
+
 func closeValue(tb testing.TB, closer io.Closer) {
 	tb.Helper()
 
@@ -0,0 +1,22 @@
+package bolt
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+)
+
+// Scheme is the URL scheme of BoltDB drivers.
+const Scheme = "bolt"
+
+// Register adds BoltDB as a cache driver.
+func Register(drivers *registry.Drivers) {
+	registry.Register(&drivers.Caches, Scheme, FromURL)
+}
+
+// FromURL opens a BoltDB at the file within uri, e.g.
+// bolt:///var/lib/anilistarr/cache.db.
+func FromURL(_ context.Context, uri *url.URL) (*Bolt, error) {
+	return New(registry.Path(uri), nil)
+}
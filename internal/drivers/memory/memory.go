@@ -0,0 +1,210 @@
+// Package memory provides an in-process cache driver with a bounded size, which
+// suits being the first tier of a chain of caches.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+	"github.com/wwmoraes/anilistarr/pkg/with"
+)
+
+// DefaultCapacity is the number of entries a cache holds by default.
+const DefaultCapacity = 4096
+
+var _ usecases.ExpiringCache = (*Cache)(nil)
+
+// Options contains optional settings for cache instances.
+type Options struct {
+	// Capacity is the maximum number of entries. Non-positive values mean
+	// [DefaultCapacity].
+	Capacity int
+}
+
+// Cache is an in-memory cache that holds up to a fixed number of entries. It
+// evicts the least recently used entry to make room for new ones. Expired
+// entries are missing on retrieval, and get evicted as they turn into the
+// least recently used ones. It is safe for concurrent use.
+type Cache struct {
+	entries map[string]*entry
+	// recency links entries from the most to the least recently used one in a
+	// ring, starting and ending on itself.
+	recency  *entry
+	capacity int
+	mutex    sync.Mutex
+}
+
+type entry struct {
+	expiresAt time.Time
+	previous  *entry
+	next      *entry
+	key       string
+	value     string
+}
+
+// WithCapacity sets the maximum number of entries.
+func WithCapacity(capacity int) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Capacity = capacity
+	})
+}
+
+// New creates an empty cache.
+func New(opts ...with.Option[Options]) *Cache {
+	options := with.Apply(Options{Capacity: DefaultCapacity}, opts...)
+
+	if options.Capacity <= 0 {
+		options.Capacity = DefaultCapacity
+	}
+
+	cache := &Cache{
+		entries:  make(map[string]*entry, options.Capacity),
+		recency:  new(entry),
+		capacity: options.Capacity,
+		mutex:    sync.Mutex{},
+	}
+
+	cache.recency.previous = cache.recency
+	cache.recency.next = cache.recency
+
+	return cache
+}
+
+// Close drops all entries.
+func (cache *Cache) Close() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	clear(cache.entries)
+
+	cache.recency.previous = cache.recency
+	cache.recency.next = cache.recency
+
+	return nil
+}
+
+// GetString retrieves value for key if it is set and not expired. Returns
+// [usecases.ErrStatusNotFound] otherwise.
+func (cache *Cache) GetString(ctx context.Context, key string) (string, error) {
+	value, _, err := cache.GetStringTTL(ctx, key)
+
+	return value, err
+}
+
+// GetStringTTL retrieves value for key and the time left until it expires, if
+// it is set and not expired. Returns [usecases.ErrStatusNotFound] otherwise.
+func (cache *Cache) GetStringTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	item, ok := cache.entries[key]
+	if !ok {
+		return "", 0, span.Assert(usecases.ErrStatusNotFound)
+	}
+
+	var ttl time.Duration
+
+	if !item.expiresAt.IsZero() {
+		ttl = time.Until(item.expiresAt)
+		if ttl <= 0 {
+			cache.remove(item)
+
+			return "", 0, span.Assert(usecases.ErrStatusNotFound)
+		}
+	}
+
+	cache.unlink(item)
+	cache.pushFront(item)
+
+	return item.value, ttl, span.Assert(nil)
+}
+
+// SetString stores value for key, evicting the least recently used entry if
+// the cache is full. It overrides any previously existing value. Set an
+// expiration time by using [usecases.WithTTL] option.
+func (cache *Cache) SetString(
+	ctx context.Context,
+	key, value string,
+	options ...usecases.CacheOption,
+) error {
+	_, span := telemetry.Start(ctx)
+	defer span.End()
+
+	params := usecases.NewCacheOptions(options...)
+
+	var expiresAt time.Time
+
+	if params.TTL > 0 {
+		expiresAt = time.Now().Add(params.TTL)
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	item, ok := cache.entries[key]
+	if ok {
+		item.value = value
+		item.expiresAt = expiresAt
+
+		cache.unlink(item)
+		cache.pushFront(item)
+
+		return span.Assert(nil)
+	}
+
+	for len(cache.entries) >= cache.capacity {
+		cache.remove(cache.recency.previous)
+	}
+
+	item = &entry{
+		expiresAt: expiresAt,
+		previous:  nil,
+		next:      nil,
+		key:       key,
+		value:     value,
+	}
+
+	cache.entries[key] = item
+	cache.pushFront(item)
+
+	return span.Assert(nil)
+}
+
+// Len returns the number of entries, including expired ones not evicted yet.
+func (cache *Cache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return len(cache.entries)
+}
+
+// remove drops an entry. The caller must hold the mutex.
+func (cache *Cache) remove(item *entry) {
+	cache.unlink(item)
+	delete(cache.entries, item.key)
+}
+
+// unlink takes an entry out of the recency ring. The caller must hold the
+// mutex.
+func (*Cache) unlink(item *entry) {
+	item.previous.next = item.next
+	item.next.previous = item.previous
+	item.previous = nil
+	item.next = nil
+}
+
+// pushFront links an entry as the most recently used one. The caller must hold
+// the mutex.
+func (cache *Cache) pushFront(item *entry) {
+	item.previous = cache.recency
+	item.next = cache.recency.next
+	item.next.previous = item
+	cache.recency.next = item
+}
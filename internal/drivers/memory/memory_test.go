@@ -0,0 +1,194 @@
+package memory_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/memory"
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+func TestCache_GetString(t *testing.T) {
+	t.Parallel()
+
+	cache := memory.New()
+
+	err := cache.SetString(t.Context(), "foo", "bar")
+	require.NoError(t, err)
+
+	got, err := cache.GetString(t.Context(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+
+	_, err = cache.GetString(t.Context(), "bar")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	require.NoError(t, cache.Close())
+
+	_, err = cache.GetString(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+}
+
+func TestCache_SetString_ttl(t *testing.T) {
+	t.Parallel()
+
+	cache := memory.New()
+
+	err := cache.SetString(t.Context(), "expired", "foo", usecases.WithTTL(time.Nanosecond))
+	require.NoError(t, err)
+
+	err = cache.SetString(t.Context(), "fresh", "bar", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	_, err = cache.GetString(t.Context(), "expired")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	got, err := cache.GetString(t.Context(), "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCache_GetStringTTL(t *testing.T) {
+	t.Parallel()
+
+	cache := memory.New()
+
+	err := cache.SetString(t.Context(), "fresh", "foo", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	err = cache.SetString(t.Context(), "forever", "bar")
+	require.NoError(t, err)
+
+	got, ttl, err := cache.GetStringTTL(t.Context(), "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Minute))
+
+	got, ttl, err = cache.GetStringTTL(t.Context(), "forever")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+	assert.Zero(t, ttl)
+}
+
+func TestCache_SetString_eviction(t *testing.T) {
+	t.Parallel()
+
+	cache := memory.New(memory.WithCapacity(2))
+
+	require.NoError(t, cache.SetString(t.Context(), "a", "1"))
+	require.NoError(t, cache.SetString(t.Context(), "b", "2"))
+
+	// reading a makes b the least recently used entry
+	_, err := cache.GetString(t.Context(), "a")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.SetString(t.Context(), "c", "3"))
+
+	_, err = cache.GetString(t.Context(), "b")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	for key, want := range map[string]string{"a": "1", "c": "3"} {
+		got, err := cache.GetString(t.Context(), key)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	// updating keeps the size
+	require.NoError(t, cache.SetString(t.Context(), "c", "4"))
+
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestCache_concurrent(t *testing.T) {
+	t.Parallel()
+
+	const workers = 8
+
+	cache := memory.New(memory.WithCapacity(16))
+
+	var wg sync.WaitGroup
+
+	for worker := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range 1000 {
+				key := strconv.Itoa((worker + index) % 32)
+
+				assert.NoError(t, cache.SetString(t.Context(), key, key))
+
+				value, err := cache.GetString(t.Context(), key)
+				if err == nil {
+					assert.Equal(t, key, value)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, cache.Len(), 16)
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	var drivers registry.Drivers
+
+	memory.Register(&drivers)
+
+	cache, err := drivers.Caches.New(t.Context(), "memory:?capacity=1")
+	require.NoError(t, err)
+	require.NoError(t, cache.Close())
+
+	_, err = drivers.Caches.New(t.Context(), "memory:?capacity=many")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
+
+func BenchmarkCache_GetString(b *testing.B) {
+	cache := memory.New(memory.WithCapacity(1024))
+
+	for index := range 1024 {
+		key := strconv.Itoa(index)
+		require.NoError(b, cache.SetString(b.Context(), key, key))
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		index := 0
+
+		for pb.Next() {
+			//nolint:errcheck // benchmarking
+			cache.GetString(b.Context(), strconv.Itoa(index%1024))
+			index++
+		}
+	})
+}
+
+func BenchmarkCache_SetString(b *testing.B) {
+	cache := memory.New(memory.WithCapacity(1024))
+
+	b.RunParallel(func(pb *testing.PB) {
+		index := 0
+
+		for pb.Next() {
+			// twice the capacity, so half of the sets evict
+			//nolint:errcheck // benchmarking
+			cache.SetString(b.Context(), strconv.Itoa(index%2048), "value", usecases.WithTTL(time.Hour))
+			index++
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+	"github.com/wwmoraes/anilistarr/pkg/with"
+)
+
+// Scheme is the URL scheme of in-memory drivers.
+const Scheme = "memory"
+
+// Register adds the in-memory cache as a cache driver.
+func Register(drivers *registry.Drivers) {
+	registry.Register(&drivers.Caches, Scheme, FromURL)
+}
+
+// FromURL creates a cache with the capacity set in the query of uri, if any,
+// e.g. memory:?capacity=1000.
+func FromURL(_ context.Context, uri *url.URL) (*Cache, error) {
+	opts := []with.Option[Options]{}
+
+	if uri.Query().Has("capacity") {
+		capacity, err := strconv.Atoi(uri.Query().Get("capacity"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid capacity: %w", usecases.ErrStatusInvalidArgument, err)
+		}
+
+		opts = append(opts, WithCapacity(capacity))
+	}
+
+	return New(opts...), nil
+}
@@ -0,0 +1,127 @@
+// Package overrides provides a file-backed [usecases.Overrides] that reloads
+// the mapping corrections whenever the file changes.
+package overrides
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v3"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+var _ usecases.Overrides = (*File)(nil)
+
+// File reads mapping overrides from a YAML or JSON file within FS. It parses
+// the file again only after its modification time or size changes, so edits
+// apply without a restart. A missing file means there are no overrides.
+//
+// Overrides are optional, so a file that fails to load only gets logged. The
+// last overrides loaded, if any, remain in use until the file is fixed.
+type File struct {
+	modTime   time.Time
+	FS        fs.FS
+	Path      string
+	overrides entities.MappingOverrides
+	size      int64
+	mutex     sync.Mutex
+}
+
+// New creates a [File] for the overrides file at path.
+func New(path string) *File {
+	var file File
+
+	file.FS = os.DirFS(filepath.Dir(path))
+	file.Path = filepath.Base(path)
+
+	return &file
+}
+
+// GetOverrides retrieves the overrides from the file, reloading it if it
+// changed since the last call.
+func (file *File) GetOverrides(ctx context.Context) (entities.MappingOverrides, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	log := telemetry.Logr(ctx).WithValues("path", file.Path)
+
+	info, err := fs.Stat(file.FS, file.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		file.overrides = entities.MappingOverrides{Mappings: nil, Exclude: nil}
+		file.modTime = time.Time{}
+		file.size = 0
+
+		return file.overrides, span.Assert(nil)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		log.Error(err, "failed to stat mapping overrides, keeping the last ones")
+
+		return file.overrides, span.Assert(nil)
+	}
+
+	if info.ModTime().Equal(file.modTime) && info.Size() == file.size {
+		return file.overrides, span.Assert(nil)
+	}
+
+	// a broken file is logged once, instead of on every call until it changes
+	file.modTime = info.ModTime()
+	file.size = info.Size()
+
+	overrides, err := file.load()
+	if err != nil {
+		span.RecordError(err)
+		log.Error(err, "failed to load mapping overrides, keeping the last ones")
+
+		return file.overrides, span.Assert(nil)
+	}
+
+	file.overrides = overrides
+
+	span.SetAttributes(
+		attribute.Int("mappings", len(overrides.Mappings)),
+		attribute.Int("exclusions", len(overrides.Exclude)),
+	)
+
+	log.Info("loaded mapping overrides",
+		"mappings", len(overrides.Mappings),
+		"exclusions", len(overrides.Exclude),
+	)
+
+	return overrides, span.Assert(nil)
+}
+
+// load reads and parses the overrides file. YAML is a superset of JSON, so it
+// handles both formats.
+func (file *File) load() (entities.MappingOverrides, error) {
+	var overrides entities.MappingOverrides
+
+	data, err := fs.ReadFile(file.FS, file.Path)
+	if err != nil {
+		return overrides, errors.Join(usecases.ErrStatusUnavailable, err)
+	}
+
+	err = yaml.Unmarshal(data, &overrides)
+	if err != nil {
+		return overrides, errors.Join(
+			usecases.ErrStatusFailedPrecondition,
+			fmt.Errorf("failed to parse overrides: %w", err),
+		)
+	}
+
+	return overrides, nil
+}
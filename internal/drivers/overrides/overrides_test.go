@@ -0,0 +1,161 @@
+package overrides_test
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/overrides"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+)
+
+const overridesPath = "overrides.yaml"
+
+func TestFile_GetOverrides(t *testing.T) {
+	t.Parallel()
+
+	want := entities.MappingOverrides{
+		Mappings: map[entities.SourceID]entities.TargetID{"1": "101", "2": "102"},
+		Exclude:  []entities.SourceID{"3"},
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "YAML",
+			data: "mappings:\n  1: 101\n  \"2\": \"102\"\nexclude:\n  - 3\n",
+		},
+		{
+			name: "JSON",
+			data: `{"mappings": {"1": "101", "2": "102"}, "exclude": ["3"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := overrides.File{
+				FS: fstest.MapFS{
+					overridesPath: &fstest.MapFile{Data: []byte(tt.data)},
+				},
+				Path: overridesPath,
+			}
+
+			got, err := file.GetOverrides(t.Context())
+			require.NoError(t, err)
+
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestFile_GetOverrides_reload(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		overridesPath: &fstest.MapFile{
+			Data:    []byte("mappings:\n  1: 101\n"),
+			ModTime: time.Unix(1, 0),
+		},
+	}
+
+	file := overrides.File{FS: fsys, Path: overridesPath}
+
+	got, err := file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[entities.SourceID]entities.TargetID{"1": "101"}, got.Mappings)
+
+	fsys[overridesPath] = &fstest.MapFile{
+		Data:    []byte("mappings:\n  1: 201\n"),
+		ModTime: time.Unix(2, 0),
+	}
+
+	got, err = file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[entities.SourceID]entities.TargetID{"1": "201"}, got.Mappings)
+
+	delete(fsys, overridesPath)
+
+	got, err = file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Empty(t, got.Mappings)
+}
+
+func TestFile_GetOverrides_keep_on_error(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		overridesPath: &fstest.MapFile{
+			Data:    []byte("exclude: [1]\n"),
+			ModTime: time.Unix(1, 0),
+		},
+	}
+
+	file := overrides.File{FS: fsys, Path: overridesPath}
+
+	_, err := file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	fsys[overridesPath] = &fstest.MapFile{
+		Data:    []byte("exclude: ["),
+		ModTime: time.Unix(2, 0),
+	}
+
+	got, err := file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, []entities.SourceID{"1"}, got.Exclude)
+
+	fsys[overridesPath] = &fstest.MapFile{
+		Data:    []byte("exclude: [2]\n"),
+		ModTime: time.Unix(3, 0),
+	}
+
+	got, err = file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, []entities.SourceID{"2"}, got.Exclude)
+}
+
+func TestFile_GetOverrides_malformed(t *testing.T) {
+	t.Parallel()
+
+	file := overrides.File{
+		FS: fstest.MapFS{
+			overridesPath: &fstest.MapFile{Data: []byte("mappings: [")},
+		},
+		Path: overridesPath,
+	}
+
+	got, err := file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Empty(t, got.Mappings)
+	assert.Empty(t, got.Exclude)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), overridesPath)
+
+	file := overrides.New(path)
+
+	assert.Equal(t, overridesPath, file.Path)
+
+	got, err := file.GetOverrides(t.Context())
+	require.NoError(t, err)
+
+	assert.Empty(t, got.Mappings)
+	assert.Empty(t, got.Exclude)
+}
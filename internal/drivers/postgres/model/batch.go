@@ -0,0 +1,72 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: batch.go
+
+package model
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrBatchAlreadyClosed = errors.New("batch already closed")
+)
+
+const putMedias = `-- name: PutMedias :batchexec
+INSERT INTO medias (source_id, target_id, target_season)
+VALUES ($1, $2, $3)
+ON CONFLICT (source_id, target_id) DO UPDATE
+SET target_season = EXCLUDED.target_season
+`
+
+type PutMediasBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type PutMediasParams struct {
+	SourceID     string
+	TargetID     string
+	TargetSeason pgtype.Int8
+}
+
+func (q *Queries) PutMedias(ctx context.Context, arg []PutMediasParams) *PutMediasBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.SourceID,
+			a.TargetID,
+			a.TargetSeason,
+		}
+		batch.Queue(putMedias, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &PutMediasBatchResults{br, len(arg), false}
+}
+
+func (b *PutMediasBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *PutMediasBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
@@ -0,0 +1,28 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package model
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Cache struct {
+	Key       string
+	Value     string
+	ExpiresAt pgtype.Timestamptz
+}
+
+type Media struct {
+	SourceID     string
+	TargetID     string
+	TargetSeason pgtype.Int8
+}
+
+type Token struct {
+	UserID      string
+	UserName    string
+	AccessToken string
+	ExpiresAt   pgtype.Timestamptz
+}
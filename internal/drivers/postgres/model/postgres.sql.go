@@ -0,0 +1,198 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: postgres.sql
+
+package model
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteExpiredCache = `-- name: DeleteExpiredCache :exec
+DELETE FROM cache
+WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredCache(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredCache)
+	return err
+}
+
+const deleteMedias = `-- name: DeleteMedias :exec
+DELETE FROM medias
+`
+
+func (q *Queries) DeleteMedias(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteMedias)
+	return err
+}
+
+const deleteTokenByName = `-- name: DeleteTokenByName :exec
+DELETE FROM tokens
+WHERE lower(user_name) = lower($1)
+AND user_id <> $2
+`
+
+type DeleteTokenByNameParams struct {
+	UserName string
+	UserID   string
+}
+
+func (q *Queries) DeleteTokenByName(ctx context.Context, arg DeleteTokenByNameParams) error {
+	_, err := q.db.Exec(ctx, deleteTokenByName, arg.UserName, arg.UserID)
+	return err
+}
+
+const getCacheString = `-- name: GetCacheString :one
+SELECT value
+FROM cache
+WHERE key = $1
+AND (expires_at IS NULL OR expires_at > now())
+LIMIT 1
+`
+
+func (q *Queries) GetCacheString(ctx context.Context, key string) (string, error) {
+	row := q.db.QueryRow(ctx, getCacheString, key)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getMedia = `-- name: GetMedia :one
+SELECT source_id, target_id, target_season FROM medias
+WHERE source_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetMedia(ctx context.Context, id string) (Media, error) {
+	row := q.db.QueryRow(ctx, getMedia, id)
+	var i Media
+	err := row.Scan(&i.SourceID, &i.TargetID, &i.TargetSeason)
+	return i, err
+}
+
+const getMediaBulk = `-- name: GetMediaBulk :many
+SELECT source_id, target_id, target_season FROM medias
+WHERE source_id = ANY($1::TEXT[])
+`
+
+func (q *Queries) GetMediaBulk(ctx context.Context, sourceIds []string) ([]Media, error) {
+	rows, err := q.db.Query(ctx, getMediaBulk, sourceIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Media
+	for rows.Next() {
+		var i Media
+		if err := rows.Scan(&i.SourceID, &i.TargetID, &i.TargetSeason); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getToken = `-- name: GetToken :one
+SELECT user_id, user_name, access_token, expires_at FROM tokens
+WHERE user_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetToken(ctx context.Context, userID string) (Token, error) {
+	row := q.db.QueryRow(ctx, getToken, userID)
+	var i Token
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.AccessToken,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getTokenByName = `-- name: GetTokenByName :one
+SELECT user_id, user_name, access_token, expires_at FROM tokens
+WHERE lower(user_name) = lower($1)
+LIMIT 1
+`
+
+func (q *Queries) GetTokenByName(ctx context.Context, userName string) (Token, error) {
+	row := q.db.QueryRow(ctx, getTokenByName, userName)
+	var i Token
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.AccessToken,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const putCacheString = `-- name: PutCacheString :exec
+INSERT INTO cache (key, value, expires_at)
+VALUES ($1, $2, now() + $3::INTERVAL)
+ON CONFLICT (key) DO UPDATE
+SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+`
+
+type PutCacheStringParams struct {
+	Key   string
+	Value string
+	Ttl   pgtype.Interval
+}
+
+func (q *Queries) PutCacheString(ctx context.Context, arg PutCacheStringParams) error {
+	_, err := q.db.Exec(ctx, putCacheString, arg.Key, arg.Value, arg.Ttl)
+	return err
+}
+
+const putMedia = `-- name: PutMedia :exec
+INSERT INTO medias (source_id, target_id, target_season)
+VALUES ($1, $2, $3)
+ON CONFLICT (source_id, target_id) DO UPDATE
+SET target_season = EXCLUDED.target_season
+`
+
+type PutMediaParams struct {
+	SourceID     string
+	TargetID     string
+	TargetSeason pgtype.Int8
+}
+
+func (q *Queries) PutMedia(ctx context.Context, arg PutMediaParams) error {
+	_, err := q.db.Exec(ctx, putMedia, arg.SourceID, arg.TargetID, arg.TargetSeason)
+	return err
+}
+
+const putToken = `-- name: PutToken :exec
+INSERT INTO tokens (user_id, user_name, access_token, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET user_name = EXCLUDED.user_name,
+	access_token = EXCLUDED.access_token,
+	expires_at = EXCLUDED.expires_at
+`
+
+type PutTokenParams struct {
+	UserID      string
+	UserName    string
+	AccessToken string
+	ExpiresAt   pgtype.Timestamptz
+}
+
+func (q *Queries) PutToken(ctx context.Context, arg PutTokenParams) error {
+	_, err := q.db.Exec(ctx, putToken,
+		arg.UserID,
+		arg.UserName,
+		arg.AccessToken,
+		arg.ExpiresAt,
+	)
+	return err
+}
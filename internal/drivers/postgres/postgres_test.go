@@ -0,0 +1,410 @@
+package postgres_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/postgres"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	getCacheQuery    = "SELECT value FROM cache"
+	putCacheQuery    = "INSERT INTO cache"
+	getMediaQuery    = "SELECT source_id, target_id, target_season FROM medias"
+	putMediaQuery    = "INSERT INTO medias"
+	deleteMediaQuery = "DELETE FROM medias"
+	getTokenQuery    = "SELECT user_id, user_name, access_token, expires_at FROM tokens"
+	putTokenQuery    = "INSERT INTO tokens"
+	deleteTokenQuery = "DELETE FROM tokens"
+	deleteCacheQuery = "DELETE FROM cache"
+)
+
+var errConnection = errors.New("connection refused")
+
+// newMock creates a mock connection that expects the setup queries.
+func newMock(tb testing.TB) pgxmock.PgxPoolIface {
+	tb.Helper()
+
+	conn, err := pgxmock.NewPool()
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() {
+		assert.NoError(tb, conn.ExpectationsWereMet())
+	})
+
+	return conn
+}
+
+// expectOpen expects the queries that [postgres.Open] runs.
+func expectOpen(conn pgxmock.PgxPoolIface) {
+	conn.ExpectPing()
+	conn.ExpectExec("CREATE TABLE IF NOT EXISTS medias").
+		WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	conn.ExpectExec(deleteCacheQuery).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+}
+
+// newPostgres creates a driver over a mock connection.
+func newPostgres(tb testing.TB) (*postgres.Postgres, pgxmock.PgxPoolIface) {
+	tb.Helper()
+
+	conn := newMock(tb)
+	expectOpen(conn)
+
+	db, err := postgres.Open(tb.Context(), conn)
+	require.NoError(tb, err)
+
+	return db, conn
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	conn := newMock(t)
+
+	conn.ExpectPing()
+	conn.ExpectExec(`pg_advisory_xact_lock(.|\n)+CREATE SCHEMA IF NOT EXISTS "mal-store"`).
+		WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	conn.ExpectExec(deleteCacheQuery).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	db, err := postgres.Open(t.Context(), conn, postgres.WithSchema("mal-store"))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestOpen_unavailable(t *testing.T) {
+	t.Parallel()
+
+	conn := newMock(t)
+
+	conn.ExpectPing().WillReturnError(errConnection)
+
+	db, err := postgres.Open(t.Context(), conn)
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+	assert.Nil(t, db)
+}
+
+func TestPostgres_GetString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err       error
+		wantError error
+		name      string
+		want      string
+	}{
+		{
+			name: "found",
+			want: "bar",
+		},
+		{
+			name:      "missing or expired",
+			err:       pgx.ErrNoRows,
+			wantError: usecases.ErrStatusNotFound,
+		},
+		{
+			name:      "connection error",
+			err:       errConnection,
+			wantError: usecases.ErrStatusUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, conn := newPostgres(t)
+
+			query := conn.ExpectQuery(getCacheQuery).WithArgs("foo")
+			if tt.err != nil {
+				query.WillReturnError(tt.err)
+			} else {
+				query.WillReturnRows(pgxmock.NewRows([]string{"value"}).AddRow(tt.want))
+			}
+
+			got, err := db.GetString(t.Context(), "foo")
+			require.ErrorIs(t, err, tt.wantError)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPostgres_SetString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []usecases.CacheOption
+		ttl     pgtype.Interval
+	}{
+		{
+			name: "without TTL",
+			ttl:  pgtype.Interval{},
+		},
+		{
+			name:    "with TTL",
+			options: []usecases.CacheOption{usecases.WithTTL(time.Hour)},
+			ttl:     pgtype.Interval{Microseconds: time.Hour.Microseconds(), Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, conn := newPostgres(t)
+
+			conn.ExpectExec(putCacheQuery).
+				WithArgs("foo", "bar", tt.ttl).
+				WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+			err := db.SetString(t.Context(), "foo", "bar", tt.options...)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPostgres_Sweep(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectExec(deleteCacheQuery).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	conn.ExpectExec(deleteCacheQuery).
+		WillReturnError(errConnection)
+	conn.ExpectClose()
+
+	require.NoError(t, db.Sweep(t.Context()))
+	require.ErrorIs(t, db.Sweep(t.Context()), usecases.ErrStatusUnavailable)
+	require.NoError(t, db.Close())
+}
+
+func TestPostgres_Close_twice(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectClose()
+	conn.ExpectClose()
+
+	require.NoError(t, db.Close())
+	require.NoError(t, db.Close())
+}
+
+func TestPostgres_GetMedia(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	season := uint64(2)
+
+	conn.ExpectQuery(getMediaQuery).
+		WithArgs("1").
+		WillReturnRows(pgxmock.NewRows([]string{"source_id", "target_id", "target_season"}).
+			AddRow("1", "101", pgtype.Int8{Int64: 2, Valid: true}))
+	conn.ExpectQuery(getMediaQuery).
+		WithArgs("2").
+		WillReturnError(pgx.ErrNoRows)
+
+	got, err := db.GetMedia(t.Context(), "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, &entities.Media{SourceID: "1", TargetID: "101", TargetSeason: &season}, got)
+
+	got, err = db.GetMedia(t.Context(), "2")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	assert.Nil(t, got)
+}
+
+func TestPostgres_GetMediaBulk(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectQuery(getMediaQuery).
+		WithArgs([]string{"1", "2"}).
+		WillReturnRows(pgxmock.NewRows([]string{"source_id", "target_id", "target_season"}).
+			AddRow("1", "101", pgtype.Int8{}))
+
+	got, err := db.GetMediaBulk(t.Context(), []string{"1", "2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*entities.Media{{SourceID: "1", TargetID: "101"}}, got)
+}
+
+func TestPostgres_PutMedia(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectExec(putMediaQuery).
+		WithArgs("1", "101", pgtype.Int8{}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err := db.PutMedia(t.Context(), &entities.Media{SourceID: "1", TargetID: "101"})
+	require.NoError(t, err)
+
+	err = db.PutMedia(t.Context(), &entities.Media{SourceID: "1", TargetID: ""})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
+
+func TestPostgres_PutMediaBulk(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectBegin()
+
+	batch := conn.ExpectBatch()
+	batch.ExpectExec(putMediaQuery).
+		WithArgs("1", "101", pgtype.Int8{}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	batch.ExpectExec(putMediaQuery).
+		WithArgs("2", "102", pgtype.Int8{Int64: 1, Valid: true}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	conn.ExpectCommit()
+
+	season := uint64(1)
+
+	err := db.PutMediaBulk(t.Context(), []*entities.Media{
+		{SourceID: "1", TargetID: "101"},
+		{SourceID: "2", TargetID: "102", TargetSeason: &season},
+	})
+	require.NoError(t, err)
+}
+
+func TestPostgres_PutMediaBulk_invalid(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectBegin()
+	conn.ExpectRollback()
+
+	err := db.PutMediaBulk(t.Context(), []*entities.Media{
+		{SourceID: "1", TargetID: "101"},
+		{SourceID: "", TargetID: "102"},
+	})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
+
+func TestPostgres_ReplaceMediaBulk(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectBegin()
+	conn.ExpectExec(deleteMediaQuery).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+	batch := conn.ExpectBatch()
+	batch.ExpectExec(putMediaQuery).
+		WithArgs("1", "101", pgtype.Int8{}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	conn.ExpectCommit()
+
+	err := db.ReplaceMediaBulk(t.Context(), []*entities.Media{{SourceID: "1", TargetID: "101"}})
+	require.NoError(t, err)
+}
+
+func TestPostgres_ReplaceMediaBulk_error(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectBegin()
+	conn.ExpectExec(deleteMediaQuery).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+	batch := conn.ExpectBatch()
+	batch.ExpectExec(putMediaQuery).
+		WithArgs("1", "101", pgtype.Int8{}).
+		WillReturnError(errConnection)
+
+	conn.ExpectRollback()
+
+	err := db.ReplaceMediaBulk(t.Context(), []*entities.Media{{SourceID: "1", TargetID: "101"}})
+	require.ErrorIs(t, err, usecases.ErrStatusAborted)
+}
+
+func TestPostgres_GetToken(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	columns := []string{"user_id", "user_name", "access_token", "expires_at"}
+
+	conn.ExpectQuery(getTokenQuery).
+		WithArgs("1").
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow("1", "Foo", "bar", pgtype.Timestamptz{Time: expiresAt, Valid: true}))
+	conn.ExpectQuery(getTokenQuery).
+		WithArgs("foo").
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow("1", "Foo", "bar", pgtype.Timestamptz{}))
+	conn.ExpectQuery(getTokenQuery).
+		WithArgs("2").
+		WillReturnError(pgx.ErrNoRows)
+	conn.ExpectQuery(getTokenQuery).
+		WithArgs("baz").
+		WillReturnError(errConnection)
+
+	got, err := db.GetToken(t.Context(), "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, &entities.Token{
+		ExpiresAt:   expiresAt,
+		UserID:      "1",
+		UserName:    "Foo",
+		AccessToken: "bar",
+	}, got)
+
+	got, err = db.GetTokenByName(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, &entities.Token{UserID: "1", UserName: "Foo", AccessToken: "bar"}, got)
+
+	_, err = db.GetToken(t.Context(), "2")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	_, err = db.GetTokenByName(t.Context(), "baz")
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+}
+
+func TestPostgres_PutToken(t *testing.T) {
+	t.Parallel()
+
+	db, conn := newPostgres(t)
+
+	conn.ExpectBegin()
+	conn.ExpectExec(deleteTokenQuery).
+		WithArgs("Foo", "1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	conn.ExpectExec(putTokenQuery).
+		WithArgs("1", "Foo", "bar", pgtype.Timestamptz{}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	conn.ExpectCommit()
+
+	err := db.PutToken(t.Context(), &entities.Token{UserID: "1", UserName: "Foo", AccessToken: "bar"})
+	require.NoError(t, err)
+
+	err = db.PutToken(t.Context(), &entities.Token{UserID: "1", UserName: "Foo"})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS medias (
+	source_id TEXT NOT NULL,
+	target_id TEXT NOT NULL,
+	target_season BIGINT,
+	CHECK(source_id <> ''),
+	CHECK(target_id <> ''),
+	PRIMARY KEY(source_id, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS
+	medias_source_id ON medias (source_id);
+
+CREATE TABLE IF NOT EXISTS cache (
+	key        TEXT NOT NULL,
+	value      TEXT NOT NULL,
+	expires_at TIMESTAMPTZ,
+	CHECK(key <> ''),
+	CHECK(value <> ''),
+	PRIMARY KEY(key)
+);
+
+CREATE INDEX IF NOT EXISTS
+	cache_expires_at ON cache (expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS tokens (
+	user_id      TEXT NOT NULL,
+	user_name    TEXT NOT NULL,
+	access_token TEXT NOT NULL,
+	expires_at   TIMESTAMPTZ,
+	CHECK(user_id <> ''),
+	CHECK(user_name <> ''),
+	CHECK(access_token <> ''),
+	PRIMARY KEY(user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS
+	tokens_user_name ON tokens (lower(user_name));
@@ -0,0 +1,31 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/postgres"
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	var drivers registry.Drivers
+
+	postgres.Register(&drivers)
+
+	require.Equal(t, []string{postgres.Scheme}, drivers.Stores.Schemes())
+	require.Equal(t, []string{postgres.Scheme}, drivers.Caches.Schemes())
+
+	// nothing listens on port 1, so connecting fails right away
+	store, err := drivers.Stores.New(t.Context(), "postgres://127.0.0.1:1/anilistarr/store?connect_timeout=1")
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+	require.Nil(t, store)
+
+	cache, err := drivers.Caches.New(t.Context(), "postgres://127.0.0.1:1/anilistarr?sslmode=invalid")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+	require.Nil(t, cache)
+}
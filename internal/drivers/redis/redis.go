@@ -4,6 +4,7 @@ package redis
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
@@ -12,7 +13,11 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
-var _ usecases.Cache = (*Redis)(nil)
+// noExpiration is the PTTL reply for keys without expiration. Other negative
+// replies mean the key is missing.
+const noExpiration time.Duration = -1
+
+var _ usecases.ExpiringCache = (*Redis)(nil)
 
 // Options re-exports [redis.Options] so consumers avoid an extra import
 type Options = redis.Options
@@ -64,6 +69,45 @@ func (cache *Redis) GetString(ctx context.Context, key string) (string, error) {
 	return res, span.Assert(usecases.ErrorJoinIf(usecases.ErrStatusUnknown, err))
 }
 
+// GetStringTTL queries the value for key and the time left until it expires
+// in a single round trip. It returns [usecases.ErrStatusNotFound] if key is
+// not in the cache.
+func (cache *Redis) GetStringTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	var (
+		value *redis.StringCmd
+		ttl   *redis.DurationCmd
+	)
+
+	_, err := cache.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		value = pipe.Get(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return "", 0, span.Assert(usecases.ErrStatusNotFound)
+	}
+
+	if err != nil {
+		return "", 0, span.Assert(errors.Join(usecases.ErrStatusUnknown, err))
+	}
+
+	remaining := ttl.Val()
+
+	switch {
+	case remaining == noExpiration:
+		remaining = 0
+	case remaining < 0:
+		// expired after the GET
+		return "", 0, span.Assert(usecases.ErrStatusNotFound)
+	}
+
+	return value.Val(), remaining, span.Assert(nil)
+}
+
 // SetString stores value for key in the cache. It supports entries with a set
 // expiration time by using [adapters.WithTTL] option.
 func (cache *Redis) SetString(
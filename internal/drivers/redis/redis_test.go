@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,6 +70,42 @@ func TestRedis_works(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRedis_GetStringTTL(t *testing.T) {
+	t.Parallel()
+
+	options := redis.Options{
+		Addr:             runValkey(t),
+		DisableIndentity: true,
+		Network:          "unix",
+	}
+
+	cache, err := redis.New(t.Context(), &options)
+	require.NoError(t, err)
+
+	defer cache.Close()
+
+	_, _, err = cache.GetStringTTL(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	err = cache.SetString(t.Context(), "foo", "bar")
+	require.NoError(t, err)
+
+	err = cache.SetString(t.Context(), "baz", "qux", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	got, ttl, err := cache.GetStringTTL(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", got)
+	assert.Zero(t, ttl)
+
+	got, ttl, err = cache.GetStringTTL(t.Context(), "baz")
+	require.NoError(t, err)
+
+	assert.Equal(t, "qux", got)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Minute))
+}
+
 func TestNew_createInstanceSuccessfully(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,113 @@
+// Package registry maps URL schemes to driver factories, which allows picking
+// store and cache drivers at runtime.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// Factory creates a driver from its URL.
+type Factory[T any] func(ctx context.Context, uri *url.URL) (T, error)
+
+// Registry maps URL schemes to the factories of a kind of driver. Registering
+// is not safe for concurrent use, so it must happen before creating drivers.
+type Registry[T any] struct {
+	factories map[string]Factory[T]
+}
+
+// Drivers contains a registry per kind of driver.
+type Drivers struct {
+	Stores Registry[usecases.Store]
+	Caches Registry[usecases.Cache]
+}
+
+// Register adds a factory of concrete drivers D to a registry of drivers T
+// under scheme, replacing any previous one. Drivers D must implement T.
+func Register[T, D any](
+	registry *Registry[T],
+	scheme string,
+	factory func(ctx context.Context, uri *url.URL) (D, error),
+) {
+	registry.Register(scheme, func(ctx context.Context, uri *url.URL) (T, error) {
+		var zero T
+
+		driver, err := factory(ctx, uri)
+		if err != nil {
+			return zero, err
+		}
+
+		value, ok := any(driver).(T)
+		if !ok {
+			return zero, fmt.Errorf(
+				"%w: %T is not a %s",
+				usecases.ErrStatusInternal,
+				driver,
+				reflect.TypeFor[T](),
+			)
+		}
+
+		return value, nil
+	})
+}
+
+// Path returns the filesystem path within an URL. Those are either opaque, as
+// in badger:data/store, or split between host and path, as in
+// badger://data/store or badger:///var/lib/store for absolute ones.
+func Path(uri *url.URL) string {
+	if uri.Opaque != "" {
+		return uri.Opaque
+	}
+
+	return uri.Host + uri.Path
+}
+
+// Register adds a factory under scheme, replacing any previous one.
+func (registry *Registry[T]) Register(scheme string, factory Factory[T]) {
+	if registry.factories == nil {
+		registry.factories = map[string]Factory[T]{}
+	}
+
+	registry.factories[scheme] = factory
+}
+
+// Schemes returns the registered schemes in order.
+func (registry *Registry[T]) Schemes() []string {
+	return slices.Sorted(maps.Keys(registry.factories))
+}
+
+// New creates a driver with the factory registered under the scheme of rawURL.
+// It fails with [usecases.ErrStatusInvalidArgument] if the URL is invalid or no
+// factory handles its scheme.
+func (registry *Registry[T]) New(ctx context.Context, rawURL string) (T, error) {
+	var zero T
+
+	uri, err := url.Parse(rawURL)
+	if err != nil {
+		return zero, fmt.Errorf("%w: invalid driver URL: %w", usecases.ErrStatusInvalidArgument, err)
+	}
+
+	factory, ok := registry.factories[uri.Scheme]
+	if !ok {
+		return zero, fmt.Errorf(
+			"%w: unknown driver scheme %q, supported ones are %s",
+			usecases.ErrStatusInvalidArgument,
+			uri.Scheme,
+			strings.Join(registry.Schemes(), ", "),
+		)
+	}
+
+	driver, err := factory(ctx, uri)
+	if err != nil {
+		return zero, fmt.Errorf("failed to create %s driver: %w", uri.Scheme, err)
+	}
+
+	return driver, nil
+}
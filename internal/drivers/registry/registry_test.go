@@ -0,0 +1,95 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	errFactory := errors.New("foo")
+	cache := test.NewMockCache(t)
+
+	var drivers registry.Drivers
+
+	var gotURI *url.URL
+
+	registry.Register(&drivers.Caches, "mem", func(_ context.Context, uri *url.URL) (*test.MockCache, error) {
+		gotURI = uri
+
+		return cache, nil
+	})
+	registry.Register(&drivers.Caches, "fail", func(context.Context, *url.URL) (*test.MockCache, error) {
+		return nil, errFactory
+	})
+	registry.Register(&drivers.Stores, "mem", func(context.Context, *url.URL) (*test.MockCache, error) {
+		return cache, nil
+	})
+
+	assert.Equal(t, []string{"fail", "mem"}, drivers.Caches.Schemes())
+
+	got, err := drivers.Caches.New(t.Context(), "mem://foo/bar?baz=qux")
+	require.NoError(t, err)
+
+	assert.Same(t, cache, got)
+	assert.Equal(t, "foo/bar", registry.Path(gotURI))
+	assert.Equal(t, "qux", gotURI.Query().Get("baz"))
+
+	got, err = drivers.Caches.New(t.Context(), "fail://")
+	require.ErrorIs(t, err, errFactory)
+
+	assert.Nil(t, got)
+
+	got, err = drivers.Caches.New(t.Context(), "unknown://")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+	require.ErrorContains(t, err, `"unknown"`)
+	require.ErrorContains(t, err, "fail, mem")
+
+	assert.Nil(t, got)
+
+	got, err = drivers.Caches.New(t.Context(), "mem://foo\n")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	assert.Nil(t, got)
+
+	// caches are not stores
+	gotStore, err := drivers.Stores.New(t.Context(), "mem://")
+	require.ErrorIs(t, err, usecases.ErrStatusInternal)
+
+	assert.Nil(t, gotStore)
+}
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rawURL string
+		want   string
+	}{
+		{name: "absolute", rawURL: "badger:///var/lib/store", want: "/var/lib/store"},
+		{name: "relative", rawURL: "badger://data/store", want: "data/store"},
+		{name: "opaque", rawURL: "sqlite:file::memory:", want: "file::memory:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uri, err := url.Parse(tt.rawURL)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, registry.Path(uri))
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	telemetry "github.com/wwmoraes/gotell"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    INTEGER NOT NULL PRIMARY KEY,
+	name       TEXT NOT NULL,
+	applied_at INTEGER NOT NULL -- Unix time in seconds
+) STRICT;`
+	selectMigrationsTable = "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'"
+	selectMigrations      = "SELECT version, applied_at FROM schema_migrations"
+	selectMigration       = "SELECT COUNT(*) > 0 FROM schema_migrations WHERE version = ?"
+	insertMigration       = "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"
+)
+
+var (
+	//go:embed migrations/*.sql
+	migrationFiles embed.FS
+
+	// legacyProbes tell which migrations databases created before versioning
+	// have, as those got all tables and columns known back then at once. Each
+	// probe returns a row if the changes of its migration exist.
+	legacyProbes = map[uint]string{
+		1: "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'medias'",
+		2: "SELECT 1 FROM pragma_table_info('medias') WHERE name = 'target_season'",
+		3: "SELECT 1 FROM pragma_table_info('cache') WHERE name = 'expires_at'",
+	}
+)
+
+// Migration is a numbered schema change. Files named after their version and
+// name within the migrations directory contain their queries, e.g.
+// 0002_add_media_target_season.sql.
+type Migration struct {
+	Name    string
+	queries string
+	Version uint
+}
+
+// MigrationStatus tells whether a migration was applied to a database and
+// when.
+type MigrationStatus struct {
+	AppliedAt time.Time
+	Migration
+	Applied bool
+}
+
+// Migrations returns all known migrations in order.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, errors.Join(usecases.ErrStatusInternal, err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+
+	for _, entry := range entries {
+		migration, err := readMigration(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return int(a.Version) - int(b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Status returns the state of all known migrations on db in order. It does not
+// change db.
+func Status(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	states := make([]MigrationStatus, 0, len(migrations))
+
+	for _, migration := range migrations {
+		appliedAt, ok := applied[migration.Version]
+
+		states = append(states, MigrationStatus{
+			AppliedAt: appliedAt,
+			Migration: migration,
+			Applied:   ok,
+		})
+	}
+
+	return states, span.Assert(nil)
+}
+
+// Migrate applies all pending migrations on db in order, each within a
+// transaction. It returns the applied ones. Databases created before versioning
+// get the migrations they already have recorded as applied first.
+func Migrate(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	_, err := db.ExecContext(ctx, createMigrationsTable)
+	if err != nil {
+		return nil, span.Assert(fmt.Errorf("failed to create migrations table: %w", err))
+	}
+
+	states, err := Status(ctx, db)
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	if !slices.ContainsFunc(states, func(state MigrationStatus) bool { return state.Applied }) {
+		err = adoptLegacy(ctx, db, states)
+		if err != nil {
+			return nil, span.Assert(err)
+		}
+	}
+
+	applied := make([]Migration, 0, len(states))
+
+	for _, state := range states {
+		ok, err := apply(ctx, db, state.Migration)
+		if err != nil {
+			return applied, span.Assert(err)
+		}
+
+		if ok {
+			applied = append(applied, state.Migration)
+		}
+	}
+
+	return applied, span.Assert(nil)
+}
+
+// readMigration parses the version and name of a migration file, and reads its
+// queries.
+func readMigration(fileName string) (Migration, error) {
+	var migration Migration
+
+	prefix, name, found := strings.Cut(strings.TrimSuffix(fileName, path.Ext(fileName)), "_")
+	if !found {
+		return migration, fmt.Errorf("%w: unnamed migration %s", usecases.ErrStatusInternal, fileName)
+	}
+
+	version, err := strconv.ParseUint(prefix, 10, 0)
+	if err != nil {
+		return migration, fmt.Errorf("%w: unnumbered migration %s: %w", usecases.ErrStatusInternal, fileName, err)
+	}
+
+	data, err := fs.ReadFile(migrationFiles, path.Join("migrations", fileName))
+	if err != nil {
+		return migration, errors.Join(usecases.ErrStatusInternal, err)
+	}
+
+	migration.Name = name
+	migration.queries = string(data)
+	migration.Version = uint(version)
+
+	return migration, nil
+}
+
+// appliedMigrations returns when each applied migration version was applied.
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[uint]time.Time, error) {
+	applied := map[uint]time.Time{}
+
+	var versioned bool
+
+	err := db.QueryRowContext(ctx, selectMigrationsTable).Scan(&versioned)
+	if err != nil || !versioned {
+		return applied, usecases.ErrorJoinIf(usecases.ErrStatusUnknown, err)
+	}
+
+	rows, err := db.QueryContext(ctx, selectMigrations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			version   uint
+			appliedAt int64
+		)
+
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list migrations: %w", err)
+		}
+
+		applied[version] = time.Unix(appliedAt, 0)
+	}
+
+	return applied, usecases.ErrorJoinIf(usecases.ErrStatusUnknown, rows.Err())
+}
+
+// adoptLegacy records the migrations that databases created before versioning
+// already have as applied.
+func adoptLegacy(ctx context.Context, db *sql.DB, states []MigrationStatus) error {
+	for _, state := range states {
+		probe, ok := legacyProbes[state.Version]
+		if !ok {
+			return nil
+		}
+
+		err := db.QueryRowContext(ctx, probe).Scan(new(int))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to inspect schema: %w", err)
+		}
+
+		_, err = db.ExecContext(ctx, insertMigration, state.Version, state.Name, time.Now().Unix())
+		if err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", state.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// apply runs migration within a transaction unless it was already applied,
+// possibly by another connection. It returns true if it applied the migration.
+func apply(ctx context.Context, db *sql.DB, migration Migration) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Join(usecases.ErrStatusFailedPrecondition, err)
+	}
+	defer tx.Rollback()
+
+	var applied bool
+
+	err = tx.QueryRowContext(ctx, selectMigration, migration.Version).Scan(&applied)
+	if err != nil || applied {
+		return false, usecases.ErrorJoinIf(usecases.ErrStatusUnknown, err)
+	}
+
+	_, err = tx.ExecContext(ctx, migration.queries)
+	if err != nil {
+		return false, fmt.Errorf("%w: migration %d failed: %w", usecases.ErrStatusAborted, migration.Version, err)
+	}
+
+	_, err = tx.ExecContext(ctx, insertMigration, migration.Version, migration.Name, time.Now().Unix())
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to record migration %d: %w", usecases.ErrStatusAborted, migration.Version, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, errors.Join(usecases.ErrStatusFailedPrecondition, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,115 @@
+//go:build !pure
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/sqlite"
+)
+
+// legacySchema is the schema of databases created before versioning, which
+// lacks the cache expiry.
+const legacySchema = `CREATE TABLE medias (
+	source_id TEXT NOT NULL,
+	target_id TEXT NOT NULL,
+	target_season INTEGER,
+	PRIMARY KEY(source_id, target_id)
+) WITHOUT ROWID, STRICT;
+CREATE TABLE cache (
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY(key)
+) WITHOUT ROWID, STRICT;
+INSERT INTO medias (source_id, target_id, target_season) VALUES ('1', '101', 2);`
+
+func TestMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := sqlite.Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for index, migration := range migrations {
+		assert.Equal(t, uint(index+1), migration.Version, "migrations must be numbered in sequence")
+		assert.NotEmpty(t, migration.Name)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	db := openDB(t, filepath.Join(t.TempDir(), "fresh.db"))
+
+	migrations, err := sqlite.Migrations()
+	require.NoError(t, err)
+
+	states, err := sqlite.Status(t.Context(), db)
+	require.NoError(t, err)
+	require.Len(t, states, len(migrations))
+
+	for _, state := range states {
+		assert.False(t, state.Applied)
+	}
+
+	applied, err := sqlite.Migrate(t.Context(), db)
+	require.NoError(t, err)
+	assert.Equal(t, migrations, applied)
+
+	applied, err = sqlite.Migrate(t.Context(), db)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	states, err = sqlite.Status(t.Context(), db)
+	require.NoError(t, err)
+
+	for _, state := range states {
+		assert.True(t, state.Applied)
+		assert.False(t, state.AppliedAt.IsZero())
+	}
+}
+
+func TestMigrate_legacy(t *testing.T) {
+	t.Parallel()
+
+	dataSourceName := filepath.Join(t.TempDir(), "legacy.db")
+	db := openDB(t, dataSourceName)
+
+	_, err := db.ExecContext(t.Context(), legacySchema)
+	require.NoError(t, err)
+
+	applied, err := sqlite.Migrate(t.Context(), db)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "add_cache_expiry", applied[0].Name)
+	assert.Equal(t, "create_tokens", applied[1].Name)
+
+	store, err := sqlite.New(t.Context(), dataSourceName)
+	require.NoError(t, err)
+
+	defer closeSQLite(t, store)
+
+	got, err := store.GetMedia(t.Context(), "1")
+	require.NoError(t, err)
+	require.NotNil(t, got.TargetSeason)
+	assert.Equal(t, uint64(2), *got.TargetSeason)
+}
+
+func openDB(tb testing.TB, dataSourceName string) *sql.DB {
+	tb.Helper()
+
+	db, err := sql.Open("sqlite", dataSourceName)
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() {
+		assert.NoError(tb, db.Close())
+	})
+
+	return db
+}
@@ -1,5 +1,3 @@
-PRAGMA encoding = 'UTF-8';
-
 CREATE TABLE IF NOT EXISTS medias (
 	source_id TEXT NOT NULL, -- VARCHAR(64)
 	target_id TEXT NOT NULL, -- VARCHAR(64)
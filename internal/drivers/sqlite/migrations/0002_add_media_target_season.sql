@@ -0,0 +1 @@
+ALTER TABLE medias ADD COLUMN target_season INTEGER;
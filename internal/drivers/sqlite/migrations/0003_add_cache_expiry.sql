@@ -0,0 +1,2 @@
+-- Unix time in nanoseconds
+ALTER TABLE cache ADD COLUMN expires_at INTEGER;
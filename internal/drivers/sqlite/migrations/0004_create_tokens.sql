@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS tokens (
+	user_id      TEXT NOT NULL, -- VARCHAR(64)
+	user_name    TEXT NOT NULL COLLATE NOCASE, -- VARCHAR(64)
+	access_token TEXT NOT NULL,
+	expires_at   INTEGER, -- Unix time in nanoseconds
+	CHECK(user_id <> ''),
+	CHECK(user_name <> ''),
+	CHECK(access_token <> ''),
+	PRIMARY KEY(user_id)
+) WITHOUT ROWID, STRICT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS
+	tokens_user_name ON tokens (user_name);
@@ -4,14 +4,27 @@
 
 package model
 
+import (
+	"database/sql"
+)
+
 type Cache struct {
-	Key   string
-	Value string
+	Key       string
+	Value     string
+	ExpiresAt sql.NullInt64
 }
 
 type Media struct {
-	SourceID string
-	TargetID string
+	SourceID     string
+	TargetID     string
+	TargetSeason sql.NullInt64
+}
+
+type Token struct {
+	UserID      string
+	UserName    string
+	AccessToken string
+	ExpiresAt   sql.NullInt64
 }
 
 type User struct {
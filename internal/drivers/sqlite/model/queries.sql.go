@@ -7,25 +7,51 @@ package model
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 )
 
+const deleteExpiredCache = `-- name: DeleteExpiredCache :exec
+DELETE FROM cache
+WHERE expires_at <= CAST(?1 AS INTEGER)
+`
+
+func (q *Queries) DeleteExpiredCache(ctx context.Context, now int64) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredCache, now)
+	return err
+}
+
+const deleteMedias = `-- name: DeleteMedias :exec
+DELETE FROM medias
+`
+
+func (q *Queries) DeleteMedias(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteMedias)
+	return err
+}
+
 const getCacheString = `-- name: GetCacheString :one
 SELECT value
 FROM cache
 WHERE key = ?1
+AND (expires_at IS NULL OR expires_at > CAST(?2 AS INTEGER))
 LIMIT 1
 `
 
-func (q *Queries) GetCacheString(ctx context.Context, key string) (string, error) {
-	row := q.db.QueryRowContext(ctx, getCacheString, key)
+type GetCacheStringParams struct {
+	Key string
+	Now int64
+}
+
+func (q *Queries) GetCacheString(ctx context.Context, arg GetCacheStringParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCacheString, arg.Key, arg.Now)
 	var value string
 	err := row.Scan(&value)
 	return value, err
 }
 
 const getMedia = `-- name: GetMedia :one
-SELECT source_id, target_id FROM medias
+SELECT source_id, target_id, target_season FROM medias
 WHERE source_id = ?1
 LIMIT 1
 `
@@ -33,12 +59,12 @@ LIMIT 1
 func (q *Queries) GetMedia(ctx context.Context, id string) (Media, error) {
 	row := q.db.QueryRowContext(ctx, getMedia, id)
 	var i Media
-	err := row.Scan(&i.SourceID, &i.TargetID)
+	err := row.Scan(&i.SourceID, &i.TargetID, &i.TargetSeason)
 	return i, err
 }
 
 const getMediaBulk = `-- name: GetMediaBulk :many
-SELECT source_id, target_id FROM medias
+SELECT source_id, target_id, target_season FROM medias
 WHERE source_id
 IN (/*SLICE:source_ids*/?)
 `
@@ -62,7 +88,7 @@ func (q *Queries) GetMediaBulk(ctx context.Context, sourceIds []string) ([]Media
 	var items []Media
 	for rows.Next() {
 		var i Media
-		if err := rows.Scan(&i.SourceID, &i.TargetID); err != nil {
+		if err := rows.Scan(&i.SourceID, &i.TargetID, &i.TargetSeason); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -76,32 +102,92 @@ func (q *Queries) GetMediaBulk(ctx context.Context, sourceIds []string) ([]Media
 	return items, nil
 }
 
+const getToken = `-- name: GetToken :one
+SELECT user_id, user_name, access_token, expires_at FROM tokens
+WHERE user_id = ?1
+LIMIT 1
+`
+
+func (q *Queries) GetToken(ctx context.Context, userID string) (Token, error) {
+	row := q.db.QueryRowContext(ctx, getToken, userID)
+	var i Token
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.AccessToken,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getTokenByName = `-- name: GetTokenByName :one
+SELECT user_id, user_name, access_token, expires_at FROM tokens
+WHERE user_name = ?1
+LIMIT 1
+`
+
+func (q *Queries) GetTokenByName(ctx context.Context, userName string) (Token, error) {
+	row := q.db.QueryRowContext(ctx, getTokenByName, userName)
+	var i Token
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.AccessToken,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const putCacheString = `-- name: PutCacheString :exec
-REPLACE INTO cache (key, value)
-VALUES (?1, ?2)
+REPLACE INTO cache (key, value, expires_at)
+VALUES (?1, ?2, ?3)
 `
 
 type PutCacheStringParams struct {
-	Key   string
-	Value string
+	Key       string
+	Value     string
+	ExpiresAt sql.NullInt64
 }
 
 func (q *Queries) PutCacheString(ctx context.Context, arg PutCacheStringParams) error {
-	_, err := q.db.ExecContext(ctx, putCacheString, arg.Key, arg.Value)
+	_, err := q.db.ExecContext(ctx, putCacheString, arg.Key, arg.Value, arg.ExpiresAt)
 	return err
 }
 
 const putMedia = `-- name: PutMedia :exec
-REPLACE INTO medias (source_id, target_id)
-VALUES (?1, ?2)
+REPLACE INTO medias (source_id, target_id, target_season)
+VALUES (?1, ?2, ?3)
 `
 
 type PutMediaParams struct {
-	SourceID string
-	TargetID string
+	SourceID     string
+	TargetID     string
+	TargetSeason sql.NullInt64
 }
 
 func (q *Queries) PutMedia(ctx context.Context, arg PutMediaParams) error {
-	_, err := q.db.ExecContext(ctx, putMedia, arg.SourceID, arg.TargetID)
+	_, err := q.db.ExecContext(ctx, putMedia, arg.SourceID, arg.TargetID, arg.TargetSeason)
+	return err
+}
+
+const putToken = `-- name: PutToken :exec
+REPLACE INTO tokens (user_id, user_name, access_token, expires_at)
+VALUES (?1, ?2, ?3, ?4)
+`
+
+type PutTokenParams struct {
+	UserID      string
+	UserName    string
+	AccessToken string
+	ExpiresAt   sql.NullInt64
+}
+
+func (q *Queries) PutToken(ctx context.Context, arg PutTokenParams) error {
+	_, err := q.db.ExecContext(ctx, putToken,
+		arg.UserID,
+		arg.UserName,
+		arg.AccessToken,
+		arg.ExpiresAt,
+	)
 	return err
 }
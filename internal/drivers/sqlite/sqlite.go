@@ -5,9 +5,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	telemetry "github.com/wwmoraes/gotell"
 
@@ -16,22 +17,28 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
-var (
-	//go:embed schema.sql
-	schema string
+const sweepInterval = 10 * time.Minute
 
-	_ usecases.Cache = (*SQLite)(nil)
-	_ usecases.Store = (*SQLite)(nil)
+var (
+	_ usecases.Cache      = (*SQLite)(nil)
+	_ usecases.Store      = (*SQLite)(nil)
+	_ usecases.TokenStore = (*SQLite)(nil)
 )
 
-// SQLite provides a SQLite-backed cache and store driver.
+// SQLite provides a SQLite-backed cache and store driver. Expired cache
+// entries are missing on retrieval, and a background sweeper deletes them
+// periodically.
 type SQLite struct {
-	handler *sql.DB
-	queries *model.Queries
+	handler  *sql.DB
+	queries  *model.Queries
+	done     chan struct{}
+	sweeping sync.WaitGroup
+	stopping sync.Once
 }
 
-// New creates a SQLite database handler and tests connection to it. It does NOT
-// import a driver; the caller is responsible for importing one.
+// New creates a SQLite database handler, tests connection to it and applies any
+// pending migrations. It does NOT import a driver; the caller is responsible
+// for importing one.
 func New(ctx context.Context, dataSourceName string) (*SQLite, error) {
 	db, err := sql.Open("sqlite", dataSourceName)
 	if err != nil {
@@ -46,32 +53,57 @@ func New(ctx context.Context, dataSourceName string) (*SQLite, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	_, err = db.ExecContext(ctx, schema)
+	// the encoding only applies to new databases, and before any transaction
+	_, err = db.ExecContext(ctx, "PRAGMA encoding = 'UTF-8';")
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute schema queries: %w", err)
 	}
 
-	return &SQLite{
-		handler: db,
-		queries: model.New(db),
-	}, nil
+	_, err = Migrate(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
+	driver := &SQLite{
+		handler:  db,
+		queries:  model.New(db),
+		done:     make(chan struct{}),
+		sweeping: sync.WaitGroup{},
+		stopping: sync.Once{},
+	}
+
+	driver.sweeping.Add(1)
+
+	go driver.sweep(sweepInterval)
+
+	return driver, nil
 }
 
-// Close terminates the connection to the underlying database.
+// Close stops the sweeper and terminates the connection to the underlying
+// database.
+// It is safe to call more than once.
 func (db *SQLite) Close() error {
+	db.stopping.Do(func() {
+		close(db.done)
+		db.sweeping.Wait()
+	})
+
 	return usecases.ErrorJoinIf(
 		usecases.ErrStatusInternal,
 		db.handler.Close(),
 	)
 }
 
-// GetString retrieves value for key if it is set. Returns
+// GetString retrieves value for key if it is set and not expired. Returns
 // [usecases.ErrStatusNotFound] otherwise.
 func (db *SQLite) GetString(ctx context.Context, key string) (string, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
-	value, err := db.queries.GetCacheString(ctx, key)
+	value, err := db.queries.GetCacheString(ctx, model.GetCacheStringParams{
+		Key: key,
+		Now: time.Now().UnixNano(),
+	})
 	if err != nil {
 		return "", span.Assert(errors.Join(usecases.ErrStatusNotFound, err))
 	}
@@ -80,17 +112,28 @@ func (db *SQLite) GetString(ctx context.Context, key string) (string, error) {
 }
 
 // SetString stores value for key. It overrides any previously existing value.
+// Set an expiration time by using [usecases.WithTTL] option.
 func (db *SQLite) SetString(
 	ctx context.Context,
 	key, value string,
-	_ ...usecases.CacheOption,
+	options ...usecases.CacheOption,
 ) error {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
+	params := usecases.NewCacheOptions(options...)
+
+	var expiresAt sql.NullInt64
+
+	if params.TTL > 0 {
+		expiresAt.Int64 = time.Now().Add(params.TTL).UnixNano()
+		expiresAt.Valid = true
+	}
+
 	err := db.queries.PutCacheString(ctx, model.PutCacheStringParams{
-		Key:   key,
-		Value: value,
+		Key:       key,
+		Value:     value,
+		ExpiresAt: expiresAt,
 	})
 	if err != nil {
 		return span.Assert(errors.Join(usecases.ErrStatusUnknown, err))
@@ -99,6 +142,17 @@ func (db *SQLite) SetString(
 	return span.Assert(nil)
 }
 
+// Sweep deletes all expired cache entries.
+func (db *SQLite) Sweep(ctx context.Context) error {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	return span.Assert(usecases.ErrorJoinIf(
+		usecases.ErrStatusAborted,
+		db.queries.DeleteExpiredCache(ctx, time.Now().UnixNano()),
+	))
+}
+
 // GetMedia retrieves a media entry from the cache.
 func (db *SQLite) GetMedia(ctx context.Context, id string) (*entities.Media, error) {
 	ctx, span := telemetry.Start(ctx)
@@ -109,10 +163,7 @@ func (db *SQLite) GetMedia(ctx context.Context, id string) (*entities.Media, err
 		return nil, span.Assert(errors.Join(usecases.ErrStatusNotFound, err))
 	}
 
-	return &entities.Media{
-		SourceID: res.SourceID,
-		TargetID: res.TargetID,
-	}, nil
+	return newMedia(res), nil
 }
 
 // GetMediaBulk retrieves a set of media entries from the cache. It returns a
@@ -130,10 +181,7 @@ func (db *SQLite) GetMediaBulk(ctx context.Context, ids []string) ([]*entities.M
 	medias := make([]*entities.Media, 0, len(res))
 
 	for _, entry := range res {
-		medias = append(medias, &entities.Media{
-			SourceID: entry.SourceID,
-			TargetID: entry.TargetID,
-		})
+		medias = append(medias, newMedia(entry))
 	}
 
 	return medias, nil
@@ -149,10 +197,7 @@ func (db *SQLite) PutMedia(ctx context.Context, media *entities.Media) error {
 		return usecases.ErrStatusInvalidArgument
 	}
 
-	err := db.queries.PutMedia(ctx, model.PutMediaParams{
-		SourceID: media.SourceID,
-		TargetID: media.TargetID,
-	})
+	err := db.queries.PutMedia(ctx, newPutMediaParams(media))
 	if err != nil {
 		return errors.Join(usecases.ErrStatusFailedPrecondition, err)
 	}
@@ -178,24 +223,192 @@ func (db *SQLite) PutMediaBulk(ctx context.Context, medias []*entities.Media) er
 	}
 	defer tx.Rollback()
 
+	err = putMedias(ctx, db.queries.WithTx(tx), medias)
+	if err != nil {
+		return err
+	}
+
+	return span.Assert(usecases.ErrorJoinIf(
+		usecases.ErrStatusFailedPrecondition,
+		tx.Commit(),
+	))
+}
+
+// ReplaceMediaBulk deletes all media entries and stores the given ones. This
+// happens within a transaction, so other connections see either the previous
+// or the new entries. An error means no changes were made to the data.
+func (db *SQLite) ReplaceMediaBulk(ctx context.Context, medias []*entities.Media) error {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	tx, err := db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Join(usecases.ErrStatusFailedPrecondition, err)
+	}
+	defer tx.Rollback()
+
 	qtx := db.queries.WithTx(tx)
 
+	err = qtx.DeleteMedias(ctx)
+	if err != nil {
+		return span.Assert(errors.Join(usecases.ErrStatusAborted, err))
+	}
+
+	err = putMedias(ctx, qtx, medias)
+	if err != nil {
+		return span.Assert(err)
+	}
+
+	return span.Assert(usecases.ErrorJoinIf(
+		usecases.ErrStatusFailedPrecondition,
+		tx.Commit(),
+	))
+}
+
+// GetToken retrieves the token of an user by their ID.
+func (db *SQLite) GetToken(ctx context.Context, userID string) (*entities.Token, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	res, err := db.queries.GetToken(ctx, userID)
+	if err != nil {
+		return nil, span.Assert(tokenError(err))
+	}
+
+	return newToken(res), nil
+}
+
+// GetTokenByName retrieves the token of an user by their name, regardless of
+// its case.
+func (db *SQLite) GetTokenByName(ctx context.Context, name string) (*entities.Token, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	res, err := db.queries.GetTokenByName(ctx, name)
+	if err != nil {
+		return nil, span.Assert(tokenError(err))
+	}
+
+	return newToken(res), nil
+}
+
+// PutToken stores the token of an user, replacing any previous one as well as
+// any other user token with the same name. It errors if the token is invalid.
+func (db *SQLite) PutToken(ctx context.Context, token *entities.Token) error {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if !token.Valid() {
+		return span.Assert(usecases.ErrStatusInvalidArgument)
+	}
+
+	var expiresAt sql.NullInt64
+
+	if !token.ExpiresAt.IsZero() {
+		expiresAt.Int64 = token.ExpiresAt.UnixNano()
+		expiresAt.Valid = true
+	}
+
+	return span.Assert(usecases.ErrorJoinIf(
+		usecases.ErrStatusFailedPrecondition,
+		db.queries.PutToken(ctx, model.PutTokenParams{
+			UserID:      token.UserID,
+			UserName:    token.UserName,
+			AccessToken: token.AccessToken,
+			ExpiresAt:   expiresAt,
+		}),
+	))
+}
+
+// sweep deletes expired cache entries every interval until the driver closes.
+func (db *SQLite) sweep(interval time.Duration) {
+	defer db.sweeping.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.done:
+			return
+		case <-ticker.C:
+			//nolint:errcheck // failures are traced and retried on the next tick
+			_ = db.Sweep(context.Background())
+		}
+	}
+}
+
+// putMedias validates and stores medias through queries.
+func putMedias(ctx context.Context, queries *model.Queries, medias []*entities.Media) error {
 	for _, media := range medias {
 		if !media.Valid() {
 			return usecases.ErrStatusInvalidArgument
 		}
 
-		err = qtx.PutMedia(ctx, model.PutMediaParams{
-			SourceID: media.SourceID,
-			TargetID: media.TargetID,
-		})
+		err := queries.PutMedia(ctx, newPutMediaParams(media))
 		if err != nil {
 			return errors.Join(usecases.ErrStatusAborted, err)
 		}
 	}
 
-	return span.Assert(usecases.ErrorJoinIf(
-		usecases.ErrStatusFailedPrecondition,
-		tx.Commit(),
-	))
+	return nil
+}
+
+// newMedia converts a media row into an entity.
+func newMedia(row model.Media) *entities.Media {
+	var targetSeason *uint64
+
+	if row.TargetSeason.Valid {
+		//nolint:gosec // seasons are always positive
+		season := uint64(row.TargetSeason.Int64)
+		targetSeason = &season
+	}
+
+	return &entities.Media{
+		SourceID:     row.SourceID,
+		TargetID:     row.TargetID,
+		TargetSeason: targetSeason,
+	}
+}
+
+// newToken converts a token row into an entity.
+func newToken(row model.Token) *entities.Token {
+	var expiresAt time.Time
+
+	if row.ExpiresAt.Valid {
+		expiresAt = time.Unix(0, row.ExpiresAt.Int64).UTC()
+	}
+
+	return &entities.Token{
+		ExpiresAt:   expiresAt,
+		UserID:      row.UserID,
+		UserName:    row.UserName,
+		AccessToken: row.AccessToken,
+	}
+}
+
+// tokenError converts a token query error.
+func tokenError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Join(usecases.ErrStatusNotFound, err)
+	}
+
+	return errors.Join(usecases.ErrStatusUnknown, err)
+}
+
+// newPutMediaParams converts a media entity into query parameters.
+func newPutMediaParams(media *entities.Media) model.PutMediaParams {
+	var targetSeason sql.NullInt64
+
+	if media.TargetSeason != nil {
+		//nolint:gosec // seasons are way below the int64 limit
+		targetSeason.Int64 = int64(*media.TargetSeason)
+		targetSeason.Valid = true
+	}
+
+	return model.PutMediaParams{
+		SourceID:     media.SourceID,
+		TargetID:     media.TargetID,
+		TargetSeason: targetSeason,
+	}
 }
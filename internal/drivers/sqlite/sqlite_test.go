@@ -4,13 +4,16 @@ package sqlite_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -100,6 +103,15 @@ func TestSQLite_Close(t *testing.T) {
 	}
 }
 
+func TestSQLite_Close_twice(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLite(t)
+
+	require.NoError(t, db.Close())
+	require.NoError(t, db.Close())
+}
+
 func TestSQLite_GetString(t *testing.T) {
 	t.Parallel()
 
@@ -246,9 +258,101 @@ func TestSQLite_SetString(t *testing.T) {
 	}
 }
 
+func TestSQLite_SetString_ttl(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLite(t)
+
+	err := db.SetString(t.Context(), "expired", "foo", usecases.WithTTL(time.Nanosecond))
+	require.NoError(t, err)
+
+	err = db.SetString(t.Context(), "fresh", "bar", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	_, err = db.GetString(t.Context(), "expired")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	got, err := db.GetString(t.Context(), "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+
+	// setting without a TTL makes it last forever
+	err = db.SetString(t.Context(), "expired", "foo")
+	require.NoError(t, err)
+
+	got, err = db.GetString(t.Context(), "expired")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got)
+}
+
+func TestSQLite_Sweep(t *testing.T) {
+	t.Parallel()
+
+	dataSourceName := filepath.Join(t.TempDir(), "sweep.db")
+
+	db, err := sqlite.New(t.Context(), dataSourceName)
+	require.NoError(t, err)
+
+	err = db.SetString(t.Context(), "expired", "foo", usecases.WithTTL(time.Nanosecond))
+	require.NoError(t, err)
+
+	err = db.SetString(t.Context(), "fresh", "bar", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	err = db.Sweep(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	handler, err := sql.Open("sqlite", dataSourceName)
+	require.NoError(t, err)
+
+	defer handler.Close()
+
+	var count int
+
+	err = handler.QueryRowContext(t.Context(), "SELECT COUNT(*) FROM cache").Scan(&count)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, count)
+}
+
+func TestNew_cache_without_expiry(t *testing.T) {
+	t.Parallel()
+
+	dataSourceName := filepath.Join(t.TempDir(), "legacy.db")
+
+	handler, err := sql.Open("sqlite", dataSourceName)
+	require.NoError(t, err)
+
+	_, err = handler.ExecContext(t.Context(), `CREATE TABLE cache (
+		key TEXT NOT NULL, value TEXT NOT NULL, PRIMARY KEY(key)
+	) WITHOUT ROWID, STRICT;
+	INSERT INTO cache (key, value) VALUES ('foo', 'bar');`)
+	require.NoError(t, err)
+	require.NoError(t, handler.Close())
+
+	db, err := sqlite.New(t.Context(), dataSourceName)
+	require.NoError(t, err)
+
+	defer closeSQLite(t, db)
+
+	got, err := db.GetString(t.Context(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", got)
+
+	err = db.SetString(t.Context(), "foo", "baz", usecases.WithTTL(time.Hour))
+	require.NoError(t, err)
+}
+
 func TestSQLite_GetMedia(t *testing.T) {
 	t.Parallel()
 
+	season := uint64(2)
+
 	type fields struct {
 		db *sqlite.SQLite
 	}
@@ -309,6 +413,28 @@ func TestSQLite_GetMedia(t *testing.T) {
 			},
 			assertError: require.NoError,
 		},
+		{
+			name: "success with season",
+			fields: fields{
+				db: compose(t, newSQLite(t), putMedias(
+					&entities.Media{
+						SourceID:     "foo",
+						TargetID:     "bar",
+						TargetSeason: &season,
+					},
+				)),
+			},
+			args: args{
+				ctx: t.Context(),
+				id:  "foo",
+			},
+			want: &entities.Media{
+				SourceID:     "foo",
+				TargetID:     "bar",
+				TargetSeason: &season,
+			},
+			assertError: require.NoError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -700,6 +826,81 @@ func TestSQLite_PutMediaBulk(t *testing.T) {
 	}
 }
 
+func TestSQLite_ReplaceMediaBulk(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLite(t)
+	ctx := t.Context()
+
+	err := db.PutMediaBulk(ctx, []*entities.Media{
+		{SourceID: "1", TargetID: "90"},
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "2", TargetID: "92"},
+	})
+	require.NoError(t, err)
+
+	err = db.ReplaceMediaBulk(ctx, []*entities.Media{
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "3", TargetID: "93"},
+	})
+	require.NoError(t, err)
+
+	got, err := db.GetMediaBulk(ctx, []string{"1", "2", "3"})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []*entities.Media{
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "3", TargetID: "93"},
+	}, got)
+
+	// invalid entries roll back the whole replacement
+	err = db.ReplaceMediaBulk(ctx, []*entities.Media{
+		{SourceID: "4", TargetID: "94"},
+		{SourceID: "5", TargetID: ""},
+	})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	got, err = db.GetMediaBulk(ctx, []string{"1", "3", "4"})
+	require.NoError(t, err)
+
+	assert.Len(t, got, 2)
+}
+
+func TestSQLite_ReplaceMediaBulk_error(t *testing.T) {
+	t.Parallel()
+
+	cancelledCtx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		ctx       context.Context
+		wantError error
+		name      string
+	}{
+		{
+			name:      "done context",
+			ctx:       cancelledCtx,
+			wantError: usecases.ErrStatusFailedPrecondition,
+		},
+		{
+			name:      "cancelled on delete",
+			ctx:       newInterruptCtx(t, 0, "modernc.org/sqlite.(*stmt).exec"),
+			wantError: usecases.ErrStatusAborted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newSQLite(t).ReplaceMediaBulk(tt.ctx, []*entities.Media{
+				{SourceID: "1", TargetID: "91"},
+			})
+			require.ErrorIs(t, err, tt.wantError)
+		})
+	}
+}
+
 func ExampleNew() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
@@ -760,7 +961,7 @@ func ExampleNew_schema_failure() {
 
 	// Output:
 	// DB: <nil>
-	// Error: failed to execute schema queries: attempt to write a readonly database (8)
+	// Error: failed to migrate schema: failed to create migrations table: attempt to write a readonly database (8)
 }
 
 func callerMatches(tb testing.TB, target string) bool {
@@ -800,6 +1001,55 @@ func compose(tb testing.TB, value *sqlite.SQLite, functors ...sqliteFunctor) *sq
 	return value
 }
 
+func TestSQLite_Token(t *testing.T) {
+	t.Parallel()
+
+	db := newSQLite(t)
+	ctx := t.Context()
+	token := entities.Token{
+		ExpiresAt:   time.Now().Add(time.Hour).UTC(),
+		UserID:      "1",
+		UserName:    "Foo",
+		AccessToken: "bar",
+	}
+
+	_, err := db.GetToken(ctx, token.UserID)
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	_, err = db.GetTokenByName(ctx, token.UserName)
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	err = db.PutToken(ctx, &entities.Token{UserID: "2"})
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	require.NoError(t, db.PutToken(ctx, &token))
+
+	got, err := db.GetToken(ctx, token.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, &token, got)
+
+	got, err = db.GetTokenByName(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, &token, got)
+
+	// renamed users no longer resolve by their previous name
+	renamed := token
+	renamed.ExpiresAt = time.Time{}
+	renamed.UserName = "baz"
+	renamed.AccessToken = "qux"
+
+	require.NoError(t, db.PutToken(ctx, &renamed))
+
+	_, err = db.GetTokenByName(ctx, "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	got, err = db.GetTokenByName(ctx, "BAZ")
+	require.NoError(t, err)
+	assert.Equal(t, &renamed, got)
+
+	require.NoError(t, db.Close())
+}
+
 //nolint:gocognit // needed to test cancelled context side-effects
 func newInterruptCtx(tb testing.TB, successes uint, caller string) *test.MockContext {
 	tb.Helper()
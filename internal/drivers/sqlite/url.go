@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+)
+
+// Scheme is the URL scheme of SQLite drivers.
+const Scheme = "sqlite"
+
+// Register adds SQLite as both a store and cache driver.
+func Register(drivers *registry.Drivers) {
+	registry.Register(&drivers.Stores, Scheme, FromURL)
+	registry.Register(&drivers.Caches, Scheme, FromURL)
+}
+
+// FromURL opens a SQLite database at the file within uri. The query is passed
+// to the driver as is, e.g. sqlite:///var/lib/anilistarr/cache.db?_pragma=...
+func FromURL(ctx context.Context, uri *url.URL) (*SQLite, error) {
+	return New(ctx, DataSourceName(uri))
+}
+
+// DataSourceName converts a SQLite URL into the data source name of the driver.
+func DataSourceName(uri *url.URL) string {
+	dataSourceName := registry.Path(uri)
+
+	if uri.RawQuery != "" {
+		dataSourceName += "?" + uri.RawQuery
+	}
+
+	return dataSourceName
+}
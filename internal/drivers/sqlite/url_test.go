@@ -0,0 +1,28 @@
+//go:build !pure
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/registry"
+	"github.com/wwmoraes/anilistarr/internal/drivers/sqlite"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	var drivers registry.Drivers
+
+	sqlite.Register(&drivers)
+
+	store, err := drivers.Stores.New(t.Context(), "sqlite:file::memory:")
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	cache, err := drivers.Caches.New(t.Context(), "sqlite:file::memory:?_pragma=query_only=true")
+	require.Error(t, err)
+	require.Nil(t, cache)
+}
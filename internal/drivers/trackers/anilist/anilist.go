@@ -7,10 +7,13 @@ package anilist
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"iter"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Khan/genqlient/graphql"
@@ -20,6 +23,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/time/rate"
 
+	"github.com/wwmoraes/anilistarr/internal/entities"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 	"github.com/wwmoraes/anilistarr/pkg/with"
 )
@@ -30,31 +34,65 @@ const (
 	interval time.Duration = time.Minute
 	// requests reflects the current Anilist API rate limits
 	// https://anilist.gitbook.io/anilist-apiv2-docs/overview/rate-limiting
-	requests        int = 30
-	defaultPageSize int = 10
+	requests           int = 30
+	defaultPageSize    int = 10
+	defaultConcurrency int = 4
+	// invalidToken is the error message upstream replies with to requests with
+	// an access token it does not accept.
+	invalidToken string = "Invalid token"
 )
 
-var _ io.Closer = (*Tracker)(nil)
+var (
+	// DefaultStatuses contains the media list statuses fetched by default.
+	DefaultStatuses = []MediaListStatus{MediaListStatusCurrent, MediaListStatusPlanning}
+
+	_ io.Closer = (*Tracker)(nil)
+)
+
+// pageResult contains the outcome of a page request.
+type pageResult struct {
+	response *GetWatchingResponse
+	err      error
+}
 
 // Tracker abstracts an Anilist GraphQL client and provides the common requests
 // needed by MediaLister
+//
+// Media lists include entries with one of the Statuses, or with one of the
+// [DefaultStatuses] if it is empty. Their pages get requested up to
+// Concurrency at a time, or one at a time if it is not positive.
+//
+// Requests about users with an unexpired token in Tokens authenticate as them,
+// which lets those with private profiles and lists be found. Tokens upstream
+// rejects get ignored. Tokens are optional.
 type Tracker struct {
-	Client   graphql.Client
-	PageSize int
+	Client      graphql.Client
+	Tokens      usecases.TokenStore
+	Statuses    []MediaListStatus
+	PageSize    int
+	Concurrency int
 }
 
 // Options contains optional settings for tracker instances.
 type Options struct {
-	Client   usecases.Doer
-	PageSize int
+	Client      usecases.Doer
+	Tokens      usecases.TokenStore
+	Retry       *Retry
+	Statuses    []MediaListStatus
+	PageSize    int
+	Concurrency int
 }
 
 // NewOptions generates an [Options] value ready to use. It starts with defaults
 // and then applies all [Option] in order.
 func NewOptions(opts ...with.Option[Options]) Options {
 	return with.Apply(Options{
-		PageSize: defaultPageSize,
-		Client:   http.DefaultClient,
+		PageSize:    defaultPageSize,
+		Client:      http.DefaultClient,
+		Statuses:    DefaultStatuses,
+		Retry:       nil,
+		Tokens:      nil,
+		Concurrency: defaultConcurrency,
 	}, opts...)
 }
 
@@ -65,6 +103,14 @@ func WithClient(client usecases.Doer) with.Functor[Options] {
 	})
 }
 
+// WithConcurrency sets how many pages of paginated requests to request at a
+// time.
+func WithConcurrency(concurrency int) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Concurrency = concurrency
+	})
+}
+
 // WithPageSize sets a custom page size for paginated requests.
 func WithPageSize(size int) with.Functor[Options] {
 	return with.Functor[Options](func(options *Options) {
@@ -72,8 +118,31 @@ func WithPageSize(size int) with.Functor[Options] {
 	})
 }
 
+// WithRetry enables the waiting mode of the [RatedClient] with the given
+// settings.
+func WithRetry(retry *Retry) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Retry = retry
+	})
+}
+
+// WithTokens sets the store of the user tokens to authenticate requests with.
+func WithTokens(tokens usecases.TokenStore) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Tokens = tokens
+	})
+}
+
+// WithStatuses sets which media list statuses to include in media lists.
+func WithStatuses(statuses ...MediaListStatus) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Statuses = statuses
+	})
+}
+
 // New creates an Anilist client that uses a [RatedClient] that respects the
-// upstream API limits.
+// upstream API limits. Concurrent page requests share its limiter.
+// Authenticated requests do as well, as upstream limits them per client.
 func New(endpoint string, opts ...with.Option[Options]) *Tracker {
 	options := NewOptions(opts...)
 
@@ -81,19 +150,29 @@ func New(endpoint string, opts ...with.Option[Options]) *Tracker {
 		Client: graphql.NewClient(
 			endpoint,
 			&RatedClient{
-				Doer:    options.Client,
+				Doer:    &bearerClient{Doer: options.Client},
 				Limiter: rate.NewLimiter(rate.Limit(requests)*rate.Every(interval), requests),
+				Retry:   options.Retry,
 			},
 		),
-		PageSize: options.PageSize,
+		Tokens:      options.Tokens,
+		Statuses:    options.Statuses,
+		PageSize:    options.PageSize,
+		Concurrency: options.Concurrency,
 	}
 }
 
-// GetUserID retrieves an user ID using their profile name.
+// GetUserID retrieves an user ID using their profile name. Users with a token
+// resolve as its viewer instead, which works for private profiles as well.
 func (tracker *Tracker) GetUserID(ctx context.Context, name string) (string, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
+	userID, ok := tracker.getViewerID(ctx, name)
+	if ok {
+		return userID, span.Assert(nil)
+	}
+
 	res, err := GetUserByName(ctx, tracker.Client, name)
 
 	gqlErrorList := gqlerror.List{}
@@ -103,14 +182,26 @@ func (tracker *Tracker) GetUserID(ctx context.Context, name string) (string, err
 	}
 
 	if err != nil {
-		return "", span.Assert(errors.Join(usecases.ErrStatusUnavailable, err))
+		return "", span.Assert(upstreamError(err))
 	}
 
 	return strconv.Itoa(res.User.Id), span.Assert(nil)
 }
 
-// GetMediaListIDs retrieves a list of medias from a user ID.
-func (tracker *Tracker) GetMediaListIDs(ctx context.Context, userID string) ([]string, error) {
+// GetMediaListIDs retrieves a list of medias from a user ID. It includes only
+// entries with one of the given statuses, which are case-insensitive
+// [MediaListStatus] values, or with one of the tracker statuses if none is
+// given. It fails with [usecases.ErrPartialMediaList] if a page other than the
+// first one fails.
+//
+// Users with a token get their list requested as themselves, which includes
+// private lists and entries. It falls back to the public list if upstream
+// rejects the token.
+func (tracker *Tracker) GetMediaListIDs(
+	ctx context.Context,
+	userID string,
+	statuses ...string,
+) ([]string, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
@@ -119,14 +210,49 @@ func (tracker *Tracker) GetMediaListIDs(ctx context.Context, userID string) ([]s
 		return nil, span.Assert(errors.Join(usecases.ErrStatusInvalidArgument, err))
 	}
 
-	anilistIDs := make([]string, 0, tracker.PageSize)
+	listStatuses, err := tracker.listStatuses(statuses)
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
 	span.SetAttributes(attribute.Int("page.size", tracker.PageSize))
 
-	for mediaID := range tracker.getMediaListIDs(ctx, userIDInt) {
-		anilistIDs = append(anilistIDs, strconv.Itoa(mediaID))
+	token := tracker.getToken(ctx, userID)
+	if token != nil {
+		anilistIDs, err := tracker.collectMediaListIDs(withBearer(ctx, token.AccessToken), userIDInt, listStatuses)
+		if !rejected(err) {
+			return anilistIDs, span.Assert(err)
+		}
+
+		telemetry.Logr(ctx).Info("token rejected, requesting public media list", "userID", userID)
+	}
+
+	anilistIDs, err := tracker.collectMediaListIDs(ctx, userIDInt, listStatuses)
+
+	return anilistIDs, span.Assert(err)
+}
+
+// Viewer retrieves the user an access token belongs to. The token it returns
+// has no expiry set.
+func (tracker *Tracker) Viewer(ctx context.Context, accessToken string) (*entities.Token, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	res, err := GetViewer(withBearer(ctx, accessToken), tracker.Client)
+	if rejected(err) {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusUnauthenticated, err))
+	}
+
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusUnavailable, err))
 	}
 
-	return anilistIDs, span.Assert(nil)
+	return &entities.Token{
+		ExpiresAt:   time.Time{},
+		UserID:      strconv.Itoa(res.Viewer.Id),
+		UserName:    res.Viewer.Name,
+		AccessToken: accessToken,
+	}, span.Assert(nil)
 }
 
 // Close terminates the client to the upstream API.
@@ -136,41 +262,211 @@ func (tracker *Tracker) Close() error {
 	return nil
 }
 
-//nolint:gocognit // gotta have those short-circuit returns ¯\_(ツ)_/¯
-func (tracker *Tracker) getMediaListIDs(ctx context.Context, userID int) iter.Seq[int] {
-	span := telemetry.SpanFromContext(ctx)
+// collectMediaListIDs collects all media IDs of the media list of an user.
+func (tracker *Tracker) collectMediaListIDs(
+	ctx context.Context,
+	userID int,
+	statuses []MediaListStatus,
+) ([]string, error) {
+	anilistIDs := make([]string, 0, tracker.PageSize)
+
+	for mediaID, err := range tracker.getMediaListIDs(ctx, userID, statuses) {
+		if err != nil {
+			return nil, err
+		}
+
+		anilistIDs = append(anilistIDs, strconv.Itoa(mediaID))
+	}
+
+	return anilistIDs, nil
+}
+
+// getViewerID resolves the ID of an user through the viewer of their token. It
+// returns false if the user has no usable token, or if it belongs to someone
+// else by now, as users may rename themselves.
+func (tracker *Tracker) getViewerID(ctx context.Context, name string) (string, bool) {
+	if tracker.Tokens == nil {
+		return "", false
+	}
+
+	token, err := tracker.Tokens.GetTokenByName(ctx, name)
+
+	token = usableToken(ctx, token, err)
+	if token == nil {
+		return "", false
+	}
+
+	viewer, err := tracker.Viewer(ctx, token.AccessToken)
+	if err != nil {
+		telemetry.Logr(ctx).Error(err, "failed to resolve token viewer", "name", name)
+
+		return "", false
+	}
+
+	return viewer.UserID, strings.EqualFold(viewer.UserName, name)
+}
+
+// getToken returns the usable token of an user, if any.
+func (tracker *Tracker) getToken(ctx context.Context, userID string) *entities.Token {
+	if tracker.Tokens == nil {
+		return nil
+	}
+
+	token, err := tracker.Tokens.GetToken(ctx, userID)
+
+	return usableToken(ctx, token, err)
+}
+
+// listStatuses parses statuses into [MediaListStatus] values. It returns the
+// tracker statuses if there's none to parse.
+func (tracker *Tracker) listStatuses(statuses []string) ([]MediaListStatus, error) {
+	if len(statuses) == 0 && len(tracker.Statuses) == 0 {
+		return DefaultStatuses, nil
+	}
 
-	return func(yield func(int) bool) {
-		for page := 1; ; page++ {
-			res, err := tracker.getWatchingPage(ctx, userID, page)
-			if err != nil {
-				//nolint:errcheck // logged in span
-				span.Assert(errors.Join(usecases.ErrStatusUnknown, err))
+	if len(statuses) == 0 {
+		return tracker.Statuses, nil
+	}
+
+	listStatuses := make([]MediaListStatus, 0, len(statuses))
+
+	for _, status := range statuses {
+		listStatus := MediaListStatus(strings.ToUpper(status))
+		if !slices.Contains(AllMediaListStatus, listStatus) {
+			return nil, fmt.Errorf("%w: unknown list status %q", usecases.ErrStatusInvalidArgument, status)
+		}
 
+		listStatuses = append(listStatuses, listStatus)
+	}
+
+	return listStatuses, nil
+}
+
+// getMediaListIDs iterates over all media list entries of an user, in order.
+// It requests the first page, and then the remaining ones upstream tells of
+// concurrently. It keeps requesting pages one at a time after those until there
+// are no entries left, as the last page may change meanwhile. Failed requests
+// yield an error, which wraps [usecases.ErrPartialMediaList] if earlier pages
+// succeeded.
+func (tracker *Tracker) getMediaListIDs(
+	ctx context.Context,
+	userID int,
+	statuses []MediaListStatus,
+) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		page := 1
+
+		last, err := tracker.getWatchingPage(ctx, userID, page, statuses)
+		if !yieldPage(page, last, err, yield) {
+			return
+		}
+
+		for res, err := range tracker.getWatchingPages(ctx, userID, statuses, page+1, last.Page.PageInfo.LastPage) {
+			page++
+
+			if !yieldPage(page, res, err, yield) {
 				return
 			}
 
-			for _, entry := range res.Page.MediaList {
-				if !yield(entry.Media.Id) {
-					return
-				}
+			last = res
+		}
+
+		tracker.yieldPagesAfter(ctx, userID, statuses, page, last, yield)
+	}
+}
 
-				if ctx.Err() != nil {
-					return
-				}
-			}
+// yieldPagesAfter requests the pages after page one at a time, yielding their
+// media IDs. It stops at the first page that is not full.
+func (tracker *Tracker) yieldPagesAfter(
+	ctx context.Context,
+	userID int,
+	statuses []MediaListStatus,
+	page int,
+	previous *GetWatchingResponse,
+	yield func(int, error) bool,
+) {
+	var err error
+
+	// avoid doing requests after exhausting records
+	for len(previous.Page.MediaList) >= tracker.PageSize {
+		page++
+
+		previous, err = tracker.getWatchingPage(ctx, userID, page, statuses)
+		if !yieldPage(page, previous, err, yield) {
+			return
+		}
+	}
+}
+
+// getWatchingPages requests the pages from first to last up to Concurrency at
+// a time. It yields their responses in order. Requests still pending get
+// abandoned once the iteration stops.
+func (tracker *Tracker) getWatchingPages(
+	ctx context.Context,
+	userID int,
+	statuses []MediaListStatus,
+	first, last int,
+) iter.Seq2[*GetWatchingResponse, error] {
+	return func(yield func(*GetWatchingResponse, error) bool) {
+		if last < first {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// each page has its own buffered channel, so requests neither block nor
+		// leak once the iteration stops
+		results := make([]chan pageResult, last-first+1)
+		for index := range results {
+			results[index] = make(chan pageResult, 1)
+		}
 
-			// avoid doing requests after exhausting records
-			if len(res.Page.MediaList) < tracker.PageSize {
+		go tracker.requestPages(ctx, userID, statuses, first, results)
+
+		for _, result := range results {
+			res := <-result
+			if !yield(res.response, res.err) {
 				return
 			}
 		}
 	}
 }
 
+// requestPages requests a page per result, starting at first, up to
+// Concurrency at a time. Pages not requested yet once ctx is done get its
+// error instead.
+func (tracker *Tracker) requestPages(
+	ctx context.Context,
+	userID int,
+	statuses []MediaListStatus,
+	first int,
+	results []chan pageResult,
+) {
+	slots := make(chan struct{}, max(tracker.Concurrency, 1))
+
+	for index, result := range results {
+		select {
+		case <-ctx.Done():
+			result <- pageResult{response: nil, err: ctx.Err()}
+
+			continue
+		case slots <- struct{}{}:
+		}
+
+		go func() {
+			defer func() { <-slots }()
+
+			res, err := tracker.getWatchingPage(ctx, userID, first+index, statuses)
+			result <- pageResult{response: res, err: err}
+		}()
+	}
+}
+
 func (tracker *Tracker) getWatchingPage(
 	ctx context.Context,
 	userID, page int,
+	statuses []MediaListStatus,
 ) (*GetWatchingResponse, error) {
 	ctx, span := telemetry.StartNamed(
 		ctx,
@@ -183,7 +479,102 @@ func (tracker *Tracker) getWatchingPage(
 	log := telemetry.Logr(ctx)
 
 	log.Info("requesting media list", "page", page)
-	res, err := GetWatching(ctx, tracker.Client, userID, page, tracker.PageSize)
+	res, err := GetWatching(ctx, tracker.Client, statuses, userID, page, tracker.PageSize)
 
 	return res, span.Assert(err)
 }
+
+// yieldPage yields the media IDs of a page response, or its error. It returns
+// false if the iteration must stop.
+func yieldPage(
+	page int,
+	res *GetWatchingResponse,
+	err error,
+	yield func(int, error) bool,
+) bool {
+	if err != nil {
+		yield(0, pageError(page, err))
+
+		return false
+	}
+
+	return yieldEntries(res.Page.MediaList, yield)
+}
+
+// yieldEntries yields the media IDs of entries. It returns false if the
+// consumer stops the iteration.
+func yieldEntries(entries []GetWatchingPageMediaList, yield func(int, error) bool) bool {
+	for _, entry := range entries {
+		if !yield(entry.Media.Id, nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// usableToken returns the token of a lookup unless it failed or the token
+// expired. Failures other than missing tokens get logged.
+func usableToken(ctx context.Context, token *entities.Token, err error) *entities.Token {
+	if errors.Is(err, usecases.ErrStatusNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		telemetry.Logr(ctx).Error(err, "failed to get token")
+
+		return nil
+	}
+
+	if token.Expired(time.Now()) {
+		return nil
+	}
+
+	return token
+}
+
+// rejected tells if upstream rejected the access token of a request.
+func rejected(err error) bool {
+	var (
+		httpErr *graphql.HTTPError
+		errs    gqlerror.List
+	)
+
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == http.StatusUnauthorized {
+			return true
+		}
+
+		errs = httpErr.Response.Errors
+	} else if !errors.As(err, &errs) {
+		return false
+	}
+
+	return slices.ContainsFunc(errs, func(err *gqlerror.Error) bool {
+		return err.Message == invalidToken
+	})
+}
+
+// pageError wraps the error of a page request. Those past the first page mean
+// the media list is partial.
+func pageError(page int, err error) error {
+	err = upstreamError(err)
+
+	if page == 1 {
+		return err
+	}
+
+	return fmt.Errorf("%w: failed on page %d: %w", usecases.ErrPartialMediaList, page, err)
+}
+
+// upstreamError wraps the error of a request, which is either rejected by the
+// rate limits or due to the tracker being unavailable.
+func upstreamError(err error) error {
+	var httpErr *graphql.HTTPError
+
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return errors.Join(usecases.ErrStatusResourceExhausted, err)
+	}
+
+	return errors.Join(usecases.ErrStatusUnavailable, err)
+}
@@ -5,11 +5,14 @@ package anilist_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Khan/genqlient/graphql"
 	"github.com/goccy/go-json"
@@ -17,12 +20,39 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	"golang.org/x/time/rate"
 
 	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/anilist"
 	"github.com/wwmoraes/anilistarr/internal/test"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
+// mediaListServer is a fake Anilist GraphQL API that serves media lists with
+// IDs from 1 to entries. It tells lastPage as the last page regardless.
+type mediaListServer struct {
+	*httptest.Server
+
+	requests atomic.Int32
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	entries  int
+	lastPage int
+	failPage int
+	latency  time.Duration
+}
+
+// mediaListRequest contains the variables of a GetWatching request.
+type mediaListRequest struct {
+	Variables json.RawMessage `json:"variables"`
+}
+
+// mediaListVariables contains the pagination variables of a GetWatching
+// request.
+type mediaListVariables struct {
+	Page    int `json:"page"`
+	PerPage int `json:"perPage"`
+}
+
 func TestTracker_GetMediaListIDs(t *testing.T) {
 	t.Parallel()
 
@@ -132,14 +162,15 @@ func TestTracker(t *testing.T) {
 			OpName: "GetWatching",
 			Query:  anilist.GetWatching_Operation,
 			Variables: &struct {
-				//nolint:tagliatelle // upstream format
-				UserID  int `json:"userId"`
-				Page    int `json:"page"`
-				PerPage int `json:"perPage"`
+				Statuses []anilist.MediaListStatus `json:"statuses"`
+				UserID   int                       `json:"userId"` //nolint:tagliatelle // upstream format
+				Page     int                       `json:"page"`
+				PerPage  int                       `json:"perPage"`
 			}{
-				UserID:  userID,
-				Page:    1,
-				PerPage: 10,
+				UserID:   userID,
+				Page:     1,
+				PerPage:  10,
+				Statuses: anilist.DefaultStatuses,
 			},
 		}),
 	).Return(
@@ -181,6 +212,143 @@ func TestTracker(t *testing.T) {
 	transport.AssertExpectations(t)
 }
 
+func TestTracker_GetMediaListIDs_statuses(t *testing.T) {
+	t.Parallel()
+
+	userID := 1
+	transport := test.NewMockRoundTripper(t)
+
+	transport.EXPECT().RoundTrip(
+		httpRequestWithJSONBody(t, graphql.Request{
+			OpName: "GetWatching",
+			Query:  anilist.GetWatching_Operation,
+			Variables: &struct {
+				Statuses []anilist.MediaListStatus `json:"statuses"`
+				UserID   int                       `json:"userId"` //nolint:tagliatelle // upstream format
+				Page     int                       `json:"page"`
+				PerPage  int                       `json:"perPage"`
+			}{
+				UserID:  userID,
+				Page:    1,
+				PerPage: 10,
+				Statuses: []anilist.MediaListStatus{
+					anilist.MediaListStatusCompleted,
+					anilist.MediaListStatusPaused,
+				},
+			},
+		}),
+	).Return(
+		//nolint:bodyclose // client transport closes it
+		httpResponseWithJSONBody(t, graphql.Response{
+			Data: &anilist.GetWatchingResponse{
+				Page: anilist.GetWatchingPage{
+					MediaList: []anilist.GetWatchingPageMediaList{
+						{Media: anilist.GetWatchingPageMediaListMedia{Id: 11}},
+					},
+				},
+			},
+		}),
+		nil,
+	).Once()
+
+	client := anilist.New(
+		"http://example.com",
+		anilist.WithClient(&http.Client{
+			Transport: transport,
+		}),
+		anilist.WithPageSize(10),
+	)
+	defer client.Close()
+
+	got, err := client.GetMediaListIDs(t.Context(), strconv.Itoa(userID), "COMPLETED", "paused")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"11"}, got)
+}
+
+func TestTracker_GetMediaListIDs_page_error(t *testing.T) {
+	t.Parallel()
+
+	errTransport := errors.New("connection reset")
+
+	tests := []struct {
+		wantError error
+		name      string
+		failPage  int
+	}{
+		{
+			name:      "first page",
+			failPage:  1,
+			wantError: usecases.ErrStatusUnavailable,
+		},
+		{
+			name:      "later page",
+			failPage:  3,
+			wantError: usecases.ErrPartialMediaList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			transport := test.NewMockRoundTripper(t)
+
+			for page := 1; page < tt.failPage; page++ {
+				transport.EXPECT().RoundTrip(mock.Anything).Return(
+					//nolint:bodyclose // client transport closes it
+					httpResponseWithJSONBody(t, graphql.Response{
+						Data: &anilist.GetWatchingResponse{
+							Page: anilist.GetWatchingPage{
+								MediaList: []anilist.GetWatchingPageMediaList{
+									{Media: anilist.GetWatchingPageMediaListMedia{Id: page}},
+								},
+							},
+						},
+					}),
+					nil,
+				).Once()
+			}
+
+			transport.EXPECT().RoundTrip(mock.Anything).Return(nil, errTransport).Once()
+
+			client := anilist.New(
+				"http://example.com",
+				anilist.WithClient(&http.Client{
+					Transport: transport,
+				}),
+				anilist.WithPageSize(1),
+			)
+			defer client.Close()
+
+			got, err := client.GetMediaListIDs(t.Context(), "1")
+			require.ErrorIs(t, err, tt.wantError)
+			require.ErrorIs(t, err, errTransport)
+
+			if tt.failPage == 1 {
+				require.NotErrorIs(t, err, usecases.ErrPartialMediaList)
+			}
+
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestTracker_GetMediaListIDs_invalid_status(t *testing.T) {
+	t.Parallel()
+
+	client := anilist.New(
+		"http://example.com",
+		anilist.WithClient(test.NewMockDoer(t)),
+	)
+	defer client.Close()
+
+	got, err := client.GetMediaListIDs(t.Context(), "1", "WATCHING")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	assert.Nil(t, got)
+}
+
 func TestTracker_GetUserID_not_found(t *testing.T) {
 	t.Parallel()
 
@@ -301,6 +469,33 @@ func TestTracker_GetUserID_unavailable(t *testing.T) {
 	transport.AssertExpectations(t)
 }
 
+func TestTracker_GetUserID_rate_limited(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	recorder.WriteHeader(http.StatusTooManyRequests)
+
+	transport := test.MockRoundTripper{}
+	//nolint:bodyclose // client transport closes it
+	transport.On("RoundTrip", mock.Anything).Return(recorder.Result(), nil).Once()
+
+	client := anilist.New(
+		"http://example.com",
+		anilist.WithClient(&http.Client{
+			Transport: &transport,
+		}),
+	)
+	defer client.Close()
+
+	got, err := client.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusResourceExhausted)
+	require.NotErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+	assert.Empty(t, got)
+
+	transport.AssertExpectations(t)
+}
+
 func httpResponseWithJSONBody(tb testing.TB, body any) *http.Response {
 	tb.Helper()
 
@@ -348,3 +543,191 @@ func httpRequestWithJSONBody(tb testing.TB, body any) any {
 		return bytes.Equal(data.Bytes(), want)
 	})
 }
+
+func TestTracker_GetMediaListIDs_concurrent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		entries      int
+		lastPage     int
+		concurrency  int
+		wantRequests int32
+	}{
+		{
+			name:         "serial",
+			entries:      23,
+			lastPage:     5,
+			concurrency:  1,
+			wantRequests: 5,
+		},
+		{
+			name:         "concurrent",
+			entries:      23,
+			lastPage:     5,
+			concurrency:  3,
+			wantRequests: 5,
+		},
+		{
+			name:         "full last page",
+			entries:      25,
+			lastPage:     5,
+			concurrency:  3,
+			wantRequests: 6,
+		},
+		{
+			name:         "outdated last page",
+			entries:      23,
+			lastPage:     2,
+			concurrency:  3,
+			wantRequests: 5,
+		},
+		{
+			name:         "unknown last page",
+			entries:      23,
+			lastPage:     0,
+			concurrency:  3,
+			wantRequests: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newMediaListServer(tt.entries, tt.lastPage, 0)
+			defer server.Close()
+
+			tracker := anilist.Tracker{
+				Client:      graphql.NewClient(server.URL, server.Client()),
+				PageSize:    5,
+				Concurrency: tt.concurrency,
+			}
+
+			got, err := tracker.GetMediaListIDs(t.Context(), "1")
+			require.NoError(t, err)
+
+			want := make([]string, 0, tt.entries)
+			for id := 1; id <= tt.entries; id++ {
+				want = append(want, strconv.Itoa(id))
+			}
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, tt.wantRequests, server.requests.Load())
+			assert.LessOrEqual(t, server.peak.Load(), int32(tt.concurrency))
+		})
+	}
+}
+
+func TestTracker_GetMediaListIDs_concurrent_page_error(t *testing.T) {
+	t.Parallel()
+
+	server := newMediaListServer(50, 10, 0)
+	defer server.Close()
+
+	server.failPage = 4
+
+	tracker := anilist.Tracker{
+		Client:      graphql.NewClient(server.URL, server.Client()),
+		PageSize:    5,
+		Concurrency: 4,
+	}
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "1")
+	require.ErrorIs(t, err, usecases.ErrPartialMediaList)
+	require.ErrorContains(t, err, "failed on page 4")
+
+	assert.Nil(t, got)
+}
+
+func BenchmarkTracker_GetMediaListIDs(b *testing.B) {
+	// 1000 entries take 20 pages of 50 entries
+	server := newMediaListServer(1000, 20, 5*time.Millisecond)
+	defer server.Close()
+
+	for _, concurrency := range []int{1, 4, 8} {
+		b.Run("concurrency="+strconv.Itoa(concurrency), func(b *testing.B) {
+			tracker := anilist.Tracker{
+				Client: graphql.NewClient(server.URL, &anilist.RatedClient{
+					Doer:    server.Client(),
+					Limiter: rate.NewLimiter(rate.Inf, 0),
+					Retry:   nil,
+				}),
+				PageSize:    50,
+				Concurrency: concurrency,
+			}
+
+			for b.Loop() {
+				//nolint:errcheck // benchmarking
+				tracker.GetMediaListIDs(b.Context(), "1")
+			}
+		})
+	}
+}
+
+func newMediaListServer(entries, lastPage int, latency time.Duration) *mediaListServer {
+	server := &mediaListServer{
+		entries:  entries,
+		lastPage: lastPage,
+		latency:  latency,
+	}
+
+	server.Server = httptest.NewServer(server)
+
+	return server
+}
+
+func (server *mediaListServer) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	server.requests.Add(1)
+	server.enter()
+
+	defer server.inFlight.Add(-1)
+
+	var (
+		request   mediaListRequest
+		variables mediaListVariables
+	)
+
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err == nil {
+		err = json.Unmarshal(request.Variables, &variables)
+	}
+
+	if err != nil || variables.Page == server.failPage {
+		writer.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	time.Sleep(server.latency)
+
+	mediaList := []anilist.GetWatchingPageMediaList{}
+
+	first := (variables.Page-1)*variables.PerPage + 1
+	for id := first; id <= min(first+variables.PerPage-1, server.entries); id++ {
+		mediaList = append(mediaList, anilist.GetWatchingPageMediaList{
+			Media: anilist.GetWatchingPageMediaListMedia{Id: id},
+		})
+	}
+
+	//nolint:errcheck,errchkjson // test server
+	json.NewEncoder(writer).Encode(graphql.Response{
+		Data: &anilist.GetWatchingResponse{
+			Page: anilist.GetWatchingPage{
+				PageInfo:  anilist.GetWatchingPagePageInfo{LastPage: server.lastPage},
+				MediaList: mediaList,
+			},
+		},
+	})
+}
+
+// enter counts a request in flight, keeping track of the peak.
+func (server *mediaListServer) enter() {
+	inFlight := server.inFlight.Add(1)
+
+	for peak := server.peak.Load(); inFlight > peak; peak = server.peak.Load() {
+		if server.peak.CompareAndSwap(peak, inFlight) {
+			break
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package anilist
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+var _ graphql.Doer = (*bearerClient)(nil)
+
+// bearerKey is the context key of the access token requests authenticate with.
+type bearerKey struct{}
+
+// bearerClient authenticates requests whose context carries an access token,
+// which lets them see private data of its user.
+type bearerClient struct {
+	usecases.Doer
+}
+
+// Do executes a HTTP request, setting its Authorization header if its context
+// carries an access token.
+func (client *bearerClient) Do(req *http.Request) (*http.Response, error) {
+	accessToken, ok := req.Context().Value(bearerKey{}).(string)
+	if ok && accessToken != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	//nolint:wrapcheck // passthrough
+	return client.Doer.Do(req)
+}
+
+// withBearer returns a copy of ctx whose requests authenticate with the access
+// token.
+func withBearer(ctx context.Context, accessToken string) context.Context {
+	return context.WithValue(ctx, bearerKey{}, accessToken)
+}
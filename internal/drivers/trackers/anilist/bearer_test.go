@@ -0,0 +1,333 @@
+package anilist_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/anilist"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// viewerAccessToken is the only access token [viewerServer] accepts.
+const viewerAccessToken = "secret"
+
+var errTokenStore = errors.New("token store failure")
+
+// viewerServer is a fake Anilist GraphQL API that knows of a private user Foo
+// with ID 1, whose access token is [viewerAccessToken], and of a public user
+// with ID 2.
+type viewerServer struct {
+	*httptest.Server
+
+	watching atomic.Int32
+}
+
+// viewerRequest contains the operation name and variables of a request.
+type viewerRequest struct {
+	OpName    string          `json:"operationName"` //nolint:tagliatelle // upstream format
+	Variables viewerVariables `json:"variables"`
+}
+
+// viewerVariables contains the variables of a request that tell users apart.
+type viewerVariables struct {
+	UserID int `json:"userId"` //nolint:tagliatelle // upstream format
+}
+
+func TestTracker_GetUserID_token(t *testing.T) {
+	t.Parallel()
+
+	token := &entities.Token{
+		UserID:      "1",
+		UserName:    "Foo",
+		AccessToken: viewerAccessToken,
+	}
+
+	tests := []struct {
+		token   *entities.Token
+		err     error
+		wantErr error
+		name    string
+		want    string
+	}{
+		{
+			name:  "private user",
+			token: token,
+			want:  "1",
+		},
+		{
+			name:    "no token",
+			err:     usecases.ErrStatusNotFound,
+			wantErr: usecases.ErrStatusNotFound,
+		},
+		{
+			name:    "token store failure",
+			err:     errTokenStore,
+			wantErr: usecases.ErrStatusNotFound,
+		},
+		{
+			name: "expired token",
+			token: &entities.Token{
+				ExpiresAt:   time.Now().Add(-time.Minute),
+				UserID:      token.UserID,
+				UserName:    token.UserName,
+				AccessToken: token.AccessToken,
+			},
+			wantErr: usecases.ErrStatusNotFound,
+		},
+		{
+			name: "rejected token",
+			token: &entities.Token{
+				UserID:      token.UserID,
+				UserName:    token.UserName,
+				AccessToken: "revoked",
+			},
+			wantErr: usecases.ErrStatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newViewerServer(t)
+
+			tokens := test.NewMockTokenStore(t)
+			tokens.EXPECT().GetTokenByName(mock.Anything, "foo").Return(tt.token, tt.err).Once()
+
+			tracker := anilist.New(server.URL,
+				anilist.WithClient(server.Client()),
+				anilist.WithTokens(tokens),
+			)
+
+			got, err := tracker.GetUserID(t.Context(), "foo")
+			require.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTracker_GetUserID_renamed(t *testing.T) {
+	t.Parallel()
+
+	server := newViewerServer(t)
+
+	// Foo used to be called bar when authorizing
+	tokens := test.NewMockTokenStore(t)
+	tokens.EXPECT().GetTokenByName(mock.Anything, "bar").Return(&entities.Token{
+		UserID:      "1",
+		UserName:    "bar",
+		AccessToken: viewerAccessToken,
+	}, nil).Once()
+
+	tracker := anilist.New(server.URL,
+		anilist.WithClient(server.Client()),
+		anilist.WithTokens(tokens),
+	)
+
+	_, err := tracker.GetUserID(t.Context(), "bar")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+}
+
+func TestTracker_GetMediaListIDs_token(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		token        *entities.Token
+		err          error
+		wantErr      error
+		name         string
+		userID       string
+		want         []string
+		wantWatching int32
+	}{
+		{
+			name:   "private list",
+			userID: "1",
+			token: &entities.Token{
+				UserID:      "1",
+				UserName:    "Foo",
+				AccessToken: viewerAccessToken,
+			},
+			want:         []string{"1", "2"},
+			wantWatching: 1,
+		},
+		{
+			name:         "private list without token",
+			userID:       "1",
+			err:          usecases.ErrStatusNotFound,
+			wantErr:      usecases.ErrStatusUnavailable,
+			wantWatching: 1,
+		},
+		{
+			name:         "public list with token store failure",
+			userID:       "2",
+			err:          errTokenStore,
+			want:         []string{"3"},
+			wantWatching: 1,
+		},
+		{
+			name:   "public list with rejected token",
+			userID: "2",
+			token: &entities.Token{
+				UserID:      "2",
+				UserName:    "Bar",
+				AccessToken: "revoked",
+			},
+			want:         []string{"3"},
+			wantWatching: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newViewerServer(t)
+
+			tokens := test.NewMockTokenStore(t)
+			tokens.EXPECT().GetToken(mock.Anything, tt.userID).Return(tt.token, tt.err).Once()
+
+			tracker := anilist.New(server.URL,
+				anilist.WithClient(server.Client()),
+				anilist.WithTokens(tokens),
+			)
+
+			got, err := tracker.GetMediaListIDs(t.Context(), tt.userID)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantWatching, server.watching.Load())
+		})
+	}
+}
+
+func TestTracker_Viewer(t *testing.T) {
+	t.Parallel()
+
+	server := newViewerServer(t)
+
+	tracker := anilist.New(server.URL, anilist.WithClient(server.Client()))
+
+	got, err := tracker.Viewer(t.Context(), viewerAccessToken)
+	require.NoError(t, err)
+
+	assert.Equal(t, &entities.Token{
+		UserID:      "1",
+		UserName:    "Foo",
+		AccessToken: viewerAccessToken,
+	}, got)
+
+	_, err = tracker.Viewer(t.Context(), "revoked")
+	require.ErrorIs(t, err, usecases.ErrStatusUnauthenticated)
+}
+
+func newViewerServer(tb testing.TB) *viewerServer {
+	tb.Helper()
+
+	server := &viewerServer{}
+	server.Server = httptest.NewServer(server)
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+func (server *viewerServer) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	var request viewerRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if request.OpName == "GetWatching" {
+		server.watching.Add(1)
+	}
+
+	authorization := req.Header.Get("Authorization")
+	if authorization != "" && authorization != "Bearer "+viewerAccessToken {
+		writeGraphQLError(writer, http.StatusBadRequest, "Invalid token")
+
+		return
+	}
+
+	switch request.OpName {
+	case "GetViewer":
+		serveViewer(writer, authorization)
+	case "GetUserByName":
+		writeGraphQLError(writer, http.StatusOK, "Not Found.")
+	case "GetWatching":
+		serveWatching(writer, request.Variables.UserID, authorization)
+	default:
+		writer.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func serveViewer(writer http.ResponseWriter, authorization string) {
+	if authorization == "" {
+		writeGraphQLError(writer, http.StatusUnauthorized, "Unauthorized.")
+
+		return
+	}
+
+	writeGraphQLData(writer, &anilist.GetViewerResponse{
+		Viewer: anilist.GetViewerViewerUser{Id: 1, Name: "Foo"},
+	})
+}
+
+func serveWatching(writer http.ResponseWriter, userID int, authorization string) {
+	ids := []int{3}
+
+	if userID == 1 {
+		if authorization == "" {
+			writeGraphQLError(writer, http.StatusNotFound, "Private User")
+
+			return
+		}
+
+		ids = []int{1, 2}
+	}
+
+	mediaList := make([]anilist.GetWatchingPageMediaList, 0, len(ids))
+	for _, id := range ids {
+		mediaList = append(mediaList, anilist.GetWatchingPageMediaList{
+			Media: anilist.GetWatchingPageMediaListMedia{Id: id},
+		})
+	}
+
+	writeGraphQLData(writer, &anilist.GetWatchingResponse{
+		Page: anilist.GetWatchingPage{
+			PageInfo:  anilist.GetWatchingPagePageInfo{LastPage: 1},
+			MediaList: mediaList,
+		},
+	})
+}
+
+func writeGraphQLData(writer http.ResponseWriter, data any) {
+	//nolint:errcheck,errchkjson // test server
+	json.NewEncoder(writer).Encode(graphql.Response{Data: data})
+}
+
+func writeGraphQLError(writer http.ResponseWriter, status int, message string) {
+	writer.WriteHeader(status)
+
+	//nolint:errcheck,errchkjson // test server
+	json.NewEncoder(writer).Encode(graphql.Response{
+		Errors: gqlerror.List{&gqlerror.Error{Message: message}},
+	})
+}
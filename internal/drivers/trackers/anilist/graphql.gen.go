@@ -29,14 +29,45 @@ type GetUserByNameUser struct {
 // GetId returns GetUserByNameUser.Id, and is useful for accessing the field via an interface.
 func (v *GetUserByNameUser) GetId() int { return v.Id }
 
+// GetViewerResponse is returned by GetViewer on success.
+type GetViewerResponse struct {
+	// Get the currently authenticated user
+	Viewer GetViewerViewerUser `json:"Viewer"`
+}
+
+// GetViewer returns GetViewerResponse.Viewer, and is useful for accessing the field via an interface.
+func (v *GetViewerResponse) GetViewer() GetViewerViewerUser { return v.Viewer }
+
+// GetViewerViewerUser includes the requested fields of the GraphQL type User.
+// The GraphQL type's documentation follows.
+//
+// A user
+type GetViewerViewerUser struct {
+	// The id of the user
+	Id int `json:"id"`
+	// The name of the user
+	Name string `json:"name"`
+}
+
+// GetId returns GetViewerViewerUser.Id, and is useful for accessing the field via an interface.
+func (v *GetViewerViewerUser) GetId() int { return v.Id }
+
+// GetName returns GetViewerViewerUser.Name, and is useful for accessing the field via an interface.
+func (v *GetViewerViewerUser) GetName() string { return v.Name }
+
 // GetWatchingPage includes the requested fields of the GraphQL type Page.
 // The GraphQL type's documentation follows.
 //
 // Page of data
 type GetWatchingPage struct {
+	// The pagination information
+	PageInfo  GetWatchingPagePageInfo    `json:"pageInfo"`
 	MediaList []GetWatchingPageMediaList `json:"mediaList"`
 }
 
+// GetPageInfo returns GetWatchingPage.PageInfo, and is useful for accessing the field via an interface.
+func (v *GetWatchingPage) GetPageInfo() GetWatchingPagePageInfo { return v.PageInfo }
+
 // GetMediaList returns GetWatchingPage.MediaList, and is useful for accessing the field via an interface.
 func (v *GetWatchingPage) GetMediaList() []GetWatchingPageMediaList { return v.MediaList }
 
@@ -85,6 +116,15 @@ type GetWatchingPageMediaListMediaTitle struct {
 // GetRomaji returns GetWatchingPageMediaListMediaTitle.Romaji, and is useful for accessing the field via an interface.
 func (v *GetWatchingPageMediaListMediaTitle) GetRomaji() string { return v.Romaji }
 
+// GetWatchingPagePageInfo includes the requested fields of the GraphQL type PageInfo.
+type GetWatchingPagePageInfo struct {
+	// The last page
+	LastPage int `json:"lastPage"`
+}
+
+// GetLastPage returns GetWatchingPagePageInfo.LastPage, and is useful for accessing the field via an interface.
+func (v *GetWatchingPagePageInfo) GetLastPage() int { return v.LastPage }
+
 // GetWatchingResponse is returned by GetWatching on success.
 type GetWatchingResponse struct {
 	Page GetWatchingPage `json:"Page"`
@@ -93,6 +133,33 @@ type GetWatchingResponse struct {
 // GetPage returns GetWatchingResponse.Page, and is useful for accessing the field via an interface.
 func (v *GetWatchingResponse) GetPage() GetWatchingPage { return v.Page }
 
+// Media list watching/reading status enum.
+type MediaListStatus string
+
+const (
+	// Currently watching/reading
+	MediaListStatusCurrent MediaListStatus = "CURRENT"
+	// Planning to watch/read
+	MediaListStatusPlanning MediaListStatus = "PLANNING"
+	// Finished watching/reading
+	MediaListStatusCompleted MediaListStatus = "COMPLETED"
+	// Stopped watching/reading before completing
+	MediaListStatusDropped MediaListStatus = "DROPPED"
+	// Paused watching/reading
+	MediaListStatusPaused MediaListStatus = "PAUSED"
+	// Re-watching/reading
+	MediaListStatusRepeating MediaListStatus = "REPEATING"
+)
+
+var AllMediaListStatus = []MediaListStatus{
+	MediaListStatusCurrent,
+	MediaListStatusPlanning,
+	MediaListStatusCompleted,
+	MediaListStatusDropped,
+	MediaListStatusPaused,
+	MediaListStatusRepeating,
+}
+
 // __GetUserByNameInput is used internally by genqlient
 type __GetUserByNameInput struct {
 	Name string `json:"name"`
@@ -103,11 +170,15 @@ func (v *__GetUserByNameInput) GetName() string { return v.Name }
 
 // __GetWatchingInput is used internally by genqlient
 type __GetWatchingInput struct {
-	UserId  int `json:"userId"`
-	Page    int `json:"page"`
-	PerPage int `json:"perPage"`
+	Statuses []MediaListStatus `json:"statuses"`
+	UserId   int               `json:"userId"`
+	Page     int               `json:"page"`
+	PerPage  int               `json:"perPage"`
 }
 
+// GetStatuses returns __GetWatchingInput.Statuses, and is useful for accessing the field via an interface.
+func (v *__GetWatchingInput) GetStatuses() []MediaListStatus { return v.Statuses }
+
 // GetUserId returns __GetWatchingInput.UserId, and is useful for accessing the field via an interface.
 func (v *__GetWatchingInput) GetUserId() int { return v.UserId }
 
@@ -151,11 +222,45 @@ func GetUserByName(
 	return data_, err_
 }
 
+// The query executed by GetViewer.
+const GetViewer_Operation = `
+query GetViewer {
+	Viewer {
+		id
+		name
+	}
+}
+`
+
+func GetViewer(
+	ctx_ context.Context,
+	client_ graphql.Client,
+) (data_ *GetViewerResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "GetViewer",
+		Query:  GetViewer_Operation,
+	}
+
+	data_ = &GetViewerResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by GetWatching.
 const GetWatching_Operation = `
-query GetWatching ($userId: Int!, $page: Int!, $perPage: Int!) {
+query GetWatching ($statuses: [MediaListStatus]!, $userId: Int!, $page: Int!, $perPage: Int!) {
 	Page(page: $page, perPage: $perPage) {
-		mediaList(userId: $userId, type: ANIME, status_in: [CURRENT,PLANNING]) {
+		pageInfo {
+			lastPage
+		}
+		mediaList(userId: $userId, type: ANIME, status_in: $statuses) {
 			media {
 				id
 				idMal
@@ -171,6 +276,7 @@ query GetWatching ($userId: Int!, $page: Int!, $perPage: Int!) {
 func GetWatching(
 	ctx_ context.Context,
 	client_ graphql.Client,
+	statuses []MediaListStatus,
 	userId int,
 	page int,
 	perPage int,
@@ -179,9 +285,10 @@ func GetWatching(
 		OpName: "GetWatching",
 		Query:  GetWatching_Operation,
 		Variables: &__GetWatchingInput{
-			UserId:  userId,
-			Page:    page,
-			PerPage: perPage,
+			Statuses: statuses,
+			UserId:   userId,
+			Page:     page,
+			PerPage:  perPage,
 		},
 	}
 
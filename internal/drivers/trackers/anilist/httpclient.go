@@ -3,19 +3,16 @@ package anilist
 import (
 	"context"
 	"fmt"
-	"maps"
-	"math"
 	"net/http"
-	"net/http/httptest"
 	"strconv"
 	"time"
 
 	"github.com/Khan/genqlient/graphql"
 	telemetry "github.com/wwmoraes/gotell"
 	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
-	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/time/rate"
 
+	"github.com/wwmoraes/anilistarr/internal/adapters/ratedclient"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
@@ -32,15 +29,24 @@ var _ graphql.Doer = (*RatedClient)(nil)
 
 // RatedClient is a rate-limited HTTP client. This allows consuming upstream
 // resources with usage limits in a friendly way.
+//
+// Setting Retry enables its waiting mode, which waits for the limits and
+// retries failed requests instead of failing right away.
 type RatedClient struct {
 	usecases.Doer
 
 	Limiter *rate.Limiter
+	Retry   *Retry
 }
 
 // Do executes a HTTP request right away if its within the limits. Otherwise it
-// returns a 429 + Retry-After header with the seconds to wait for
+// returns a 429 + Retry-After header with the seconds to wait for, unless in
+// waiting mode.
 func (client *RatedClient) Do(req *http.Request) (*http.Response, error) {
+	if client.Retry != nil {
+		return client.doRetrying(req)
+	}
+
 	span := telemetry.SpanFromContext(req.Context())
 
 	reservation := client.Limiter.Reserve()
@@ -51,15 +57,17 @@ func (client *RatedClient) Do(req *http.Request) (*http.Response, error) {
 	if reservation.Delay() > 0 {
 		reservation.Cancel()
 
-		res := newResponseFor(req, http.StatusTooManyRequests, nil, http.Header{
-			"Retry-After": []string{
-				strconv.FormatFloat(math.Ceil(reservation.Delay().Seconds()), 'f', 0, 64),
-			},
-		})
-
-		return res, nil
+		return ratedclient.NewRetryAfterResponse(req, reservation.Delay()), nil
 	}
 
+	return client.send(req)
+}
+
+// send executes a HTTP request, updating the limiter from the response headers
+// if upstream limits it.
+func (client *RatedClient) send(req *http.Request) (*http.Response, error) {
+	span := telemetry.SpanFromContext(req.Context())
+
 	resp, err := client.Doer.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", usecases.ErrStatusUnknown, err)
@@ -81,29 +89,6 @@ func (client *RatedClient) Do(req *http.Request) (*http.Response, error) {
 	return resp, span.Assert(nil)
 }
 
-func newResponseFor(
-	req *http.Request,
-	status int,
-	data []byte,
-	headers http.Header,
-) *http.Response {
-	span := telemetry.SpanFromContext(req.Context())
-
-	writer := httptest.NewRecorder()
-	maps.Copy(writer.Header(), headers)
-	writer.WriteHeader(status)
-
-	_, err := writer.Write(data)
-	if err != nil {
-		span.RecordError(err, trace.WithStackTrace(true))
-	}
-
-	res := writer.Result()
-	res.Request = req
-
-	return res
-}
-
 func tryUpdateLimiterBurstFromHeaders(
 	ctx context.Context,
 	limiter *rate.Limiter,
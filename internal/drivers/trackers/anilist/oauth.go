@@ -0,0 +1,161 @@
+package anilist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/goccy/go-json"
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	// DefaultAuthorizeURL is the Anilist page users consent at.
+	DefaultAuthorizeURL = "https://anilist.co/api/v2/oauth/authorize"
+	// DefaultTokenURL is the Anilist endpoint that exchanges codes for tokens.
+	DefaultTokenURL = "https://anilist.co/api/v2/oauth/token"
+)
+
+var _ usecases.Authorizer = (*Authorizer)(nil)
+
+// Authorizer grants access to the data of Anilist users through the OAuth2
+// authorization code flow, which needs an Anilist API client.
+//
+// It resolves whom tokens belong to through the viewer of Tracker. ClientID,
+// ClientSecret and RedirectURL must match those of the API client. The URLs
+// default to [DefaultAuthorizeURL] and [DefaultTokenURL].
+type Authorizer struct {
+	Client       usecases.Doer
+	Tracker      *Tracker
+	AuthorizeURL string
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// tokenRequest contains the parameters of an authorization code exchange.
+//
+//nolint:tagliatelle // JSON tags must match the upstream naming convention
+type tokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RedirectURI  string `json:"redirect_uri"`
+	Code         string `json:"code"`
+}
+
+// tokenResponse contains the token an authorization code exchanges for.
+//
+//nolint:tagliatelle // JSON tags must match the upstream naming convention
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// AuthCodeURL returns the URL users consent at, which redirects back to
+// RedirectURL with the state and a code.
+func (authorizer *Authorizer) AuthCodeURL(state string) string {
+	authorizeURL := authorizer.AuthorizeURL
+	if authorizeURL == "" {
+		authorizeURL = DefaultAuthorizeURL
+	}
+
+	query := url.Values{
+		"client_id":     []string{authorizer.ClientID},
+		"redirect_uri":  []string{authorizer.RedirectURL},
+		"response_type": []string{"code"},
+		"state":         []string{state},
+	}
+
+	return authorizeURL + "?" + query.Encode()
+}
+
+// Authorize exchanges an authorization code for the token of the user that
+// consented.
+func (authorizer *Authorizer) Authorize(ctx context.Context, code string) (*entities.Token, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if code == "" {
+		return nil, span.Assert(fmt.Errorf("%w: empty authorization code", usecases.ErrStatusInvalidArgument))
+	}
+
+	res, err := authorizer.exchange(ctx, code)
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	token, err := authorizer.Tracker.Viewer(ctx, res.AccessToken)
+	if err != nil {
+		return nil, span.Assert(err)
+	}
+
+	if res.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+
+	return token, span.Assert(nil)
+}
+
+// exchange requests the token an authorization code grants.
+func (authorizer *Authorizer) exchange(ctx context.Context, code string) (*tokenResponse, error) {
+	ctx, span := telemetry.StartNamed(ctx, "anilist.OAuthToken", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	tokenURL := authorizer.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+
+	body, err := json.Marshal(tokenRequest{
+		GrantType:    "authorization_code",
+		ClientID:     authorizer.ClientID,
+		ClientSecret: authorizer.ClientSecret,
+		RedirectURI:  authorizer.RedirectURL,
+		Code:         code,
+	})
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInternal, err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInvalidArgument, err))
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := authorizer.Client.Do(req)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusUnavailable, err))
+	}
+	defer res.Body.Close()
+
+	err = usecases.ErrorFromHTTPStatus(res.StatusCode)
+	if err != nil {
+		return nil, span.Assert(fmt.Errorf("%w: %s", err, "failed to exchange authorization code"))
+	}
+
+	var token tokenResponse
+
+	err = json.NewDecoder(res.Body).Decode(&token)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInternal, err))
+	}
+
+	if token.AccessToken == "" {
+		return nil, span.Assert(fmt.Errorf("%w: no access token granted", usecases.ErrStatusFailedPrecondition))
+	}
+
+	return &token, span.Assert(nil)
+}
@@ -0,0 +1,138 @@
+package anilist_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/anilist"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// tokenServerRequest contains the parameters of a code exchange.
+//
+//nolint:tagliatelle // JSON tags must match the upstream naming convention
+type tokenServerRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RedirectURI  string `json:"redirect_uri"`
+	Code         string `json:"code"`
+}
+
+// tokenServerResponse contains the token a code exchanges for.
+//
+//nolint:tagliatelle // JSON tags must match the upstream naming convention
+type tokenServerResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func TestAuthorizer_AuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	authorizer := anilist.Authorizer{
+		ClientID:    "42",
+		RedirectURL: "https://example.com/auth/anilist/callback",
+	}
+
+	got, err := url.Parse(authorizer.AuthCodeURL("foo"))
+	require.NoError(t, err)
+
+	assert.Equal(t, anilist.DefaultAuthorizeURL, got.Scheme+"://"+got.Host+got.Path)
+	assert.Equal(t, url.Values{
+		"client_id":     []string{"42"},
+		"redirect_uri":  []string{"https://example.com/auth/anilist/callback"},
+		"response_type": []string{"code"},
+		"state":         []string{"foo"},
+	}, got.Query())
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	t.Parallel()
+
+	viewer := newViewerServer(t)
+	tokenServer := newTokenServer(t, viewerAccessToken)
+
+	authorizer := anilist.Authorizer{
+		Client:       tokenServer.Client(),
+		Tracker:      anilist.New(viewer.URL, anilist.WithClient(viewer.Client())),
+		TokenURL:     tokenServer.URL,
+		ClientID:     "42",
+		ClientSecret: "hunter2",
+		RedirectURL:  "https://example.com/auth/anilist/callback",
+	}
+
+	got, err := authorizer.Authorize(t.Context(), "good")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1", got.UserID)
+	assert.Equal(t, "Foo", got.UserName)
+	assert.Equal(t, viewerAccessToken, got.AccessToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), got.ExpiresAt, time.Minute)
+
+	_, err = authorizer.Authorize(t.Context(), "bad")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	_, err = authorizer.Authorize(t.Context(), "")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+}
+
+func TestAuthorizer_Authorize_rejected_token(t *testing.T) {
+	t.Parallel()
+
+	viewer := newViewerServer(t)
+	tokenServer := newTokenServer(t, "revoked")
+
+	authorizer := anilist.Authorizer{
+		Client:       tokenServer.Client(),
+		Tracker:      anilist.New(viewer.URL, anilist.WithClient(viewer.Client())),
+		TokenURL:     tokenServer.URL,
+		ClientID:     "42",
+		ClientSecret: "hunter2",
+		RedirectURL:  "https://example.com/auth/anilist/callback",
+	}
+
+	_, err := authorizer.Authorize(t.Context(), "good")
+	require.ErrorIs(t, err, usecases.ErrStatusUnauthenticated)
+}
+
+// newTokenServer returns a fake Anilist token endpoint that grants the access
+// token for the code "good" to the client 42.
+func newTokenServer(tb testing.TB, accessToken string) *httptest.Server {
+	tb.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		var request tokenServerRequest
+
+		err := json.NewDecoder(req.Body).Decode(&request)
+		if err != nil || request != (tokenServerRequest{
+			GrantType:    "authorization_code",
+			ClientID:     "42",
+			ClientSecret: "hunter2",
+			RedirectURI:  "https://example.com/auth/anilist/callback",
+			Code:         "good",
+		}) {
+			writer.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		//nolint:errcheck,errchkjson // test server
+		json.NewEncoder(writer).Encode(tokenServerResponse{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			ExpiresIn:   int64(time.Hour / time.Second),
+		})
+	}))
+	tb.Cleanup(server.Close)
+
+	return server
+}
@@ -0,0 +1,259 @@
+package anilist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/ratedclient"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	// DefaultRetryBaseDelay is the backoff of the first retry of a [Retry]
+	// without a BaseDelay.
+	DefaultRetryBaseDelay = time.Second
+	// DefaultRetryMaxDelay caps the backoff of a [Retry] without a MaxDelay.
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// Clock tells the current time and waits for some to pass.
+type Clock interface {
+	Now() time.Time
+	After(delay time.Duration) <-chan time.Time
+}
+
+// Retry contains the settings of the waiting mode of a [RatedClient].
+//
+// Requests wait on the rate limiter instead of failing right away, and upstream
+// 429 and 5xx responses get retried. Those wait for as long as their
+// Retry-After header tells or, lacking it, a jittered exponential backoff that
+// starts at BaseDelay and doubles on each retry up to MaxDelay.
+//
+// Budget caps the total time a request spends waiting, as does its context
+// deadline. Requests that would wait past either get the last response as-is,
+// which is a 429 with Retry-After if the limiter has no tokens left.
+//
+// Clock defaults to the system one.
+type Retry struct {
+	Clock     Clock
+	Budget    time.Duration
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(delay time.Duration) <-chan time.Time {
+	return time.After(delay)
+}
+
+// doRetrying executes the request as Do does, waiting on the limiter and
+// retrying failed responses as long as the retry budget allows.
+func (client *RatedClient) doRetrying(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	clock := client.Retry.clock()
+	deadline := client.Retry.deadline(ctx, clock.Now())
+
+	for attempt := 0; ; attempt++ {
+		resp, err := client.reserve(req, clock, deadline)
+		if err != nil || !retryable(resp.StatusCode) || !rewindable(req) {
+			return resp, err
+		}
+
+		delay := client.Retry.backoff(attempt, resp.Header, clock.Now())
+		if clock.Now().Add(delay).After(deadline) {
+			return resp, nil
+		}
+
+		discard(ctx, resp)
+
+		telemetry.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.Stringer("delay", delay),
+			attribute.Int("status", resp.StatusCode),
+		))
+
+		err = wait(ctx, clock, delay)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reserve waits for a limiter token and then sends the request. It returns a
+// 429 with Retry-After instead if the token arrives past the deadline.
+func (client *RatedClient) reserve(
+	req *http.Request,
+	clock Clock,
+	deadline time.Time,
+) (*http.Response, error) {
+	span := telemetry.SpanFromContext(req.Context())
+
+	now := clock.Now()
+
+	reservation := client.Limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return nil, span.Assert(usecases.ErrStatusInternal)
+	}
+
+	delay := reservation.DelayFrom(now)
+	if now.Add(delay).After(deadline) {
+		reservation.CancelAt(now)
+
+		return ratedclient.NewRetryAfterResponse(req, delay), nil
+	}
+
+	err := wait(req.Context(), clock, delay)
+	if err != nil {
+		reservation.CancelAt(clock.Now())
+
+		return nil, span.Assert(err)
+	}
+
+	return client.send(req)
+}
+
+func (retry *Retry) clock() Clock {
+	if retry.Clock == nil {
+		return systemClock{}
+	}
+
+	return retry.Clock
+}
+
+// deadline returns when the budget of a request that starts now ends, which is
+// never past the context deadline.
+func (retry *Retry) deadline(ctx context.Context, now time.Time) time.Time {
+	deadline := now.Add(retry.Budget)
+
+	ctxDeadline, ok := ctx.Deadline()
+	if ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+
+	return deadline
+}
+
+// backoff returns how long to wait before the retry after attempt, which is
+// zero-based. It prefers the Retry-After header over a jittered exponential
+// backoff.
+func (retry *Retry) backoff(attempt int, header http.Header, now time.Time) time.Duration {
+	delay, ok := retryAfter(header, now)
+	if ok {
+		return delay
+	}
+
+	base := retry.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+
+	limit := retry.MaxDelay
+	if limit <= 0 {
+		limit = DefaultRetryMaxDelay
+	}
+
+	delay = min(base, limit)
+	for range attempt {
+		if delay > limit/2 {
+			delay = limit
+
+			break
+		}
+
+		delay *= 2
+	}
+
+	// equal jitter, which keeps half of the delay to not retry too early
+	//nolint:gosec // jitter needs no cryptographic randomness
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds
+// or a HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 0)
+	if err == nil && seconds >= 0 && seconds < math.MaxInt64/int64(time.Second) {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// rewindable tells if the request body can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of the request with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to rewind request body: %w", usecases.ErrStatusInternal, err)
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+
+	return clone, nil
+}
+
+// discard drains and closes the response body so its connection gets reused.
+func discard(ctx context.Context, resp *http.Response) {
+	_, err := io.Copy(io.Discard, resp.Body)
+	err = errors.Join(err, resp.Body.Close())
+	if err != nil {
+		telemetry.SpanFromContext(ctx).RecordError(err)
+	}
+}
+
+func wait(ctx context.Context, clock Clock, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return errors.Join(usecases.ErrStatusCancelled, ctx.Err())
+	case <-clock.After(delay):
+		return nil
+	}
+}
@@ -0,0 +1,295 @@
+package anilist_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/anilist"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+// fakeClock advances its time by the delay waited on right away.
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+	mutex sync.Mutex
+	stuck bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Now()}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+func (clock *fakeClock) After(delay time.Duration) <-chan time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.waits = append(clock.waits, delay)
+
+	if clock.stuck {
+		return nil
+	}
+
+	clock.now = clock.now.Add(delay)
+
+	ch := make(chan time.Time, 1)
+	ch <- clock.now
+
+	return ch
+}
+
+func (clock *fakeClock) Waits() []time.Duration {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.waits
+}
+
+// newStatusServer returns a server that replies with each status in order, and
+// then with the last one. It counts the requests and checks their bodies.
+func newStatusServer(t *testing.T, body string, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		data, err := io.ReadAll(req.Body)
+		if err != nil || string(data) != body {
+			writer.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		call := int(calls.Add(1)) - 1
+
+		writer.WriteHeader(statuses[min(call, len(statuses)-1)])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func newRetryRequest(ctx context.Context, t *testing.T, url, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+
+	return req
+}
+
+func TestRatedClient_Retry_waits_for_limiter(t *testing.T) {
+	t.Parallel()
+
+	server, calls := newStatusServer(t, "", http.StatusOK)
+	clock := newFakeClock()
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: rate.NewLimiter(rate.Every(time.Second), 1),
+		Retry:   &anilist.Retry{Clock: clock, Budget: time.Minute},
+	}
+
+	for range 2 {
+		res, err := client.Do(newRetryRequest(t.Context(), t, server.URL, ""))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	assert.EqualValues(t, 2, calls.Load())
+	assert.Equal(t, []time.Duration{time.Second}, clock.Waits())
+}
+
+func TestRatedClient_Retry_limiter_past_budget(t *testing.T) {
+	t.Parallel()
+
+	server, calls := newStatusServer(t, "", http.StatusOK)
+	clock := newFakeClock()
+
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	require.True(t, limiter.AllowN(clock.Now(), 1))
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: limiter,
+		Retry:   &anilist.Retry{Clock: clock, Budget: time.Minute},
+	}
+
+	res, err := client.Do(newRetryRequest(t.Context(), t, server.URL, ""))
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "3600", res.Header.Get("Retry-After"))
+	assert.Zero(t, calls.Load())
+	assert.Empty(t, clock.Waits())
+}
+
+func TestRatedClient_Retry_backoff(t *testing.T) {
+	t.Parallel()
+
+	body := `{"query":"foo"}`
+	server, calls := newStatusServer(t, body,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusOK,
+	)
+	clock := newFakeClock()
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: rate.NewLimiter(rate.Inf, 1),
+		Retry: &anilist.Retry{
+			Clock:     clock,
+			Budget:    time.Minute,
+			BaseDelay: time.Second,
+			MaxDelay:  time.Minute,
+		},
+	}
+
+	res, err := client.Do(newRetryRequest(t.Context(), t, server.URL, body))
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.EqualValues(t, 3, calls.Load())
+
+	waits := clock.Waits()
+	require.Len(t, waits, 2)
+	assert.GreaterOrEqual(t, waits[0], time.Second/2)
+	assert.LessOrEqual(t, waits[0], time.Second)
+	assert.GreaterOrEqual(t, waits[1], time.Second)
+	assert.LessOrEqual(t, waits[1], 2*time.Second)
+}
+
+func TestRatedClient_Retry_honors_retry_after(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			writer.Header().Set("Retry-After", "5")
+			writer.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	clock := newFakeClock()
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: rate.NewLimiter(rate.Inf, 1),
+		Retry:   &anilist.Retry{Clock: clock, Budget: time.Minute},
+	}
+
+	res, err := client.Do(newRetryRequest(t.Context(), t, server.URL, ""))
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.EqualValues(t, 2, calls.Load())
+	assert.Equal(t, []time.Duration{5 * time.Second}, clock.Waits())
+}
+
+func TestRatedClient_Retry_budget_spent(t *testing.T) {
+	t.Parallel()
+
+	server, calls := newStatusServer(t, "", http.StatusInternalServerError)
+	clock := newFakeClock()
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: rate.NewLimiter(rate.Inf, 1),
+		Retry: &anilist.Retry{
+			Clock:     clock,
+			Budget:    10 * time.Second,
+			BaseDelay: time.Second,
+			MaxDelay:  2 * time.Second,
+		},
+	}
+
+	start := clock.Now()
+
+	res, err := client.Do(newRetryRequest(t.Context(), t, server.URL, ""))
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.Greater(t, calls.Load(), int32(5))
+	assert.LessOrEqual(t, clock.Now().Sub(start), 10*time.Second)
+	assert.EqualValues(t, len(clock.Waits())+1, calls.Load())
+}
+
+func TestRatedClient_Retry_client_error(t *testing.T) {
+	t.Parallel()
+
+	server, calls := newStatusServer(t, "", http.StatusNotFound)
+	clock := newFakeClock()
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: rate.NewLimiter(rate.Inf, 1),
+		Retry:   &anilist.Retry{Clock: clock, Budget: time.Minute},
+	}
+
+	res, err := client.Do(newRetryRequest(t.Context(), t, server.URL, ""))
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Empty(t, clock.Waits())
+}
+
+func TestRatedClient_Retry_context_done(t *testing.T) {
+	t.Parallel()
+
+	server, calls := newStatusServer(t, "", http.StatusOK)
+	clock := newFakeClock()
+	clock.stuck = true
+
+	limiter := rate.NewLimiter(rate.Every(time.Second), 1)
+	require.True(t, limiter.AllowN(clock.Now(), 1))
+
+	client := anilist.RatedClient{
+		Doer:    server.Client(),
+		Limiter: limiter,
+		Retry:   &anilist.Retry{Clock: clock, Budget: time.Minute},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	res, err := client.Do(newRetryRequest(ctx, t, server.URL, ""))
+	require.ErrorIs(t, err, usecases.ErrStatusCancelled)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Nil(t, res)
+	assert.Zero(t, calls.Load())
+	assert.True(t, limiter.AllowN(clock.Now().Add(time.Second), 1))
+}
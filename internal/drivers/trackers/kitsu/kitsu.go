@@ -0,0 +1,326 @@
+// Package kitsu provides an [usecases.Tracker] that communicates with Kitsu's
+// JSON:API.
+package kitsu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/ratedclient"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+	"github.com/wwmoraes/anilistarr/pkg/with"
+)
+
+const (
+	// DefaultEndpoint is the base URL of the public Kitsu API.
+	DefaultEndpoint = "https://kitsu.app/api/edge"
+
+	// MediaType is the JSON:API media type Kitsu requires on requests.
+	MediaType = "application/vnd.api+json"
+
+	// resourceAnime is both the type and relationship name of anime resources.
+	resourceAnime = "anime"
+
+	// interval and requests are conservative limits; Kitsu does not document
+	// its rate limits, but it throttles clients that burst requests.
+	interval time.Duration = time.Minute
+	requests int           = 60
+
+	// maxPageSize is the upstream limit for library entries page sizes.
+	maxPageSize     int = 500
+	defaultPageSize int = 100
+)
+
+var (
+	// DefaultStatuses contains the library entry statuses fetched by default.
+	// Those mirror the CURRENT and PLANNING statuses from Anilist.
+	DefaultStatuses = []string{"current", "planned"}
+
+	_ io.Closer        = (*Tracker)(nil)
+	_ usecases.Tracker = (*Tracker)(nil)
+)
+
+// Tracker abstracts a Kitsu JSON:API client and provides the common requests
+// needed by MediaLister
+type Tracker struct {
+	Client   usecases.Doer
+	Endpoint string
+	Statuses []string
+	PageSize int
+}
+
+// Options contains optional settings for tracker instances.
+type Options struct {
+	Client   usecases.Doer
+	Statuses []string
+	PageSize int
+}
+
+// resource is a JSON:API resource identifier.
+type resource struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// document is a JSON:API top-level document. It decodes only the members the
+// tracker needs.
+type document struct {
+	Links links          `json:"links"`
+	Data  []libraryEntry `json:"data"`
+}
+
+// links is a JSON:API links object with pagination links.
+type links struct {
+	Next string `json:"next,omitempty"`
+}
+
+// libraryEntry is a JSON:API resource of an user library entry.
+type libraryEntry struct {
+	Relationships libraryEntryRelationships `json:"relationships"`
+	resource
+}
+
+// libraryEntryRelationships contains the library entry relationships.
+type libraryEntryRelationships struct {
+	Anime relationship `json:"anime"`
+}
+
+// relationship is a JSON:API to-one relationship object.
+type relationship struct {
+	Data *resource `json:"data"`
+}
+
+// NewOptions generates an [Options] value ready to use. It starts with defaults
+// and then applies all [Option] in order.
+func NewOptions(opts ...with.Option[Options]) Options {
+	return with.Apply(Options{
+		PageSize: defaultPageSize,
+		Client:   http.DefaultClient,
+		Statuses: DefaultStatuses,
+	}, opts...)
+}
+
+// WithClient sets a custom HTTP client.
+func WithClient(client usecases.Doer) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Client = client
+	})
+}
+
+// WithPageSize sets a custom page size for paginated requests. Sizes are
+// capped to the upstream maximum.
+func WithPageSize(size int) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.PageSize = min(size, maxPageSize)
+	})
+}
+
+// WithStatuses sets which library entry statuses to include in media lists.
+func WithStatuses(statuses ...string) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Statuses = statuses
+	})
+}
+
+// New creates a Kitsu client for the given API endpoint that uses a
+// [ratedclient.RatedClient] to keep requests within friendly limits. It uses
+// the public Kitsu API if endpoint is empty.
+func New(endpoint string, opts ...with.Option[Options]) *Tracker {
+	options := NewOptions(opts...)
+
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	return &Tracker{
+		Client: &ratedclient.RatedClient{
+			Doer:    options.Client,
+			Limiter: rate.NewLimiter(rate.Limit(requests)*rate.Every(interval), requests),
+		},
+		Endpoint: endpoint,
+		Statuses: options.Statuses,
+		PageSize: options.PageSize,
+	}
+}
+
+// GetUserID retrieves an user ID using their profile slug. It falls back to
+// searching by name for users that have no slug set.
+func (tracker *Tracker) GetUserID(ctx context.Context, name string) (string, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	for _, filter := range [...]string{"filter[slug]", "filter[name]"} {
+		query := url.Values{}
+		query.Set(filter, name)
+		query.Set("fields[users]", "id")
+
+		doc, err := tracker.getDocument(ctx, tracker.Endpoint+"/users?"+query.Encode())
+		if err != nil {
+			return "", span.Assert(err)
+		}
+
+		if len(doc.Data) > 0 {
+			return doc.Data[0].ID, span.Assert(nil)
+		}
+	}
+
+	return "", span.Assert(usecases.ErrStatusNotFound)
+}
+
+// GetMediaListIDs retrieves a list of anime IDs from a user library. It includes
+// only entries with one of the given statuses, or with one of the tracker
+// statuses if none is given.
+func (tracker *Tracker) GetMediaListIDs(
+	ctx context.Context,
+	userID string,
+	statuses ...string,
+) ([]entities.SourceID, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	_, err := strconv.ParseUint(userID, 10, 0)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInvalidArgument, err))
+	}
+
+	kitsuIDs := make([]entities.SourceID, 0, tracker.PageSize)
+	span.SetAttributes(attribute.Int("page.size", tracker.PageSize))
+
+	if len(statuses) == 0 {
+		statuses = tracker.Statuses
+	}
+
+	for mediaID, err := range tracker.getMediaListIDs(ctx, userID, statuses) {
+		if err != nil {
+			return nil, span.Assert(err)
+		}
+
+		kitsuIDs = append(kitsuIDs, mediaID)
+	}
+
+	return kitsuIDs, span.Assert(nil)
+}
+
+// Close terminates the client to the upstream API.
+func (tracker *Tracker) Close() error {
+	tracker.Client = nil
+
+	return nil
+}
+
+// getMediaListIDs iterates over all library entries of an user, following the
+// JSON:API next links until there are no pages left.
+func (tracker *Tracker) getMediaListIDs(
+	ctx context.Context,
+	userID string,
+	statuses []string,
+) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		next := tracker.libraryEntriesURL(userID, statuses)
+
+		for next != "" {
+			doc, err := tracker.getDocument(ctx, next)
+			if err != nil {
+				yield("", err)
+
+				return
+			}
+
+			if !yieldEntries(doc.Data, yield) {
+				return
+			}
+
+			next = doc.Links.Next
+		}
+	}
+}
+
+func (tracker *Tracker) libraryEntriesURL(userID string, statuses []string) string {
+	query := url.Values{}
+	query.Set("filter[userId]", userID)
+	query.Set("filter[kind]", resourceAnime)
+	query.Set("filter[status]", strings.Join(statuses, ","))
+	query.Set("include", resourceAnime)
+	query.Set("fields[anime]", "id")
+	query.Set("fields[libraryEntries]", resourceAnime)
+	query.Set("page[limit]", strconv.Itoa(tracker.PageSize))
+
+	return tracker.Endpoint + "/library-entries?" + query.Encode()
+}
+
+// yieldEntries yields the anime IDs of the library entries. It returns false if
+// the consumer stops the iteration.
+func yieldEntries(entries []libraryEntry, yield func(string, error) bool) bool {
+	for _, entry := range entries {
+		if entry.Relationships.Anime.Data == nil {
+			continue
+		}
+
+		if !yield(entry.Relationships.Anime.Data.ID, nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tracker *Tracker) getDocument(ctx context.Context, uri string) (*document, error) {
+	ctx, span := telemetry.StartNamed(
+		ctx,
+		"kitsu.GetDocument",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("url", uri)),
+	)
+	defer span.End()
+
+	if tracker.Client == nil {
+		return nil, span.Assert(usecases.ErrStatusFailedPrecondition)
+	}
+
+	log := telemetry.Logr(ctx)
+
+	log.Info("requesting document", "url", uri)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInvalidArgument, err))
+	}
+
+	req.Header.Set("Accept", MediaType)
+	req.Header.Set("Content-Type", MediaType)
+
+	res, err := tracker.Client.Do(req)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusUnavailable, err))
+	}
+	defer res.Body.Close()
+
+	err = usecases.ErrorFromHTTPStatus(res.StatusCode)
+	if err != nil {
+		return nil, span.Assert(fmt.Errorf("%w: %s", err, "failed to get document"))
+	}
+
+	var doc document
+
+	err = json.NewDecoder(res.Body).Decode(&doc)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInternal, err))
+	}
+
+	return &doc, span.Assert(nil)
+}
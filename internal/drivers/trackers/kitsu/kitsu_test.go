@@ -0,0 +1,291 @@
+package kitsu_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/kitsu"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+type kitsuUser struct {
+	ID   string
+	Slug string
+	Name string
+}
+
+type kitsuEntry struct {
+	Status  string
+	AnimeID string
+}
+
+// newKitsuServer creates a stand-in for the Kitsu JSON:API that serves users
+// and their library entries, paginated with JSON:API next links.
+func newKitsuServer(
+	tb testing.TB,
+	users []kitsuUser,
+	libraries map[string][]kitsuEntry,
+) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", usersHandler(tb, users))
+	mux.HandleFunc("GET /library-entries", libraryEntriesHandler(tb, libraries))
+
+	server := httptest.NewServer(mux)
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+func usersHandler(tb testing.TB, users []kitsuUser) http.HandlerFunc {
+	tb.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		data := []map[string]any{}
+
+		for _, user := range users {
+			if user.Slug == query.Get("filter[slug]") || user.Name == query.Get("filter[name]") {
+				data = append(data, map[string]any{"id": user.ID, "type": "users"})
+			}
+		}
+
+		writeDocument(tb, w, map[string]any{"data": data, "links": map[string]any{}})
+	}
+}
+
+func libraryEntriesHandler(
+	tb testing.TB,
+	libraries map[string][]kitsuEntry,
+) http.HandlerFunc {
+	tb.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != kitsu.MediaType {
+			http.Error(w, "not acceptable", http.StatusNotAcceptable)
+
+			return
+		}
+
+		query := r.URL.Query()
+		statuses := strings.Split(query.Get("filter[status]"), ",")
+
+		entries := slices.DeleteFunc(
+			slices.Clone(libraries[query.Get("filter[userId]")]),
+			func(entry kitsuEntry) bool {
+				return !slices.Contains(statuses, entry.Status)
+			},
+		)
+
+		page, next := paginate(tb, query, entries)
+
+		data := make([]map[string]any, 0, len(page))
+		for _, entry := range page {
+			data = append(data, map[string]any{
+				"id":   "entry-" + entry.AnimeID,
+				"type": "libraryEntries",
+				"relationships": map[string]any{
+					"anime": map[string]any{
+						"data": map[string]any{"id": entry.AnimeID, "type": "anime"},
+					},
+				},
+			})
+		}
+
+		links := map[string]any{}
+		if next > 0 {
+			query.Set("page[offset]", strconv.Itoa(next))
+			links["next"] = fmt.Sprintf("http://%s%s?%s", r.Host, r.URL.Path, query.Encode())
+		}
+
+		writeDocument(tb, w, map[string]any{"data": data, "links": links})
+	}
+}
+
+func writeDocument(tb testing.TB, w http.ResponseWriter, doc map[string]any) {
+	tb.Helper()
+
+	w.Header().Set("Content-Type", kitsu.MediaType)
+
+	err := json.NewEncoder(w).Encode(doc)
+	if err != nil {
+		tb.Error(err)
+	}
+}
+
+// paginate returns the page of entries requested by the JSON:API page query
+// parameters, and the offset of the next page if there's one left.
+func paginate[T any](tb testing.TB, query url.Values, entries []T) ([]T, int) {
+	tb.Helper()
+
+	limit, err := strconv.Atoi(query.Get("page[limit]"))
+	if err != nil {
+		tb.Error(err)
+	}
+
+	offset := 0
+
+	if query.Has("page[offset]") {
+		offset, err = strconv.Atoi(query.Get("page[offset]"))
+		if err != nil {
+			tb.Error(err)
+		}
+	}
+
+	end := min(offset+limit, len(entries))
+	if end == len(entries) {
+		return entries[offset:end], 0
+	}
+
+	return entries[offset:end], end
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	server := newKitsuServer(t, []kitsuUser{
+		{ID: "42", Slug: "foo", Name: "Foo"},
+		{ID: "43", Name: "bar"},
+	}, map[string][]kitsuEntry{
+		"42": {
+			{AnimeID: "1", Status: "current"},
+			{AnimeID: "2", Status: "completed"},
+			{AnimeID: "3", Status: "planned"},
+			{AnimeID: "5", Status: "dropped"},
+			{AnimeID: "8", Status: "current"},
+			{AnimeID: "13", Status: "planned"},
+		},
+	})
+
+	tracker := kitsu.New(
+		server.URL,
+		kitsu.WithClient(server.Client()),
+		kitsu.WithPageSize(2),
+	)
+	defer tracker.Close()
+
+	gotUserID, err := tracker.GetUserID(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "42", gotUserID)
+
+	gotMediaList, err := tracker.GetMediaListIDs(t.Context(), gotUserID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "3", "8", "13"}, gotMediaList)
+
+	gotUserID, err = tracker.GetUserID(t.Context(), "bar")
+	require.NoError(t, err)
+
+	assert.Equal(t, "43", gotUserID)
+}
+
+func TestTracker_WithStatuses(t *testing.T) {
+	t.Parallel()
+
+	server := newKitsuServer(t, nil, map[string][]kitsuEntry{
+		"42": {
+			{AnimeID: "1", Status: "current"},
+			{AnimeID: "2", Status: "completed"},
+			{AnimeID: "3", Status: "on_hold"},
+		},
+	})
+
+	tracker := kitsu.New(
+		server.URL,
+		kitsu.WithClient(server.Client()),
+		kitsu.WithStatuses("completed", "on_hold"),
+	)
+	defer tracker.Close()
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "42")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2", "3"}, got)
+}
+
+func TestTracker_GetMediaListIDs_statuses(t *testing.T) {
+	t.Parallel()
+
+	server := newKitsuServer(t, nil, map[string][]kitsuEntry{
+		"42": {
+			{AnimeID: "1", Status: "current"},
+			{AnimeID: "2", Status: "completed"},
+			{AnimeID: "3", Status: "dropped"},
+		},
+	})
+
+	tracker := kitsu.New(server.URL, kitsu.WithClient(server.Client()))
+	defer tracker.Close()
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "42", "completed", "dropped")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2", "3"}, got)
+}
+
+func TestTracker_GetUserID_not_found(t *testing.T) {
+	t.Parallel()
+
+	server := newKitsuServer(t, nil, nil)
+
+	tracker := kitsu.New(server.URL, kitsu.WithClient(server.Client()))
+	defer tracker.Close()
+
+	got, err := tracker.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	assert.Empty(t, got)
+}
+
+func TestTracker_GetMediaListIDs_invalid(t *testing.T) {
+	t.Parallel()
+
+	tracker := kitsu.New("")
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusInvalidArgument)
+
+	assert.Nil(t, got)
+}
+
+func TestTracker_GetMediaListIDs_unavailable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tracker := kitsu.New(server.URL, kitsu.WithClient(server.Client()))
+	defer tracker.Close()
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "42")
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+
+	assert.Nil(t, got)
+}
+
+func TestTracker_Close(t *testing.T) {
+	t.Parallel()
+
+	tracker := kitsu.New("")
+
+	require.NoError(t, tracker.Close())
+
+	got, err := tracker.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusFailedPrecondition)
+
+	assert.Empty(t, got)
+}
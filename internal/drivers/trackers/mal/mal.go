@@ -0,0 +1,311 @@
+// Package mal provides an [usecases.Tracker] that communicates with
+// MyAnimeList's v2 REST API.
+package mal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	telemetry "github.com/wwmoraes/gotell"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+
+	"github.com/wwmoraes/anilistarr/internal/adapters/ratedclient"
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+	"github.com/wwmoraes/anilistarr/pkg/with"
+)
+
+const (
+	// DefaultEndpoint is the base URL of the public MyAnimeList v2 API.
+	DefaultEndpoint = "https://api.myanimelist.net/v2"
+
+	// HTTPHeaderClientID contains the client ID HTTP header name used to
+	// authenticate public API requests.
+	HTTPHeaderClientID = "X-Mal-Client-Id"
+
+	// interval and requests are conservative limits; MyAnimeList does not
+	// document its rate limits, but it blocks clients that burst requests.
+	interval time.Duration = time.Minute
+	requests int           = 30
+
+	// maxPageSize is the upstream limit for animelist page sizes.
+	maxPageSize     int = 1000
+	defaultPageSize int = 100
+)
+
+var (
+	// DefaultStatuses contains the list statuses fetched by default. Those
+	// mirror the CURRENT and PLANNING statuses from Anilist.
+	DefaultStatuses = []string{"watching", "plan_to_watch"}
+
+	_ io.Closer        = (*Tracker)(nil)
+	_ usecases.Tracker = (*Tracker)(nil)
+)
+
+// Tracker abstracts a MyAnimeList REST client and provides the common requests
+// needed by MediaLister
+type Tracker struct {
+	Client   usecases.Doer
+	Endpoint string
+	ClientID string
+	Statuses []string
+	PageSize int
+}
+
+// Options contains optional settings for tracker instances.
+type Options struct {
+	Client   usecases.Doer
+	Statuses []string
+	PageSize int
+}
+
+// animeList is the animelist response from the MyAnimeList v2 API.
+type animeList struct {
+	Paging animeListPaging  `json:"paging"`
+	Data   []animeListEntry `json:"data"`
+}
+
+// animeListEntry is an anime on an user animelist.
+//
+//nolint:tagliatelle // JSON tags must match the upstream naming convention
+type animeListEntry struct {
+	ListStatus animeListStatus `json:"list_status"`
+	Node       animeListNode   `json:"node"`
+}
+
+// animeListNode is the anime details of an animelist entry.
+type animeListNode struct {
+	Title string `json:"title"`
+	ID    int    `json:"id"`
+}
+
+// animeListStatus is the user status of an animelist entry.
+type animeListStatus struct {
+	Status string `json:"status"`
+}
+
+// animeListPaging links the animelist response to its sibling pages.
+type animeListPaging struct {
+	Next string `json:"next,omitempty"`
+}
+
+// NewOptions generates an [Options] value ready to use. It starts with defaults
+// and then applies all [Option] in order.
+func NewOptions(opts ...with.Option[Options]) Options {
+	return with.Apply(Options{
+		PageSize: defaultPageSize,
+		Client:   http.DefaultClient,
+		Statuses: DefaultStatuses,
+	}, opts...)
+}
+
+// WithClient sets a custom HTTP client.
+func WithClient(client usecases.Doer) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Client = client
+	})
+}
+
+// WithPageSize sets a custom page size for paginated requests. Sizes are
+// capped to the upstream maximum.
+func WithPageSize(size int) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.PageSize = min(size, maxPageSize)
+	})
+}
+
+// WithStatuses sets which animelist statuses to include in media lists.
+func WithStatuses(statuses ...string) with.Functor[Options] {
+	return with.Functor[Options](func(options *Options) {
+		options.Statuses = statuses
+	})
+}
+
+// New creates a MyAnimeList client that uses a [ratedclient.RatedClient] to keep
+// requests within friendly limits. The client ID comes from the MyAnimeList API
+// config page and is required by all public endpoints.
+func New(endpoint, clientID string, opts ...with.Option[Options]) *Tracker {
+	options := NewOptions(opts...)
+
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	return &Tracker{
+		Client: &ratedclient.RatedClient{
+			Doer:    options.Client,
+			Limiter: rate.NewLimiter(rate.Limit(requests)*rate.Every(interval), requests),
+		},
+		Endpoint: endpoint,
+		ClientID: clientID,
+		Statuses: options.Statuses,
+		PageSize: options.PageSize,
+	}
+}
+
+// GetUserID checks that a user profile exists and returns its name.
+//
+// MyAnimeList does not expose numeric IDs of other users on its public API, and
+// all list endpoints take the user name instead. Thus the name itself is the ID.
+func (tracker *Tracker) GetUserID(ctx context.Context, name string) (string, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	_, err := tracker.getAnimeListPage(ctx, tracker.animeListURL(name, 1))
+	if err != nil {
+		return "", span.Assert(err)
+	}
+
+	return name, span.Assert(nil)
+}
+
+// GetMediaListIDs retrieves a list of medias from a user name. It includes only
+// entries with one of the given statuses, or with one of the tracker statuses
+// if none is given.
+func (tracker *Tracker) GetMediaListIDs(
+	ctx context.Context,
+	userID string,
+	statuses ...string,
+) ([]entities.SourceID, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	malIDs := make([]entities.SourceID, 0, tracker.PageSize)
+	span.SetAttributes(attribute.Int("page.size", tracker.PageSize))
+
+	if len(statuses) == 0 {
+		statuses = tracker.Statuses
+	}
+
+	for mediaID, err := range tracker.getMediaListIDs(ctx, userID, statuses) {
+		if err != nil {
+			return nil, span.Assert(err)
+		}
+
+		malIDs = append(malIDs, strconv.Itoa(mediaID))
+	}
+
+	return malIDs, span.Assert(nil)
+}
+
+// Close terminates the client to the upstream API.
+func (tracker *Tracker) Close() error {
+	tracker.Client = nil
+
+	return nil
+}
+
+func (tracker *Tracker) getMediaListIDs(
+	ctx context.Context,
+	name string,
+	statuses []string,
+) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		next := tracker.animeListURL(name, tracker.PageSize)
+
+		for next != "" {
+			res, err := tracker.getAnimeListPage(ctx, next)
+			if err != nil {
+				yield(0, err)
+
+				return
+			}
+
+			if !yieldEntries(res.Data, statuses, yield) {
+				return
+			}
+
+			next = res.Paging.Next
+		}
+	}
+}
+
+// yieldEntries yields the IDs of the entries with one of the given statuses. It
+// returns false if the consumer stops the iteration.
+func yieldEntries(
+	entries []animeListEntry,
+	statuses []string,
+	yield func(int, error) bool,
+) bool {
+	for _, entry := range entries {
+		if !slices.Contains(statuses, entry.ListStatus.Status) {
+			continue
+		}
+
+		if !yield(entry.Node.ID, nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tracker *Tracker) animeListURL(name string, limit int) string {
+	query := url.Values{}
+	query.Set("fields", "list_status")
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("nsfw", "true")
+
+	return fmt.Sprintf(
+		"%s/users/%s/animelist?%s",
+		tracker.Endpoint,
+		url.PathEscape(name),
+		query.Encode(),
+	)
+}
+
+func (tracker *Tracker) getAnimeListPage(ctx context.Context, uri string) (*animeList, error) {
+	ctx, span := telemetry.StartNamed(
+		ctx,
+		"mal.GetAnimeList",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("url", uri)),
+	)
+	defer span.End()
+
+	if tracker.Client == nil {
+		return nil, span.Assert(usecases.ErrStatusFailedPrecondition)
+	}
+
+	log := telemetry.Logr(ctx)
+
+	log.Info("requesting media list", "url", uri)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInvalidArgument, err))
+	}
+
+	req.Header.Set(HTTPHeaderClientID, tracker.ClientID)
+
+	res, err := tracker.Client.Do(req)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusUnavailable, err))
+	}
+	defer res.Body.Close()
+
+	err = usecases.ErrorFromHTTPStatus(res.StatusCode)
+	if err != nil {
+		return nil, span.Assert(fmt.Errorf("%w: %s", err, "failed to get animelist"))
+	}
+
+	var list animeList
+
+	err = json.NewDecoder(res.Body).Decode(&list)
+	if err != nil {
+		return nil, span.Assert(errors.Join(usecases.ErrStatusInternal, err))
+	}
+
+	return &list, span.Assert(nil)
+}
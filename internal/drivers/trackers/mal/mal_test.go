@@ -0,0 +1,231 @@
+package mal_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/drivers/trackers/mal"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+type malEntry struct {
+	Status string
+	ID     int
+}
+
+// newMALServer creates a stand-in for the MyAnimeList v2 API that serves the
+// given user animelists, paginated by the limit and offset query parameters.
+func newMALServer(tb testing.TB, clientID string, lists map[string][]malEntry) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+
+	var server *httptest.Server
+
+	mux.HandleFunc("GET /users/{name}/animelist", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(mal.HTTPHeaderClientID) != clientID {
+			http.Error(w, "invalid client id", http.StatusUnauthorized)
+
+			return
+		}
+
+		entries, ok := lists[r.PathValue("name")]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+
+			return
+		}
+
+		page, next := paginate(tb, r.URL.Query(), entries)
+
+		data := make([]map[string]any, 0, len(page))
+		for _, entry := range page {
+			data = append(data, map[string]any{
+				"node":        map[string]any{"id": entry.ID, "title": strconv.Itoa(entry.ID)},
+				"list_status": map[string]any{"status": entry.Status},
+			})
+		}
+
+		paging := map[string]any{}
+		if next > 0 {
+			query := r.URL.Query()
+			query.Set("offset", strconv.Itoa(next))
+			paging["next"] = fmt.Sprintf("%s%s?%s", server.URL, r.URL.Path, query.Encode())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		err := json.NewEncoder(w).Encode(map[string]any{
+			"data":   data,
+			"paging": paging,
+		})
+		if err != nil {
+			tb.Error(err)
+		}
+	})
+
+	server = httptest.NewServer(mux)
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+// paginate returns the page of entries requested by the limit and offset query
+// parameters, and the offset of the next page if there's one left.
+func paginate[T any](tb testing.TB, query url.Values, entries []T) ([]T, int) {
+	tb.Helper()
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		tb.Error(err)
+	}
+
+	offset := 0
+
+	if query.Has("offset") {
+		offset, err = strconv.Atoi(query.Get("offset"))
+		if err != nil {
+			tb.Error(err)
+		}
+	}
+
+	end := min(offset+limit, len(entries))
+	if end == len(entries) {
+		return entries[offset:end], 0
+	}
+
+	return entries[offset:end], end
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	clientID := "client"
+	server := newMALServer(t, clientID, map[string][]malEntry{
+		"foo": {
+			{ID: 1, Status: "watching"},
+			{ID: 2, Status: "completed"},
+			{ID: 3, Status: "plan_to_watch"},
+			{ID: 5, Status: "dropped"},
+			{ID: 8, Status: "watching"},
+			{ID: 13, Status: "watching"},
+		},
+	})
+
+	tracker := mal.New(
+		server.URL,
+		clientID,
+		mal.WithClient(server.Client()),
+		mal.WithPageSize(2),
+	)
+	defer tracker.Close()
+
+	gotUserID, err := tracker.GetUserID(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", gotUserID)
+
+	gotMediaList, err := tracker.GetMediaListIDs(t.Context(), gotUserID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "3", "8", "13"}, gotMediaList)
+}
+
+func TestTracker_WithStatuses(t *testing.T) {
+	t.Parallel()
+
+	clientID := "client"
+	server := newMALServer(t, clientID, map[string][]malEntry{
+		"foo": {
+			{ID: 1, Status: "watching"},
+			{ID: 2, Status: "completed"},
+			{ID: 3, Status: "on_hold"},
+		},
+	})
+
+	tracker := mal.New(
+		server.URL,
+		clientID,
+		mal.WithClient(server.Client()),
+		mal.WithStatuses("completed", "on_hold"),
+	)
+	defer tracker.Close()
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2", "3"}, got)
+}
+
+func TestTracker_GetMediaListIDs_statuses(t *testing.T) {
+	t.Parallel()
+
+	clientID := "client"
+	server := newMALServer(t, clientID, map[string][]malEntry{
+		"foo": {
+			{ID: 1, Status: "watching"},
+			{ID: 2, Status: "completed"},
+			{ID: 3, Status: "dropped"},
+		},
+	})
+
+	tracker := mal.New(server.URL, clientID, mal.WithClient(server.Client()))
+	defer tracker.Close()
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "foo", "completed", "dropped")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2", "3"}, got)
+}
+
+func TestTracker_GetUserID_not_found(t *testing.T) {
+	t.Parallel()
+
+	clientID := "client"
+	server := newMALServer(t, clientID, map[string][]malEntry{})
+
+	tracker := mal.New(server.URL, clientID, mal.WithClient(server.Client()))
+	defer tracker.Close()
+
+	got, err := tracker.GetUserID(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusNotFound)
+
+	assert.Empty(t, got)
+}
+
+func TestTracker_GetMediaListIDs_unauthenticated(t *testing.T) {
+	t.Parallel()
+
+	server := newMALServer(t, "client", map[string][]malEntry{
+		"foo": {{ID: 1, Status: "watching"}},
+	})
+
+	tracker := mal.New(server.URL, "invalid", mal.WithClient(server.Client()))
+	defer tracker.Close()
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusUnauthenticated)
+
+	assert.Nil(t, got)
+}
+
+func TestTracker_Close(t *testing.T) {
+	t.Parallel()
+
+	tracker := mal.New("", "client")
+
+	require.NoError(t, tracker.Close())
+
+	got, err := tracker.GetMediaListIDs(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusFailedPrecondition)
+
+	assert.Nil(t, got)
+}
@@ -0,0 +1,15 @@
+package entities
+
+// MappingConflict represents a source ID that mapping sources disagree on. It
+// lists the target ID of each source in priority order, where the first one is
+// the one in use.
+type MappingConflict struct {
+	SourceID SourceID        `json:"sourceID"`
+	Targets  []MappingTarget `json:"targets"`
+}
+
+// MappingTarget is the target ID a mapping source maps a source ID to.
+type MappingTarget struct {
+	Provider string   `json:"provider"`
+	TargetID TargetID `json:"targetID"`
+}
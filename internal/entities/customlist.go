@@ -3,7 +3,9 @@ package entities
 // CustomList contains custom entries in the Sonarr format.
 type CustomList []CustomEntry
 
-// CustomEntry represents one media entry in the Sonarr format.
+// CustomEntry represents one media entry in the Sonarr format. Seasons lists
+// which seasons to monitor; all of them if empty.
 type CustomEntry struct {
-	TvdbID uint64
+	Seasons []uint64 `json:"seasons,omitempty"`
+	TvdbID  uint64
 }
@@ -11,10 +11,15 @@ type (
 // Media represents a relationship between two services. It contains the ID of
 // the same media on them both.
 //
+// Target services that split medias in seasons (e.g. TVDB) may also have the
+// TargetSeason the source media matches to. It is nil if the source media
+// matches all seasons or if it is unknown.
+//
 //nolint:tagliatelle // JSON tags must match upstream naming convention
 type Media struct {
-	SourceID SourceID `db:"source_id" json:"source_id,omitempty"`
-	TargetID TargetID `db:"target_id" json:"target_id,omitempty"`
+	TargetSeason *uint64  `db:"target_season" json:"target_season,omitempty"`
+	SourceID     SourceID `db:"source_id"     json:"source_id,omitempty"`
+	TargetID     TargetID `db:"target_id"     json:"target_id,omitempty"`
 }
 
 // Valid returns true if this is a valid media i.e. it contains both non-empty,
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// movieTargetSeparator splits the TMDB and IMDb IDs within movie target IDs.
+const movieTargetSeparator = "/"
+
+// MovieList contains custom entries in the Radarr format.
+type MovieList []MovieEntry
+
+// MovieEntry represents one movie entry in the Radarr format. It is compatible
+// with both the custom and StevenLu list formats.
+//
+//nolint:tagliatelle // JSON tags must match the Radarr naming convention
+type MovieEntry struct {
+	ImdbID string `json:"imdb_id,omitempty"`
+	TmdbID uint64 `json:"tmdbId"`
+}
+
+// TargetID encodes the entry IDs as a single target ID, which allows stores to
+// map a source ID to both.
+func (entry MovieEntry) TargetID() TargetID {
+	id := strconv.FormatUint(entry.TmdbID, 10)
+
+	if entry.ImdbID == "" {
+		return id
+	}
+
+	return id + movieTargetSeparator + entry.ImdbID
+}
+
+// ParseMovieEntry decodes a target ID generated by [MovieEntry.TargetID].
+func ParseMovieEntry(id TargetID) (MovieEntry, error) {
+	tmdbID, imdbID, _ := strings.Cut(id, movieTargetSeparator)
+
+	tmdbIDInt, err := strconv.ParseUint(tmdbID, 10, 0)
+	if err != nil {
+		return MovieEntry{}, fmt.Errorf("failed to parse TMDB ID: %w", err)
+	}
+
+	return MovieEntry{
+		ImdbID: imdbID,
+		TmdbID: tmdbIDInt,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+)
+
+func TestMovieEntry_TargetID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		want  entities.TargetID
+		entry entities.MovieEntry
+	}{
+		{
+			name:  "TMDB only",
+			entry: entities.MovieEntry{TmdbID: 129},
+			want:  "129",
+		},
+		{
+			name:  "TMDB and IMDb",
+			entry: entities.MovieEntry{TmdbID: 129, ImdbID: "tt0245429"},
+			want:  "129/tt0245429",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.entry.TargetID()
+			assert.Equal(t, tt.want, got)
+
+			gotEntry, err := entities.ParseMovieEntry(got)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.entry, gotEntry)
+		})
+	}
+}
+
+func TestParseMovieEntry_invalid(t *testing.T) {
+	t.Parallel()
+
+	got, err := entities.ParseMovieEntry("tt0245429")
+	require.Error(t, err)
+
+	assert.Empty(t, got)
+}
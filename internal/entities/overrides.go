@@ -0,0 +1,30 @@
+package entities
+
+import "slices"
+
+// MappingOverrides contains local corrections to the mappings from sources.
+// Mappings replaces the target ID of source IDs, and Exclude lists source IDs
+// that must not map to anything. Exclusions take precedence.
+type MappingOverrides struct {
+	Mappings map[SourceID]TargetID `json:"mappings" yaml:"mappings"`
+	Exclude  []SourceID            `json:"exclude"  yaml:"exclude"`
+}
+
+// Excluded returns true if the source ID must not map to anything.
+func (overrides MappingOverrides) Excluded(id SourceID) bool {
+	return slices.Contains(overrides.Exclude, id)
+}
+
+// Media returns the overridden media of a source ID, or nil if it has none.
+func (overrides MappingOverrides) Media(id SourceID) *Media {
+	targetID, ok := overrides.Mappings[id]
+	if !ok || overrides.Excluded(id) {
+		return nil
+	}
+
+	return &Media{
+		SourceID:     id,
+		TargetID:     targetID,
+		TargetSeason: nil,
+	}
+}
@@ -0,0 +1,34 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+)
+
+func TestMappingOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides := entities.MappingOverrides{
+		Mappings: map[entities.SourceID]entities.TargetID{"2": "192", "3": "193", "5": "195"},
+		Exclude:  []entities.SourceID{"1", "3"},
+	}
+
+	assert.True(t, overrides.Excluded("1"))
+	assert.False(t, overrides.Excluded("2"))
+
+	assert.Nil(t, overrides.Media("3"))
+	assert.Nil(t, overrides.Media("8"))
+	assert.Equal(t, &entities.Media{SourceID: "2", TargetID: "192"}, overrides.Media("2"))
+}
+
+func TestMappingOverrides_empty(t *testing.T) {
+	t.Parallel()
+
+	var overrides entities.MappingOverrides
+
+	assert.False(t, overrides.Excluded("1"))
+	assert.Nil(t, overrides.Media("1"))
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// Token is an access token that lets a tracker act on behalf of an user, e.g.
+// to read their private media list. It never expires if ExpiresAt is zero.
+type Token struct {
+	ExpiresAt   time.Time `json:"expiresAt"`
+	UserID      string    `json:"userID"`
+	UserName    string    `json:"userName"`
+	AccessToken string    `json:"accessToken"`
+}
+
+// Valid returns true if this is a valid token i.e. it contains an user ID, an
+// user name and an access token.
+func (token *Token) Valid() bool {
+	return token.UserID != "" && token.UserName != "" && token.AccessToken != ""
+}
+
+// Expired returns true if the token expired as of now.
+func (token *Token) Expired(now time.Time) bool {
+	return !token.ExpiresAt.IsZero() && !now.Before(token.ExpiresAt)
+}
@@ -0,0 +1,59 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+)
+
+func TestToken_Valid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		token entities.Token
+		name  string
+		want  bool
+	}{
+		{
+			name:  "valid",
+			token: entities.Token{UserID: "1", UserName: "foo", AccessToken: "bar"},
+			want:  true,
+		},
+		{
+			name:  "no user ID",
+			token: entities.Token{UserName: "foo", AccessToken: "bar"},
+			want:  false,
+		},
+		{
+			name:  "no user name",
+			token: entities.Token{UserID: "1", AccessToken: "bar"},
+			want:  false,
+		},
+		{
+			name:  "no access token",
+			token: entities.Token{UserID: "1", UserName: "foo"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.token.Valid())
+		})
+	}
+}
+
+func TestToken_Expired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	assert.False(t, (&entities.Token{}).Expired(now))
+	assert.False(t, (&entities.Token{ExpiresAt: now.Add(time.Second)}).Expired(now))
+	assert.True(t, (&entities.Token{ExpiresAt: now}).Expired(now))
+}
@@ -4,7 +4,10 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
-var _ usecases.Metadata = (*Metadata)(nil)
+var (
+	_ usecases.Metadata       = (*Metadata)(nil)
+	_ usecases.SeasonMetadata = (*SeasonMetadata)(nil)
+)
 
 //nolint:tagliatelle // JSON tags must match the upstream naming convention
 type Metadata struct {
@@ -12,6 +15,11 @@ type Metadata struct {
 	TargetID string `json:"target_id,omitempty"`
 }
 
+type SeasonMetadata struct {
+	TargetSeason *uint64
+	Metadata
+}
+
 func (entry Metadata) GetSourceID() string {
 	return entry.SourceID
 }
@@ -26,3 +34,7 @@ func (entry Metadata) Valid() bool {
 		entry.TargetID != "" &&
 		entry.TargetID != "0"
 }
+
+func (entry SeasonMetadata) GetTargetSeason() *uint64 {
+	return entry.TargetSeason
+}
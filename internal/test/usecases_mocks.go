@@ -7,12 +7,159 @@ package test
 import (
 	"context"
 	"net/http"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 	"github.com/wwmoraes/anilistarr/internal/entities"
 	"github.com/wwmoraes/anilistarr/internal/usecases"
 )
 
+// NewMockAuthorizer creates a new instance of MockAuthorizer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthorizer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthorizer {
+	mock := &MockAuthorizer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuthorizer is an autogenerated mock type for the Authorizer type
+type MockAuthorizer struct {
+	mock.Mock
+}
+
+type MockAuthorizer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuthorizer) EXPECT() *MockAuthorizer_Expecter {
+	return &MockAuthorizer_Expecter{mock: &_m.Mock}
+}
+
+// AuthCodeURL provides a mock function for the type MockAuthorizer
+func (_mock *MockAuthorizer) AuthCodeURL(state string) string {
+	ret := _mock.Called(state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthCodeURL")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(state)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockAuthorizer_AuthCodeURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthCodeURL'
+type MockAuthorizer_AuthCodeURL_Call struct {
+	*mock.Call
+}
+
+// AuthCodeURL is a helper method to define mock.On call
+//   - state string
+func (_e *MockAuthorizer_Expecter) AuthCodeURL(state interface{}) *MockAuthorizer_AuthCodeURL_Call {
+	return &MockAuthorizer_AuthCodeURL_Call{Call: _e.mock.On("AuthCodeURL", state)}
+}
+
+func (_c *MockAuthorizer_AuthCodeURL_Call) Run(run func(state string)) *MockAuthorizer_AuthCodeURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuthorizer_AuthCodeURL_Call) Return(s string) *MockAuthorizer_AuthCodeURL_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockAuthorizer_AuthCodeURL_Call) RunAndReturn(run func(state string) string) *MockAuthorizer_AuthCodeURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Authorize provides a mock function for the type MockAuthorizer
+func (_mock *MockAuthorizer) Authorize(ctx context.Context, code string) (*entities.Token, error) {
+	ret := _mock.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 *entities.Token
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.Token, error)); ok {
+		return returnFunc(ctx, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.Token); ok {
+		r0 = returnFunc(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Token)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAuthorizer_Authorize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authorize'
+type MockAuthorizer_Authorize_Call struct {
+	*mock.Call
+}
+
+// Authorize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+func (_e *MockAuthorizer_Expecter) Authorize(ctx interface{}, code interface{}) *MockAuthorizer_Authorize_Call {
+	return &MockAuthorizer_Authorize_Call{Call: _e.mock.On("Authorize", ctx, code)}
+}
+
+func (_c *MockAuthorizer_Authorize_Call) Run(run func(ctx context.Context, code string)) *MockAuthorizer_Authorize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuthorizer_Authorize_Call) Return(token *entities.Token, err error) *MockAuthorizer_Authorize_Call {
+	_c.Call.Return(token, err)
+	return _c
+}
+
+func (_c *MockAuthorizer_Authorize_Call) RunAndReturn(run func(ctx context.Context, code string) (*entities.Token, error)) *MockAuthorizer_Authorize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockCache creates a new instance of MockCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockCache(t interface {
@@ -228,13 +375,13 @@ func (_c *MockCache_SetString_Call) RunAndReturn(run func(ctx context.Context, k
 	return _c
 }
 
-// NewMockDoer creates a new instance of MockDoer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockExpiringCache creates a new instance of MockExpiringCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockDoer(t interface {
+func NewMockExpiringCache(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockDoer {
-	mock := &MockDoer{}
+}) *MockExpiringCache {
+	mock := &MockExpiringCache{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -242,149 +389,102 @@ func NewMockDoer(t interface {
 	return mock
 }
 
-// MockDoer is an autogenerated mock type for the Doer type
-type MockDoer struct {
+// MockExpiringCache is an autogenerated mock type for the ExpiringCache type
+type MockExpiringCache struct {
 	mock.Mock
 }
 
-type MockDoer_Expecter struct {
+type MockExpiringCache_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockDoer) EXPECT() *MockDoer_Expecter {
-	return &MockDoer_Expecter{mock: &_m.Mock}
+func (_m *MockExpiringCache) EXPECT() *MockExpiringCache_Expecter {
+	return &MockExpiringCache_Expecter{mock: &_m.Mock}
 }
 
-// Do provides a mock function for the type MockDoer
-func (_mock *MockDoer) Do(req *http.Request) (*http.Response, error) {
-	ret := _mock.Called(req)
+// Close provides a mock function for the type MockExpiringCache
+func (_mock *MockExpiringCache) Close() error {
+	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for Do")
+		panic("no return value specified for Close")
 	}
 
-	var r0 *http.Response
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*http.Request) (*http.Response, error)); ok {
-		return returnFunc(req)
-	}
-	if returnFunc, ok := ret.Get(0).(func(*http.Request) *http.Response); ok {
-		r0 = returnFunc(req)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*http.Response)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(*http.Request) error); ok {
-		r1 = returnFunc(req)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockDoer_Do_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Do'
-type MockDoer_Do_Call struct {
+// MockExpiringCache_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockExpiringCache_Close_Call struct {
 	*mock.Call
 }
 
-// Do is a helper method to define mock.On call
-//   - req *http.Request
-func (_e *MockDoer_Expecter) Do(req interface{}) *MockDoer_Do_Call {
-	return &MockDoer_Do_Call{Call: _e.mock.On("Do", req)}
+// Close is a helper method to define mock.On call
+func (_e *MockExpiringCache_Expecter) Close() *MockExpiringCache_Close_Call {
+	return &MockExpiringCache_Close_Call{Call: _e.mock.On("Close")}
 }
 
-func (_c *MockDoer_Do_Call) Run(run func(req *http.Request)) *MockDoer_Do_Call {
+func (_c *MockExpiringCache_Close_Call) Run(run func()) *MockExpiringCache_Close_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *http.Request
-		if args[0] != nil {
-			arg0 = args[0].(*http.Request)
-		}
-		run(
-			arg0,
-		)
+		run()
 	})
 	return _c
 }
 
-func (_c *MockDoer_Do_Call) Return(response *http.Response, err error) *MockDoer_Do_Call {
-	_c.Call.Return(response, err)
+func (_c *MockExpiringCache_Close_Call) Return(err error) *MockExpiringCache_Close_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockDoer_Do_Call) RunAndReturn(run func(req *http.Request) (*http.Response, error)) *MockDoer_Do_Call {
+func (_c *MockExpiringCache_Close_Call) RunAndReturn(run func() error) *MockExpiringCache_Close_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockGetter creates a new instance of MockGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockGetter(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockGetter {
-	mock := &MockGetter{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// MockGetter is an autogenerated mock type for the Getter type
-type MockGetter struct {
-	mock.Mock
-}
-
-type MockGetter_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *MockGetter) EXPECT() *MockGetter_Expecter {
-	return &MockGetter_Expecter{mock: &_m.Mock}
-}
-
-// Get provides a mock function for the type MockGetter
-func (_mock *MockGetter) Get(ctx context.Context, uri string) ([]byte, error) {
-	ret := _mock.Called(ctx, uri)
+// GetString provides a mock function for the type MockExpiringCache
+func (_mock *MockExpiringCache) GetString(ctx context.Context, key string) (string, error) {
+	ret := _mock.Called(ctx, key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for GetString")
 	}
 
-	var r0 []byte
+	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
-		return returnFunc(ctx, uri)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, key)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
-		r0 = returnFunc(ctx, uri)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, key)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
-		}
+		r0 = ret.Get(0).(string)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, uri)
+		r1 = returnFunc(ctx, key)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockGetter_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockGetter_Get_Call struct {
+// MockExpiringCache_GetString_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetString'
+type MockExpiringCache_GetString_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
+// GetString is a helper method to define mock.On call
 //   - ctx context.Context
-//   - uri string
-func (_e *MockGetter_Expecter) Get(ctx interface{}, uri interface{}) *MockGetter_Get_Call {
-	return &MockGetter_Get_Call{Call: _e.mock.On("Get", ctx, uri)}
+//   - key string
+func (_e *MockExpiringCache_Expecter) GetString(ctx interface{}, key interface{}) *MockExpiringCache_GetString_Call {
+	return &MockExpiringCache_GetString_Call{Call: _e.mock.On("GetString", ctx, key)}
 }
 
-func (_c *MockGetter_Get_Call) Run(run func(ctx context.Context, uri string)) *MockGetter_Get_Call {
+func (_c *MockExpiringCache_GetString_Call) Run(run func(ctx context.Context, key string)) *MockExpiringCache_GetString_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -402,128 +502,127 @@ func (_c *MockGetter_Get_Call) Run(run func(ctx context.Context, uri string)) *M
 	return _c
 }
 
-func (_c *MockGetter_Get_Call) Return(bytes []byte, err error) *MockGetter_Get_Call {
-	_c.Call.Return(bytes, err)
+func (_c *MockExpiringCache_GetString_Call) Return(s string, err error) *MockExpiringCache_GetString_Call {
+	_c.Call.Return(s, err)
 	return _c
 }
 
-func (_c *MockGetter_Get_Call) RunAndReturn(run func(ctx context.Context, uri string) ([]byte, error)) *MockGetter_Get_Call {
+func (_c *MockExpiringCache_GetString_Call) RunAndReturn(run func(ctx context.Context, key string) (string, error)) *MockExpiringCache_GetString_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockMediaLister creates a new instance of MockMediaLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockMediaLister(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockMediaLister {
-	mock := &MockMediaLister{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+// GetStringTTL provides a mock function for the type MockExpiringCache
+func (_mock *MockExpiringCache) GetStringTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	ret := _mock.Called(ctx, key)
 
-	return mock
-}
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringTTL")
+	}
 
-// MockMediaLister is an autogenerated mock type for the MediaLister type
-type MockMediaLister struct {
-	mock.Mock
+	var r0 string
+	var r1 time.Duration
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, time.Duration, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) time.Duration); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = returnFunc(ctx, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-type MockMediaLister_Expecter struct {
-	mock *mock.Mock
+// MockExpiringCache_GetStringTTL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStringTTL'
+type MockExpiringCache_GetStringTTL_Call struct {
+	*mock.Call
 }
 
-func (_m *MockMediaLister) EXPECT() *MockMediaLister_Expecter {
-	return &MockMediaLister_Expecter{mock: &_m.Mock}
+// GetStringTTL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockExpiringCache_Expecter) GetStringTTL(ctx interface{}, key interface{}) *MockExpiringCache_GetStringTTL_Call {
+	return &MockExpiringCache_GetStringTTL_Call{Call: _e.mock.On("GetStringTTL", ctx, key)}
 }
 
-// Close provides a mock function for the type MockMediaLister
-func (_mock *MockMediaLister) Close() error {
-	ret := _mock.Called()
-
-	if len(ret) == 0 {
-		panic("no return value specified for Close")
-	}
-
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
-	} else {
-		r0 = ret.Error(0)
-	}
-	return r0
-}
-
-// MockMediaLister_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
-type MockMediaLister_Close_Call struct {
-	*mock.Call
-}
-
-// Close is a helper method to define mock.On call
-func (_e *MockMediaLister_Expecter) Close() *MockMediaLister_Close_Call {
-	return &MockMediaLister_Close_Call{Call: _e.mock.On("Close")}
-}
-
-func (_c *MockMediaLister_Close_Call) Run(run func()) *MockMediaLister_Close_Call {
+func (_c *MockExpiringCache_GetStringTTL_Call) Run(run func(ctx context.Context, key string)) *MockExpiringCache_GetStringTTL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockMediaLister_Close_Call) Return(err error) *MockMediaLister_Close_Call {
-	_c.Call.Return(err)
+func (_c *MockExpiringCache_GetStringTTL_Call) Return(s string, duration time.Duration, err error) *MockExpiringCache_GetStringTTL_Call {
+	_c.Call.Return(s, duration, err)
 	return _c
 }
 
-func (_c *MockMediaLister_Close_Call) RunAndReturn(run func() error) *MockMediaLister_Close_Call {
+func (_c *MockExpiringCache_GetStringTTL_Call) RunAndReturn(run func(ctx context.Context, key string) (string, time.Duration, error)) *MockExpiringCache_GetStringTTL_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Generate provides a mock function for the type MockMediaLister
-func (_mock *MockMediaLister) Generate(ctx context.Context, name string) (entities.CustomList, error) {
-	ret := _mock.Called(ctx, name)
+// SetString provides a mock function for the type MockExpiringCache
+func (_mock *MockExpiringCache) SetString(ctx context.Context, key string, value string, options ...usecases.CacheOption) error {
+	var tmpRet mock.Arguments
+	if len(options) > 0 {
+		tmpRet = _mock.Called(ctx, key, value, options)
+	} else {
+		tmpRet = _mock.Called(ctx, key, value)
+	}
+	ret := tmpRet
 
 	if len(ret) == 0 {
-		panic("no return value specified for Generate")
+		panic("no return value specified for SetString")
 	}
 
-	var r0 entities.CustomList
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (entities.CustomList, error)); ok {
-		return returnFunc(ctx, name)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) entities.CustomList); ok {
-		r0 = returnFunc(ctx, name)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(entities.CustomList)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, name)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, ...usecases.CacheOption) error); ok {
+		r0 = returnFunc(ctx, key, value, options...)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockMediaLister_Generate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Generate'
-type MockMediaLister_Generate_Call struct {
+// MockExpiringCache_SetString_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetString'
+type MockExpiringCache_SetString_Call struct {
 	*mock.Call
 }
 
-// Generate is a helper method to define mock.On call
+// SetString is a helper method to define mock.On call
 //   - ctx context.Context
-//   - name string
-func (_e *MockMediaLister_Expecter) Generate(ctx interface{}, name interface{}) *MockMediaLister_Generate_Call {
-	return &MockMediaLister_Generate_Call{Call: _e.mock.On("Generate", ctx, name)}
+//   - key string
+//   - value string
+//   - options ...usecases.CacheOption
+func (_e *MockExpiringCache_Expecter) SetString(ctx interface{}, key interface{}, value interface{}, options ...interface{}) *MockExpiringCache_SetString_Call {
+	return &MockExpiringCache_SetString_Call{Call: _e.mock.On("SetString",
+		append([]interface{}{ctx, key, value}, options...)...)}
 }
 
-func (_c *MockMediaLister_Generate_Call) Run(run func(ctx context.Context, name string)) *MockMediaLister_Generate_Call {
+func (_c *MockExpiringCache_SetString_Call) Run(run func(ctx context.Context, key string, value string, options ...usecases.CacheOption)) *MockExpiringCache_SetString_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -533,139 +632,201 @@ func (_c *MockMediaLister_Generate_Call) Run(run func(ctx context.Context, name
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 []usecases.CacheOption
+		var variadicArgs []usecases.CacheOption
+		if len(args) > 3 {
+			variadicArgs = args[3].([]usecases.CacheOption)
+		}
+		arg3 = variadicArgs
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockMediaLister_Generate_Call) Return(customList entities.CustomList, err error) *MockMediaLister_Generate_Call {
-	_c.Call.Return(customList, err)
+func (_c *MockExpiringCache_SetString_Call) Return(err error) *MockExpiringCache_SetString_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMediaLister_Generate_Call) RunAndReturn(run func(ctx context.Context, name string) (entities.CustomList, error)) *MockMediaLister_Generate_Call {
+func (_c *MockExpiringCache_SetString_Call) RunAndReturn(run func(ctx context.Context, key string, value string, options ...usecases.CacheOption) error) *MockExpiringCache_SetString_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserID provides a mock function for the type MockMediaLister
-func (_mock *MockMediaLister) GetUserID(ctx context.Context, name string) (string, error) {
-	ret := _mock.Called(ctx, name)
+// NewMockDoer creates a new instance of MockDoer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDoer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDoer {
+	mock := &MockDoer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockDoer is an autogenerated mock type for the Doer type
+type MockDoer struct {
+	mock.Mock
+}
+
+type MockDoer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDoer) EXPECT() *MockDoer_Expecter {
+	return &MockDoer_Expecter{mock: &_m.Mock}
+}
+
+// Do provides a mock function for the type MockDoer
+func (_mock *MockDoer) Do(req *http.Request) (*http.Response, error) {
+	ret := _mock.Called(req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserID")
+		panic("no return value specified for Do")
 	}
 
-	var r0 string
+	var r0 *http.Response
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return returnFunc(ctx, name)
+	if returnFunc, ok := ret.Get(0).(func(*http.Request) (*http.Response, error)); ok {
+		return returnFunc(req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = returnFunc(ctx, name)
+	if returnFunc, ok := ret.Get(0).(func(*http.Request) *http.Response); ok {
+		r0 = returnFunc(req)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*http.Response)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, name)
+	if returnFunc, ok := ret.Get(1).(func(*http.Request) error); ok {
+		r1 = returnFunc(req)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockMediaLister_GetUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserID'
-type MockMediaLister_GetUserID_Call struct {
+// MockDoer_Do_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Do'
+type MockDoer_Do_Call struct {
 	*mock.Call
 }
 
-// GetUserID is a helper method to define mock.On call
-//   - ctx context.Context
-//   - name string
-func (_e *MockMediaLister_Expecter) GetUserID(ctx interface{}, name interface{}) *MockMediaLister_GetUserID_Call {
-	return &MockMediaLister_GetUserID_Call{Call: _e.mock.On("GetUserID", ctx, name)}
+// Do is a helper method to define mock.On call
+//   - req *http.Request
+func (_e *MockDoer_Expecter) Do(req interface{}) *MockDoer_Do_Call {
+	return &MockDoer_Do_Call{Call: _e.mock.On("Do", req)}
 }
 
-func (_c *MockMediaLister_GetUserID_Call) Run(run func(ctx context.Context, name string)) *MockMediaLister_GetUserID_Call {
+func (_c *MockDoer_Do_Call) Run(run func(req *http.Request)) *MockDoer_Do_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 context.Context
+		var arg0 *http.Request
 		if args[0] != nil {
-			arg0 = args[0].(context.Context)
-		}
-		var arg1 string
-		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg0 = args[0].(*http.Request)
 		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockMediaLister_GetUserID_Call) Return(s string, err error) *MockMediaLister_GetUserID_Call {
-	_c.Call.Return(s, err)
+func (_c *MockDoer_Do_Call) Return(response *http.Response, err error) *MockDoer_Do_Call {
+	_c.Call.Return(response, err)
 	return _c
 }
 
-func (_c *MockMediaLister_GetUserID_Call) RunAndReturn(run func(ctx context.Context, name string) (string, error)) *MockMediaLister_GetUserID_Call {
+func (_c *MockDoer_Do_Call) RunAndReturn(run func(req *http.Request) (*http.Response, error)) *MockDoer_Do_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// MapIDs provides a mock function for the type MockMediaLister
-func (_mock *MockMediaLister) MapIDs(ctx context.Context, ids []entities.SourceID) ([]entities.TargetID, error) {
-	ret := _mock.Called(ctx, ids)
+// NewMockGetter creates a new instance of MockGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGetter {
+	mock := &MockGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockGetter is an autogenerated mock type for the Getter type
+type MockGetter struct {
+	mock.Mock
+}
+
+type MockGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGetter) EXPECT() *MockGetter_Expecter {
+	return &MockGetter_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function for the type MockGetter
+func (_mock *MockGetter) Get(ctx context.Context, uri string) ([]byte, error) {
+	ret := _mock.Called(ctx, uri)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MapIDs")
+		panic("no return value specified for Get")
 	}
 
-	var r0 []entities.TargetID
+	var r0 []byte
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []entities.SourceID) ([]entities.TargetID, error)); ok {
-		return returnFunc(ctx, ids)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return returnFunc(ctx, uri)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []entities.SourceID) []entities.TargetID); ok {
-		r0 = returnFunc(ctx, ids)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = returnFunc(ctx, uri)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entities.TargetID)
+			r0 = ret.Get(0).([]byte)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []entities.SourceID) error); ok {
-		r1 = returnFunc(ctx, ids)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, uri)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockMediaLister_MapIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MapIDs'
-type MockMediaLister_MapIDs_Call struct {
+// MockGetter_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockGetter_Get_Call struct {
 	*mock.Call
 }
 
-// MapIDs is a helper method to define mock.On call
+// Get is a helper method to define mock.On call
 //   - ctx context.Context
-//   - ids []entities.SourceID
-func (_e *MockMediaLister_Expecter) MapIDs(ctx interface{}, ids interface{}) *MockMediaLister_MapIDs_Call {
-	return &MockMediaLister_MapIDs_Call{Call: _e.mock.On("MapIDs", ctx, ids)}
+//   - uri string
+func (_e *MockGetter_Expecter) Get(ctx interface{}, uri interface{}) *MockGetter_Get_Call {
+	return &MockGetter_Get_Call{Call: _e.mock.On("Get", ctx, uri)}
 }
 
-func (_c *MockMediaLister_MapIDs_Call) Run(run func(ctx context.Context, ids []entities.SourceID)) *MockMediaLister_MapIDs_Call {
+func (_c *MockGetter_Get_Call) Run(run func(ctx context.Context, uri string)) *MockGetter_Get_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []entities.SourceID
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].([]entities.SourceID)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -675,46 +836,671 @@ func (_c *MockMediaLister_MapIDs_Call) Run(run func(ctx context.Context, ids []e
 	return _c
 }
 
-func (_c *MockMediaLister_MapIDs_Call) Return(vs []entities.TargetID, err error) *MockMediaLister_MapIDs_Call {
-	_c.Call.Return(vs, err)
+func (_c *MockGetter_Get_Call) Return(bytes []byte, err error) *MockGetter_Get_Call {
+	_c.Call.Return(bytes, err)
 	return _c
 }
 
-func (_c *MockMediaLister_MapIDs_Call) RunAndReturn(run func(ctx context.Context, ids []entities.SourceID) ([]entities.TargetID, error)) *MockMediaLister_MapIDs_Call {
+func (_c *MockGetter_Get_Call) RunAndReturn(run func(ctx context.Context, uri string) ([]byte, error)) *MockGetter_Get_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Refresh provides a mock function for the type MockMediaLister
+// NewMockMediaLister creates a new instance of MockMediaLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMediaLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMediaLister {
+	mock := &MockMediaLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMediaLister is an autogenerated mock type for the MediaLister type
+type MockMediaLister struct {
+	mock.Mock
+}
+
+type MockMediaLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMediaLister) EXPECT() *MockMediaLister_Expecter {
+	return &MockMediaLister_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function for the type MockMediaLister
+func (_mock *MockMediaLister) Close() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMediaLister_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockMediaLister_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *MockMediaLister_Expecter) Close() *MockMediaLister_Close_Call {
+	return &MockMediaLister_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *MockMediaLister_Close_Call) Run(run func()) *MockMediaLister_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMediaLister_Close_Call) Return(err error) *MockMediaLister_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMediaLister_Close_Call) RunAndReturn(run func() error) *MockMediaLister_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Generate provides a mock function for the type MockMediaLister
+func (_mock *MockMediaLister) Generate(ctx context.Context, name string, statuses ...string) (entities.CustomList, error) {
+	var tmpRet mock.Arguments
+	if len(statuses) > 0 {
+		tmpRet = _mock.Called(ctx, name, statuses)
+	} else {
+		tmpRet = _mock.Called(ctx, name)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Generate")
+	}
+
+	var r0 entities.CustomList
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) (entities.CustomList, error)); ok {
+		return returnFunc(ctx, name, statuses...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) entities.CustomList); ok {
+		r0 = returnFunc(ctx, name, statuses...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entities.CustomList)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = returnFunc(ctx, name, statuses...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMediaLister_Generate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Generate'
+type MockMediaLister_Generate_Call struct {
+	*mock.Call
+}
+
+// Generate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - statuses ...string
+func (_e *MockMediaLister_Expecter) Generate(ctx interface{}, name interface{}, statuses ...interface{}) *MockMediaLister_Generate_Call {
+	return &MockMediaLister_Generate_Call{Call: _e.mock.On("Generate",
+		append([]interface{}{ctx, name}, statuses...)...)}
+}
+
+func (_c *MockMediaLister_Generate_Call) Run(run func(ctx context.Context, name string, statuses ...string)) *MockMediaLister_Generate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		var variadicArgs []string
+		if len(args) > 2 {
+			variadicArgs = args[2].([]string)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMediaLister_Generate_Call) Return(customList entities.CustomList, err error) *MockMediaLister_Generate_Call {
+	_c.Call.Return(customList, err)
+	return _c
+}
+
+func (_c *MockMediaLister_Generate_Call) RunAndReturn(run func(ctx context.Context, name string, statuses ...string) (entities.CustomList, error)) *MockMediaLister_Generate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GenerateMovies provides a mock function for the type MockMediaLister
+func (_mock *MockMediaLister) GenerateMovies(ctx context.Context, name string, statuses ...string) (entities.MovieList, error) {
+	var tmpRet mock.Arguments
+	if len(statuses) > 0 {
+		tmpRet = _mock.Called(ctx, name, statuses)
+	} else {
+		tmpRet = _mock.Called(ctx, name)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateMovies")
+	}
+
+	var r0 entities.MovieList
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) (entities.MovieList, error)); ok {
+		return returnFunc(ctx, name, statuses...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) entities.MovieList); ok {
+		r0 = returnFunc(ctx, name, statuses...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entities.MovieList)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = returnFunc(ctx, name, statuses...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMediaLister_GenerateMovies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateMovies'
+type MockMediaLister_GenerateMovies_Call struct {
+	*mock.Call
+}
+
+// GenerateMovies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - statuses ...string
+func (_e *MockMediaLister_Expecter) GenerateMovies(ctx interface{}, name interface{}, statuses ...interface{}) *MockMediaLister_GenerateMovies_Call {
+	return &MockMediaLister_GenerateMovies_Call{Call: _e.mock.On("GenerateMovies",
+		append([]interface{}{ctx, name}, statuses...)...)}
+}
+
+func (_c *MockMediaLister_GenerateMovies_Call) Run(run func(ctx context.Context, name string, statuses ...string)) *MockMediaLister_GenerateMovies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		var variadicArgs []string
+		if len(args) > 2 {
+			variadicArgs = args[2].([]string)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMediaLister_GenerateMovies_Call) Return(movieList entities.MovieList, err error) *MockMediaLister_GenerateMovies_Call {
+	_c.Call.Return(movieList, err)
+	return _c
+}
+
+func (_c *MockMediaLister_GenerateMovies_Call) RunAndReturn(run func(ctx context.Context, name string, statuses ...string) (entities.MovieList, error)) *MockMediaLister_GenerateMovies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserID provides a mock function for the type MockMediaLister
+func (_mock *MockMediaLister) GetUserID(ctx context.Context, name string) (string, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserID")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMediaLister_GetUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserID'
+type MockMediaLister_GetUserID_Call struct {
+	*mock.Call
+}
+
+// GetUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockMediaLister_Expecter) GetUserID(ctx interface{}, name interface{}) *MockMediaLister_GetUserID_Call {
+	return &MockMediaLister_GetUserID_Call{Call: _e.mock.On("GetUserID", ctx, name)}
+}
+
+func (_c *MockMediaLister_GetUserID_Call) Run(run func(ctx context.Context, name string)) *MockMediaLister_GetUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMediaLister_GetUserID_Call) Return(s string, err error) *MockMediaLister_GetUserID_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockMediaLister_GetUserID_Call) RunAndReturn(run func(ctx context.Context, name string) (string, error)) *MockMediaLister_GetUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MapIDs provides a mock function for the type MockMediaLister
+func (_mock *MockMediaLister) MapIDs(ctx context.Context, ids []entities.SourceID) ([]entities.TargetID, error) {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MapIDs")
+	}
+
+	var r0 []entities.TargetID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []entities.SourceID) ([]entities.TargetID, error)); ok {
+		return returnFunc(ctx, ids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []entities.SourceID) []entities.TargetID); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.TargetID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []entities.SourceID) error); ok {
+		r1 = returnFunc(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMediaLister_MapIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MapIDs'
+type MockMediaLister_MapIDs_Call struct {
+	*mock.Call
+}
+
+// MapIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []entities.SourceID
+func (_e *MockMediaLister_Expecter) MapIDs(ctx interface{}, ids interface{}) *MockMediaLister_MapIDs_Call {
+	return &MockMediaLister_MapIDs_Call{Call: _e.mock.On("MapIDs", ctx, ids)}
+}
+
+func (_c *MockMediaLister_MapIDs_Call) Run(run func(ctx context.Context, ids []entities.SourceID)) *MockMediaLister_MapIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []entities.SourceID
+		if args[1] != nil {
+			arg1 = args[1].([]entities.SourceID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMediaLister_MapIDs_Call) Return(vs []entities.TargetID, err error) *MockMediaLister_MapIDs_Call {
+	_c.Call.Return(vs, err)
+	return _c
+}
+
+func (_c *MockMediaLister_MapIDs_Call) RunAndReturn(run func(ctx context.Context, ids []entities.SourceID) ([]entities.TargetID, error)) *MockMediaLister_MapIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refresh provides a mock function for the type MockMediaLister
 func (_mock *MockMediaLister) Refresh(ctx context.Context, client usecases.Getter) error {
 	ret := _mock.Called(ctx, client)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Refresh")
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, usecases.Getter) error); ok {
+		r0 = returnFunc(ctx, client)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMediaLister_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
+type MockMediaLister_Refresh_Call struct {
+	*mock.Call
+}
+
+// Refresh is a helper method to define mock.On call
+//   - ctx context.Context
+//   - client usecases.Getter
+func (_e *MockMediaLister_Expecter) Refresh(ctx interface{}, client interface{}) *MockMediaLister_Refresh_Call {
+	return &MockMediaLister_Refresh_Call{Call: _e.mock.On("Refresh", ctx, client)}
+}
+
+func (_c *MockMediaLister_Refresh_Call) Run(run func(ctx context.Context, client usecases.Getter)) *MockMediaLister_Refresh_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 usecases.Getter
+		if args[1] != nil {
+			arg1 = args[1].(usecases.Getter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMediaLister_Refresh_Call) Return(err error) *MockMediaLister_Refresh_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMediaLister_Refresh_Call) RunAndReturn(run func(ctx context.Context, client usecases.Getter) error) *MockMediaLister_Refresh_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMetadata creates a new instance of MockMetadata. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetadata(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetadata {
+	mock := &MockMetadata{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockMetadata is an autogenerated mock type for the Metadata type
+type MockMetadata struct {
+	mock.Mock
+}
+
+type MockMetadata_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetadata) EXPECT() *MockMetadata_Expecter {
+	return &MockMetadata_Expecter{mock: &_m.Mock}
+}
+
+// GetSourceID provides a mock function for the type MockMetadata
+func (_mock *MockMetadata) GetSourceID() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSourceID")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockMetadata_GetSourceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSourceID'
+type MockMetadata_GetSourceID_Call struct {
+	*mock.Call
+}
+
+// GetSourceID is a helper method to define mock.On call
+func (_e *MockMetadata_Expecter) GetSourceID() *MockMetadata_GetSourceID_Call {
+	return &MockMetadata_GetSourceID_Call{Call: _e.mock.On("GetSourceID")}
+}
+
+func (_c *MockMetadata_GetSourceID_Call) Run(run func()) *MockMetadata_GetSourceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetadata_GetSourceID_Call) Return(s string) *MockMetadata_GetSourceID_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockMetadata_GetSourceID_Call) RunAndReturn(run func() string) *MockMetadata_GetSourceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTargetID provides a mock function for the type MockMetadata
+func (_mock *MockMetadata) GetTargetID() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTargetID")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockMetadata_GetTargetID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTargetID'
+type MockMetadata_GetTargetID_Call struct {
+	*mock.Call
+}
+
+// GetTargetID is a helper method to define mock.On call
+func (_e *MockMetadata_Expecter) GetTargetID() *MockMetadata_GetTargetID_Call {
+	return &MockMetadata_GetTargetID_Call{Call: _e.mock.On("GetTargetID")}
+}
+
+func (_c *MockMetadata_GetTargetID_Call) Run(run func()) *MockMetadata_GetTargetID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetadata_GetTargetID_Call) Return(s string) *MockMetadata_GetTargetID_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockMetadata_GetTargetID_Call) RunAndReturn(run func() string) *MockMetadata_GetTargetID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Valid provides a mock function for the type MockMetadata
+func (_mock *MockMetadata) Valid() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Valid")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockMetadata_Valid_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Valid'
+type MockMetadata_Valid_Call struct {
+	*mock.Call
+}
+
+// Valid is a helper method to define mock.On call
+func (_e *MockMetadata_Expecter) Valid() *MockMetadata_Valid_Call {
+	return &MockMetadata_Valid_Call{Call: _e.mock.On("Valid")}
+}
+
+func (_c *MockMetadata_Valid_Call) Run(run func()) *MockMetadata_Valid_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetadata_Valid_Call) Return(b bool) *MockMetadata_Valid_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockMetadata_Valid_Call) RunAndReturn(run func() bool) *MockMetadata_Valid_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSource creates a new instance of MockSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSource {
+	mock := &MockSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSource is an autogenerated mock type for the Source type
+type MockSource struct {
+	mock.Mock
+}
+
+type MockSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSource) EXPECT() *MockSource_Expecter {
+	return &MockSource_Expecter{mock: &_m.Mock}
+}
+
+// Fetch provides a mock function for the type MockSource
+func (_mock *MockSource) Fetch(ctx context.Context, client usecases.Getter) ([]usecases.Metadata, error) {
+	ret := _mock.Called(ctx, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, usecases.Getter) error); ok {
+	var r0 []usecases.Metadata
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, usecases.Getter) ([]usecases.Metadata, error)); ok {
+		return returnFunc(ctx, client)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, usecases.Getter) []usecases.Metadata); ok {
 		r0 = returnFunc(ctx, client)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]usecases.Metadata)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, usecases.Getter) error); ok {
+		r1 = returnFunc(ctx, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockMediaLister_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
-type MockMediaLister_Refresh_Call struct {
+// MockSource_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
+type MockSource_Fetch_Call struct {
 	*mock.Call
 }
 
-// Refresh is a helper method to define mock.On call
+// Fetch is a helper method to define mock.On call
 //   - ctx context.Context
 //   - client usecases.Getter
-func (_e *MockMediaLister_Expecter) Refresh(ctx interface{}, client interface{}) *MockMediaLister_Refresh_Call {
-	return &MockMediaLister_Refresh_Call{Call: _e.mock.On("Refresh", ctx, client)}
+func (_e *MockSource_Expecter) Fetch(ctx interface{}, client interface{}) *MockSource_Fetch_Call {
+	return &MockSource_Fetch_Call{Call: _e.mock.On("Fetch", ctx, client)}
 }
 
-func (_c *MockMediaLister_Refresh_Call) Run(run func(ctx context.Context, client usecases.Getter)) *MockMediaLister_Refresh_Call {
+func (_c *MockSource_Fetch_Call) Run(run func(ctx context.Context, client usecases.Getter)) *MockSource_Fetch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -732,23 +1518,67 @@ func (_c *MockMediaLister_Refresh_Call) Run(run func(ctx context.Context, client
 	return _c
 }
 
-func (_c *MockMediaLister_Refresh_Call) Return(err error) *MockMediaLister_Refresh_Call {
-	_c.Call.Return(err)
+func (_c *MockSource_Fetch_Call) Return(metadatas []usecases.Metadata, err error) *MockSource_Fetch_Call {
+	_c.Call.Return(metadatas, err)
 	return _c
 }
 
-func (_c *MockMediaLister_Refresh_Call) RunAndReturn(run func(ctx context.Context, client usecases.Getter) error) *MockMediaLister_Refresh_Call {
+func (_c *MockSource_Fetch_Call) RunAndReturn(run func(ctx context.Context, client usecases.Getter) ([]usecases.Metadata, error)) *MockSource_Fetch_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockMetadata creates a new instance of MockMetadata. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// String provides a mock function for the type MockSource
+func (_mock *MockSource) String() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for String")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockSource_String_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'String'
+type MockSource_String_Call struct {
+	*mock.Call
+}
+
+// String is a helper method to define mock.On call
+func (_e *MockSource_Expecter) String() *MockSource_String_Call {
+	return &MockSource_String_Call{Call: _e.mock.On("String")}
+}
+
+func (_c *MockSource_String_Call) Run(run func()) *MockSource_String_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSource_String_Call) Return(s string) *MockSource_String_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockSource_String_Call) RunAndReturn(run func() string) *MockSource_String_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockConflictReporter creates a new instance of MockConflictReporter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockMetadata(t interface {
+func NewMockConflictReporter(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockMetadata {
-	mock := &MockMetadata{}
+}) *MockConflictReporter {
+	mock := &MockConflictReporter{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -756,227 +1586,253 @@ func NewMockMetadata(t interface {
 	return mock
 }
 
-// MockMetadata is an autogenerated mock type for the Metadata type
-type MockMetadata struct {
+// MockConflictReporter is an autogenerated mock type for the ConflictReporter type
+type MockConflictReporter struct {
 	mock.Mock
 }
 
-type MockMetadata_Expecter struct {
+type MockConflictReporter_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockMetadata) EXPECT() *MockMetadata_Expecter {
-	return &MockMetadata_Expecter{mock: &_m.Mock}
+func (_m *MockConflictReporter) EXPECT() *MockConflictReporter_Expecter {
+	return &MockConflictReporter_Expecter{mock: &_m.Mock}
 }
 
-// GetSourceID provides a mock function for the type MockMetadata
-func (_mock *MockMetadata) GetSourceID() string {
+// Conflicts provides a mock function for the type MockConflictReporter
+func (_mock *MockConflictReporter) Conflicts() []entities.MappingConflict {
 	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSourceID")
+		panic("no return value specified for Conflicts")
 	}
 
-	var r0 string
-	if returnFunc, ok := ret.Get(0).(func() string); ok {
+	var r0 []entities.MappingConflict
+	if returnFunc, ok := ret.Get(0).(func() []entities.MappingConflict); ok {
 		r0 = returnFunc()
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.MappingConflict)
+		}
 	}
 	return r0
 }
 
-// MockMetadata_GetSourceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSourceID'
-type MockMetadata_GetSourceID_Call struct {
+// MockConflictReporter_Conflicts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Conflicts'
+type MockConflictReporter_Conflicts_Call struct {
 	*mock.Call
 }
 
-// GetSourceID is a helper method to define mock.On call
-func (_e *MockMetadata_Expecter) GetSourceID() *MockMetadata_GetSourceID_Call {
-	return &MockMetadata_GetSourceID_Call{Call: _e.mock.On("GetSourceID")}
+// Conflicts is a helper method to define mock.On call
+func (_e *MockConflictReporter_Expecter) Conflicts() *MockConflictReporter_Conflicts_Call {
+	return &MockConflictReporter_Conflicts_Call{Call: _e.mock.On("Conflicts")}
 }
 
-func (_c *MockMetadata_GetSourceID_Call) Run(run func()) *MockMetadata_GetSourceID_Call {
+func (_c *MockConflictReporter_Conflicts_Call) Run(run func()) *MockConflictReporter_Conflicts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run()
 	})
 	return _c
 }
 
-func (_c *MockMetadata_GetSourceID_Call) Return(s string) *MockMetadata_GetSourceID_Call {
-	_c.Call.Return(s)
+func (_c *MockConflictReporter_Conflicts_Call) Return(mappingConflicts []entities.MappingConflict) *MockConflictReporter_Conflicts_Call {
+	_c.Call.Return(mappingConflicts)
 	return _c
 }
 
-func (_c *MockMetadata_GetSourceID_Call) RunAndReturn(run func() string) *MockMetadata_GetSourceID_Call {
+func (_c *MockConflictReporter_Conflicts_Call) RunAndReturn(run func() []entities.MappingConflict) *MockConflictReporter_Conflicts_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTargetID provides a mock function for the type MockMetadata
-func (_mock *MockMetadata) GetTargetID() string {
+// NewMockStore creates a new instance of MockStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStore {
+	mock := &MockStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockStore is an autogenerated mock type for the Store type
+type MockStore struct {
+	mock.Mock
+}
+
+type MockStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStore) EXPECT() *MockStore_Expecter {
+	return &MockStore_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function for the type MockStore
+func (_mock *MockStore) Close() error {
 	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTargetID")
+		panic("no return value specified for Close")
 	}
 
-	var r0 string
-	if returnFunc, ok := ret.Get(0).(func() string); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
 		r0 = returnFunc()
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockMetadata_GetTargetID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTargetID'
-type MockMetadata_GetTargetID_Call struct {
+// MockStore_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockStore_Close_Call struct {
 	*mock.Call
 }
-
-// GetTargetID is a helper method to define mock.On call
-func (_e *MockMetadata_Expecter) GetTargetID() *MockMetadata_GetTargetID_Call {
-	return &MockMetadata_GetTargetID_Call{Call: _e.mock.On("GetTargetID")}
+
+// Close is a helper method to define mock.On call
+func (_e *MockStore_Expecter) Close() *MockStore_Close_Call {
+	return &MockStore_Close_Call{Call: _e.mock.On("Close")}
 }
 
-func (_c *MockMetadata_GetTargetID_Call) Run(run func()) *MockMetadata_GetTargetID_Call {
+func (_c *MockStore_Close_Call) Run(run func()) *MockStore_Close_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run()
 	})
 	return _c
 }
 
-func (_c *MockMetadata_GetTargetID_Call) Return(s string) *MockMetadata_GetTargetID_Call {
-	_c.Call.Return(s)
+func (_c *MockStore_Close_Call) Return(err error) *MockStore_Close_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMetadata_GetTargetID_Call) RunAndReturn(run func() string) *MockMetadata_GetTargetID_Call {
+func (_c *MockStore_Close_Call) RunAndReturn(run func() error) *MockStore_Close_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Valid provides a mock function for the type MockMetadata
-func (_mock *MockMetadata) Valid() bool {
-	ret := _mock.Called()
+// GetMedia provides a mock function for the type MockStore
+func (_mock *MockStore) GetMedia(ctx context.Context, id string) (*entities.Media, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Valid")
+		panic("no return value specified for GetMedia")
 	}
 
-	var r0 bool
-	if returnFunc, ok := ret.Get(0).(func() bool); ok {
-		r0 = returnFunc()
+	var r0 *entities.Media
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.Media, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.Media); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Media)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockMetadata_Valid_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Valid'
-type MockMetadata_Valid_Call struct {
+// MockStore_GetMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMedia'
+type MockStore_GetMedia_Call struct {
 	*mock.Call
 }
 
-// Valid is a helper method to define mock.On call
-func (_e *MockMetadata_Expecter) Valid() *MockMetadata_Valid_Call {
-	return &MockMetadata_Valid_Call{Call: _e.mock.On("Valid")}
+// GetMedia is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockStore_Expecter) GetMedia(ctx interface{}, id interface{}) *MockStore_GetMedia_Call {
+	return &MockStore_GetMedia_Call{Call: _e.mock.On("GetMedia", ctx, id)}
 }
 
-func (_c *MockMetadata_Valid_Call) Run(run func()) *MockMetadata_Valid_Call {
+func (_c *MockStore_GetMedia_Call) Run(run func(ctx context.Context, id string)) *MockStore_GetMedia_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockMetadata_Valid_Call) Return(b bool) *MockMetadata_Valid_Call {
-	_c.Call.Return(b)
+func (_c *MockStore_GetMedia_Call) Return(media *entities.Media, err error) *MockStore_GetMedia_Call {
+	_c.Call.Return(media, err)
 	return _c
 }
 
-func (_c *MockMetadata_Valid_Call) RunAndReturn(run func() bool) *MockMetadata_Valid_Call {
+func (_c *MockStore_GetMedia_Call) RunAndReturn(run func(ctx context.Context, id string) (*entities.Media, error)) *MockStore_GetMedia_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockSource creates a new instance of MockSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockSource(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockSource {
-	mock := &MockSource{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// MockSource is an autogenerated mock type for the Source type
-type MockSource struct {
-	mock.Mock
-}
-
-type MockSource_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *MockSource) EXPECT() *MockSource_Expecter {
-	return &MockSource_Expecter{mock: &_m.Mock}
-}
-
-// Fetch provides a mock function for the type MockSource
-func (_mock *MockSource) Fetch(ctx context.Context, client usecases.Getter) ([]usecases.Metadata, error) {
-	ret := _mock.Called(ctx, client)
+// GetMediaBulk provides a mock function for the type MockStore
+func (_mock *MockStore) GetMediaBulk(ctx context.Context, ids []string) ([]*entities.Media, error) {
+	ret := _mock.Called(ctx, ids)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Fetch")
+		panic("no return value specified for GetMediaBulk")
 	}
 
-	var r0 []usecases.Metadata
+	var r0 []*entities.Media
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, usecases.Getter) ([]usecases.Metadata, error)); ok {
-		return returnFunc(ctx, client)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]*entities.Media, error)); ok {
+		return returnFunc(ctx, ids)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, usecases.Getter) []usecases.Metadata); ok {
-		r0 = returnFunc(ctx, client)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []*entities.Media); ok {
+		r0 = returnFunc(ctx, ids)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]usecases.Metadata)
+			r0 = ret.Get(0).([]*entities.Media)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, usecases.Getter) error); ok {
-		r1 = returnFunc(ctx, client)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, ids)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockSource_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
-type MockSource_Fetch_Call struct {
+// MockStore_GetMediaBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMediaBulk'
+type MockStore_GetMediaBulk_Call struct {
 	*mock.Call
 }
 
-// Fetch is a helper method to define mock.On call
+// GetMediaBulk is a helper method to define mock.On call
 //   - ctx context.Context
-//   - client usecases.Getter
-func (_e *MockSource_Expecter) Fetch(ctx interface{}, client interface{}) *MockSource_Fetch_Call {
-	return &MockSource_Fetch_Call{Call: _e.mock.On("Fetch", ctx, client)}
+//   - ids []string
+func (_e *MockStore_Expecter) GetMediaBulk(ctx interface{}, ids interface{}) *MockStore_GetMediaBulk_Call {
+	return &MockStore_GetMediaBulk_Call{Call: _e.mock.On("GetMediaBulk", ctx, ids)}
 }
 
-func (_c *MockSource_Fetch_Call) Run(run func(ctx context.Context, client usecases.Getter)) *MockSource_Fetch_Call {
+func (_c *MockStore_GetMediaBulk_Call) Run(run func(ctx context.Context, ids []string)) *MockStore_GetMediaBulk_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 usecases.Getter
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(usecases.Getter)
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
@@ -986,172 +1842,255 @@ func (_c *MockSource_Fetch_Call) Run(run func(ctx context.Context, client usecas
 	return _c
 }
 
-func (_c *MockSource_Fetch_Call) Return(metadatas []usecases.Metadata, err error) *MockSource_Fetch_Call {
-	_c.Call.Return(metadatas, err)
+func (_c *MockStore_GetMediaBulk_Call) Return(medias []*entities.Media, err error) *MockStore_GetMediaBulk_Call {
+	_c.Call.Return(medias, err)
 	return _c
 }
 
-func (_c *MockSource_Fetch_Call) RunAndReturn(run func(ctx context.Context, client usecases.Getter) ([]usecases.Metadata, error)) *MockSource_Fetch_Call {
+func (_c *MockStore_GetMediaBulk_Call) RunAndReturn(run func(ctx context.Context, ids []string) ([]*entities.Media, error)) *MockStore_GetMediaBulk_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// String provides a mock function for the type MockSource
-func (_mock *MockSource) String() string {
-	ret := _mock.Called()
+// PutMedia provides a mock function for the type MockStore
+func (_mock *MockStore) PutMedia(ctx context.Context, media *entities.Media) error {
+	ret := _mock.Called(ctx, media)
 
 	if len(ret) == 0 {
-		panic("no return value specified for String")
+		panic("no return value specified for PutMedia")
 	}
 
-	var r0 string
-	if returnFunc, ok := ret.Get(0).(func() string); ok {
-		r0 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entities.Media) error); ok {
+		r0 = returnFunc(ctx, media)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockSource_String_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'String'
-type MockSource_String_Call struct {
+// MockStore_PutMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMedia'
+type MockStore_PutMedia_Call struct {
 	*mock.Call
 }
 
-// String is a helper method to define mock.On call
-func (_e *MockSource_Expecter) String() *MockSource_String_Call {
-	return &MockSource_String_Call{Call: _e.mock.On("String")}
+// PutMedia is a helper method to define mock.On call
+//   - ctx context.Context
+//   - media *entities.Media
+func (_e *MockStore_Expecter) PutMedia(ctx interface{}, media interface{}) *MockStore_PutMedia_Call {
+	return &MockStore_PutMedia_Call{Call: _e.mock.On("PutMedia", ctx, media)}
 }
 
-func (_c *MockSource_String_Call) Run(run func()) *MockSource_String_Call {
+func (_c *MockStore_PutMedia_Call) Run(run func(ctx context.Context, media *entities.Media)) *MockStore_PutMedia_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *entities.Media
+		if args[1] != nil {
+			arg1 = args[1].(*entities.Media)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockSource_String_Call) Return(s string) *MockSource_String_Call {
-	_c.Call.Return(s)
+func (_c *MockStore_PutMedia_Call) Return(err error) *MockStore_PutMedia_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSource_String_Call) RunAndReturn(run func() string) *MockSource_String_Call {
+func (_c *MockStore_PutMedia_Call) RunAndReturn(run func(ctx context.Context, media *entities.Media) error) *MockStore_PutMedia_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockStore creates a new instance of MockStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockStore(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockStore {
-	mock := &MockStore{}
-	mock.Mock.Test(t)
+// PutMediaBulk provides a mock function for the type MockStore
+func (_mock *MockStore) PutMediaBulk(ctx context.Context, medias []*entities.Media) error {
+	ret := _mock.Called(ctx, medias)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	if len(ret) == 0 {
+		panic("no return value specified for PutMediaBulk")
+	}
 
-	return mock
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*entities.Media) error); ok {
+		r0 = returnFunc(ctx, medias)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
 }
 
-// MockStore is an autogenerated mock type for the Store type
-type MockStore struct {
-	mock.Mock
+// MockStore_PutMediaBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMediaBulk'
+type MockStore_PutMediaBulk_Call struct {
+	*mock.Call
 }
 
-type MockStore_Expecter struct {
-	mock *mock.Mock
+// PutMediaBulk is a helper method to define mock.On call
+//   - ctx context.Context
+//   - medias []*entities.Media
+func (_e *MockStore_Expecter) PutMediaBulk(ctx interface{}, medias interface{}) *MockStore_PutMediaBulk_Call {
+	return &MockStore_PutMediaBulk_Call{Call: _e.mock.On("PutMediaBulk", ctx, medias)}
+}
+
+func (_c *MockStore_PutMediaBulk_Call) Run(run func(ctx context.Context, medias []*entities.Media)) *MockStore_PutMediaBulk_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*entities.Media
+		if args[1] != nil {
+			arg1 = args[1].([]*entities.Media)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStore_PutMediaBulk_Call) Return(err error) *MockStore_PutMediaBulk_Call {
+	_c.Call.Return(err)
+	return _c
 }
 
-func (_m *MockStore) EXPECT() *MockStore_Expecter {
-	return &MockStore_Expecter{mock: &_m.Mock}
+func (_c *MockStore_PutMediaBulk_Call) RunAndReturn(run func(ctx context.Context, medias []*entities.Media) error) *MockStore_PutMediaBulk_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// Close provides a mock function for the type MockStore
-func (_mock *MockStore) Close() error {
-	ret := _mock.Called()
+// ReplaceMediaBulk provides a mock function for the type MockStore
+func (_mock *MockStore) ReplaceMediaBulk(ctx context.Context, medias []*entities.Media) error {
+	ret := _mock.Called(ctx, medias)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Close")
+		panic("no return value specified for ReplaceMediaBulk")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*entities.Media) error); ok {
+		r0 = returnFunc(ctx, medias)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStore_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
-type MockStore_Close_Call struct {
+// MockStore_ReplaceMediaBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceMediaBulk'
+type MockStore_ReplaceMediaBulk_Call struct {
 	*mock.Call
 }
 
-// Close is a helper method to define mock.On call
-func (_e *MockStore_Expecter) Close() *MockStore_Close_Call {
-	return &MockStore_Close_Call{Call: _e.mock.On("Close")}
+// ReplaceMediaBulk is a helper method to define mock.On call
+//   - ctx context.Context
+//   - medias []*entities.Media
+func (_e *MockStore_Expecter) ReplaceMediaBulk(ctx interface{}, medias interface{}) *MockStore_ReplaceMediaBulk_Call {
+	return &MockStore_ReplaceMediaBulk_Call{Call: _e.mock.On("ReplaceMediaBulk", ctx, medias)}
 }
 
-func (_c *MockStore_Close_Call) Run(run func()) *MockStore_Close_Call {
+func (_c *MockStore_ReplaceMediaBulk_Call) Run(run func(ctx context.Context, medias []*entities.Media)) *MockStore_ReplaceMediaBulk_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*entities.Media
+		if args[1] != nil {
+			arg1 = args[1].([]*entities.Media)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockStore_Close_Call) Return(err error) *MockStore_Close_Call {
+func (_c *MockStore_ReplaceMediaBulk_Call) Return(err error) *MockStore_ReplaceMediaBulk_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStore_Close_Call) RunAndReturn(run func() error) *MockStore_Close_Call {
+func (_c *MockStore_ReplaceMediaBulk_Call) RunAndReturn(run func(ctx context.Context, medias []*entities.Media) error) *MockStore_ReplaceMediaBulk_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetMedia provides a mock function for the type MockStore
-func (_mock *MockStore) GetMedia(ctx context.Context, id string) (*entities.Media, error) {
-	ret := _mock.Called(ctx, id)
+// NewMockTokenStore creates a new instance of MockTokenStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenStore {
+	mock := &MockTokenStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTokenStore is an autogenerated mock type for the TokenStore type
+type MockTokenStore struct {
+	mock.Mock
+}
+
+type MockTokenStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTokenStore) EXPECT() *MockTokenStore_Expecter {
+	return &MockTokenStore_Expecter{mock: &_m.Mock}
+}
+
+// GetToken provides a mock function for the type MockTokenStore
+func (_mock *MockTokenStore) GetToken(ctx context.Context, userID string) (*entities.Token, error) {
+	ret := _mock.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMedia")
+		panic("no return value specified for GetToken")
 	}
 
-	var r0 *entities.Media
+	var r0 *entities.Token
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.Media, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.Token, error)); ok {
+		return returnFunc(ctx, userID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.Media); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.Token); ok {
+		r0 = returnFunc(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entities.Media)
+			r0 = ret.Get(0).(*entities.Token)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
+		r1 = returnFunc(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStore_GetMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMedia'
-type MockStore_GetMedia_Call struct {
+// MockTokenStore_GetToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetToken'
+type MockTokenStore_GetToken_Call struct {
 	*mock.Call
 }
 
-// GetMedia is a helper method to define mock.On call
+// GetToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-func (_e *MockStore_Expecter) GetMedia(ctx interface{}, id interface{}) *MockStore_GetMedia_Call {
-	return &MockStore_GetMedia_Call{Call: _e.mock.On("GetMedia", ctx, id)}
+//   - userID string
+func (_e *MockTokenStore_Expecter) GetToken(ctx interface{}, userID interface{}) *MockTokenStore_GetToken_Call {
+	return &MockTokenStore_GetToken_Call{Call: _e.mock.On("GetToken", ctx, userID)}
 }
 
-func (_c *MockStore_GetMedia_Call) Run(run func(ctx context.Context, id string)) *MockStore_GetMedia_Call {
+func (_c *MockTokenStore_GetToken_Call) Run(run func(ctx context.Context, userID string)) *MockTokenStore_GetToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1169,65 +2108,65 @@ func (_c *MockStore_GetMedia_Call) Run(run func(ctx context.Context, id string))
 	return _c
 }
 
-func (_c *MockStore_GetMedia_Call) Return(media *entities.Media, err error) *MockStore_GetMedia_Call {
-	_c.Call.Return(media, err)
+func (_c *MockTokenStore_GetToken_Call) Return(token *entities.Token, err error) *MockTokenStore_GetToken_Call {
+	_c.Call.Return(token, err)
 	return _c
 }
 
-func (_c *MockStore_GetMedia_Call) RunAndReturn(run func(ctx context.Context, id string) (*entities.Media, error)) *MockStore_GetMedia_Call {
+func (_c *MockTokenStore_GetToken_Call) RunAndReturn(run func(ctx context.Context, userID string) (*entities.Token, error)) *MockTokenStore_GetToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetMediaBulk provides a mock function for the type MockStore
-func (_mock *MockStore) GetMediaBulk(ctx context.Context, ids []string) ([]*entities.Media, error) {
-	ret := _mock.Called(ctx, ids)
+// GetTokenByName provides a mock function for the type MockTokenStore
+func (_mock *MockTokenStore) GetTokenByName(ctx context.Context, name string) (*entities.Token, error) {
+	ret := _mock.Called(ctx, name)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMediaBulk")
+		panic("no return value specified for GetTokenByName")
 	}
 
-	var r0 []*entities.Media
+	var r0 *entities.Token
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]*entities.Media, error)); ok {
-		return returnFunc(ctx, ids)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*entities.Token, error)); ok {
+		return returnFunc(ctx, name)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []*entities.Media); ok {
-		r0 = returnFunc(ctx, ids)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *entities.Token); ok {
+		r0 = returnFunc(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*entities.Media)
+			r0 = ret.Get(0).(*entities.Token)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = returnFunc(ctx, ids)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockStore_GetMediaBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMediaBulk'
-type MockStore_GetMediaBulk_Call struct {
+// MockTokenStore_GetTokenByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTokenByName'
+type MockTokenStore_GetTokenByName_Call struct {
 	*mock.Call
 }
 
-// GetMediaBulk is a helper method to define mock.On call
+// GetTokenByName is a helper method to define mock.On call
 //   - ctx context.Context
-//   - ids []string
-func (_e *MockStore_Expecter) GetMediaBulk(ctx interface{}, ids interface{}) *MockStore_GetMediaBulk_Call {
-	return &MockStore_GetMediaBulk_Call{Call: _e.mock.On("GetMediaBulk", ctx, ids)}
+//   - name string
+func (_e *MockTokenStore_Expecter) GetTokenByName(ctx interface{}, name interface{}) *MockTokenStore_GetTokenByName_Call {
+	return &MockTokenStore_GetTokenByName_Call{Call: _e.mock.On("GetTokenByName", ctx, name)}
 }
 
-func (_c *MockStore_GetMediaBulk_Call) Run(run func(ctx context.Context, ids []string)) *MockStore_GetMediaBulk_Call {
+func (_c *MockTokenStore_GetTokenByName_Call) Run(run func(ctx context.Context, name string)) *MockTokenStore_GetTokenByName_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []string
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].([]string)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -1237,54 +2176,54 @@ func (_c *MockStore_GetMediaBulk_Call) Run(run func(ctx context.Context, ids []s
 	return _c
 }
 
-func (_c *MockStore_GetMediaBulk_Call) Return(medias []*entities.Media, err error) *MockStore_GetMediaBulk_Call {
-	_c.Call.Return(medias, err)
+func (_c *MockTokenStore_GetTokenByName_Call) Return(token *entities.Token, err error) *MockTokenStore_GetTokenByName_Call {
+	_c.Call.Return(token, err)
 	return _c
 }
 
-func (_c *MockStore_GetMediaBulk_Call) RunAndReturn(run func(ctx context.Context, ids []string) ([]*entities.Media, error)) *MockStore_GetMediaBulk_Call {
+func (_c *MockTokenStore_GetTokenByName_Call) RunAndReturn(run func(ctx context.Context, name string) (*entities.Token, error)) *MockTokenStore_GetTokenByName_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PutMedia provides a mock function for the type MockStore
-func (_mock *MockStore) PutMedia(ctx context.Context, media *entities.Media) error {
-	ret := _mock.Called(ctx, media)
+// PutToken provides a mock function for the type MockTokenStore
+func (_mock *MockTokenStore) PutToken(ctx context.Context, token *entities.Token) error {
+	ret := _mock.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PutMedia")
+		panic("no return value specified for PutToken")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *entities.Media) error); ok {
-		r0 = returnFunc(ctx, media)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entities.Token) error); ok {
+		r0 = returnFunc(ctx, token)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStore_PutMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMedia'
-type MockStore_PutMedia_Call struct {
+// MockTokenStore_PutToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutToken'
+type MockTokenStore_PutToken_Call struct {
 	*mock.Call
 }
 
-// PutMedia is a helper method to define mock.On call
+// PutToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - media *entities.Media
-func (_e *MockStore_Expecter) PutMedia(ctx interface{}, media interface{}) *MockStore_PutMedia_Call {
-	return &MockStore_PutMedia_Call{Call: _e.mock.On("PutMedia", ctx, media)}
+//   - token *entities.Token
+func (_e *MockTokenStore_Expecter) PutToken(ctx interface{}, token interface{}) *MockTokenStore_PutToken_Call {
+	return &MockTokenStore_PutToken_Call{Call: _e.mock.On("PutToken", ctx, token)}
 }
 
-func (_c *MockStore_PutMedia_Call) Run(run func(ctx context.Context, media *entities.Media)) *MockStore_PutMedia_Call {
+func (_c *MockTokenStore_PutToken_Call) Run(run func(ctx context.Context, token *entities.Token)) *MockTokenStore_PutToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *entities.Media
+		var arg1 *entities.Token
 		if args[1] != nil {
-			arg1 = args[1].(*entities.Media)
+			arg1 = args[1].(*entities.Token)
 		}
 		run(
 			arg0,
@@ -1294,69 +2233,99 @@ func (_c *MockStore_PutMedia_Call) Run(run func(ctx context.Context, media *enti
 	return _c
 }
 
-func (_c *MockStore_PutMedia_Call) Return(err error) *MockStore_PutMedia_Call {
+func (_c *MockTokenStore_PutToken_Call) Return(err error) *MockTokenStore_PutToken_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStore_PutMedia_Call) RunAndReturn(run func(ctx context.Context, media *entities.Media) error) *MockStore_PutMedia_Call {
+func (_c *MockTokenStore_PutToken_Call) RunAndReturn(run func(ctx context.Context, token *entities.Token) error) *MockTokenStore_PutToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PutMediaBulk provides a mock function for the type MockStore
-func (_mock *MockStore) PutMediaBulk(ctx context.Context, medias []*entities.Media) error {
-	ret := _mock.Called(ctx, medias)
+// NewMockOverrides creates a new instance of MockOverrides. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOverrides(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOverrides {
+	mock := &MockOverrides{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockOverrides is an autogenerated mock type for the Overrides type
+type MockOverrides struct {
+	mock.Mock
+}
+
+type MockOverrides_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOverrides) EXPECT() *MockOverrides_Expecter {
+	return &MockOverrides_Expecter{mock: &_m.Mock}
+}
+
+// GetOverrides provides a mock function for the type MockOverrides
+func (_mock *MockOverrides) GetOverrides(ctx context.Context) (entities.MappingOverrides, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PutMediaBulk")
+		panic("no return value specified for GetOverrides")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []*entities.Media) error); ok {
-		r0 = returnFunc(ctx, medias)
+	var r0 entities.MappingOverrides
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (entities.MappingOverrides, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) entities.MappingOverrides); ok {
+		r0 = returnFunc(ctx)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(entities.MappingOverrides)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStore_PutMediaBulk_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutMediaBulk'
-type MockStore_PutMediaBulk_Call struct {
+// MockOverrides_GetOverrides_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOverrides'
+type MockOverrides_GetOverrides_Call struct {
 	*mock.Call
 }
 
-// PutMediaBulk is a helper method to define mock.On call
+// GetOverrides is a helper method to define mock.On call
 //   - ctx context.Context
-//   - medias []*entities.Media
-func (_e *MockStore_Expecter) PutMediaBulk(ctx interface{}, medias interface{}) *MockStore_PutMediaBulk_Call {
-	return &MockStore_PutMediaBulk_Call{Call: _e.mock.On("PutMediaBulk", ctx, medias)}
+func (_e *MockOverrides_Expecter) GetOverrides(ctx interface{}) *MockOverrides_GetOverrides_Call {
+	return &MockOverrides_GetOverrides_Call{Call: _e.mock.On("GetOverrides", ctx)}
 }
 
-func (_c *MockStore_PutMediaBulk_Call) Run(run func(ctx context.Context, medias []*entities.Media)) *MockStore_PutMediaBulk_Call {
+func (_c *MockOverrides_GetOverrides_Call) Run(run func(ctx context.Context)) *MockOverrides_GetOverrides_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []*entities.Media
-		if args[1] != nil {
-			arg1 = args[1].([]*entities.Media)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStore_PutMediaBulk_Call) Return(err error) *MockStore_PutMediaBulk_Call {
-	_c.Call.Return(err)
+func (_c *MockOverrides_GetOverrides_Call) Return(mappingOverrides entities.MappingOverrides, err error) *MockOverrides_GetOverrides_Call {
+	_c.Call.Return(mappingOverrides, err)
 	return _c
 }
 
-func (_c *MockStore_PutMediaBulk_Call) RunAndReturn(run func(ctx context.Context, medias []*entities.Media) error) *MockStore_PutMediaBulk_Call {
+func (_c *MockOverrides_GetOverrides_Call) RunAndReturn(run func(ctx context.Context) (entities.MappingOverrides, error)) *MockOverrides_GetOverrides_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1433,8 +2402,14 @@ func (_c *MockTracker_Close_Call) RunAndReturn(run func() error) *MockTracker_Cl
 }
 
 // GetMediaListIDs provides a mock function for the type MockTracker
-func (_mock *MockTracker) GetMediaListIDs(ctx context.Context, userID string) ([]entities.SourceID, error) {
-	ret := _mock.Called(ctx, userID)
+func (_mock *MockTracker) GetMediaListIDs(ctx context.Context, userID string, statuses ...string) ([]entities.SourceID, error) {
+	var tmpRet mock.Arguments
+	if len(statuses) > 0 {
+		tmpRet = _mock.Called(ctx, userID, statuses)
+	} else {
+		tmpRet = _mock.Called(ctx, userID)
+	}
+	ret := tmpRet
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMediaListIDs")
@@ -1442,18 +2417,18 @@ func (_mock *MockTracker) GetMediaListIDs(ctx context.Context, userID string) ([
 
 	var r0 []entities.SourceID
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]entities.SourceID, error)); ok {
-		return returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) ([]entities.SourceID, error)); ok {
+		return returnFunc(ctx, userID, statuses...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []entities.SourceID); ok {
-		r0 = returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...string) []entities.SourceID); ok {
+		r0 = returnFunc(ctx, userID, statuses...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entities.SourceID)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = returnFunc(ctx, userID, statuses...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1468,11 +2443,13 @@ type MockTracker_GetMediaListIDs_Call struct {
 // GetMediaListIDs is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID string
-func (_e *MockTracker_Expecter) GetMediaListIDs(ctx interface{}, userID interface{}) *MockTracker_GetMediaListIDs_Call {
-	return &MockTracker_GetMediaListIDs_Call{Call: _e.mock.On("GetMediaListIDs", ctx, userID)}
+//   - statuses ...string
+func (_e *MockTracker_Expecter) GetMediaListIDs(ctx interface{}, userID interface{}, statuses ...interface{}) *MockTracker_GetMediaListIDs_Call {
+	return &MockTracker_GetMediaListIDs_Call{Call: _e.mock.On("GetMediaListIDs",
+		append([]interface{}{ctx, userID}, statuses...)...)}
 }
 
-func (_c *MockTracker_GetMediaListIDs_Call) Run(run func(ctx context.Context, userID string)) *MockTracker_GetMediaListIDs_Call {
+func (_c *MockTracker_GetMediaListIDs_Call) Run(run func(ctx context.Context, userID string, statuses ...string)) *MockTracker_GetMediaListIDs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1482,9 +2459,16 @@ func (_c *MockTracker_GetMediaListIDs_Call) Run(run func(ctx context.Context, us
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 []string
+		var variadicArgs []string
+		if len(args) > 2 {
+			variadicArgs = args[2].([]string)
+		}
+		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
+			arg2...,
 		)
 	})
 	return _c
@@ -1495,7 +2479,7 @@ func (_c *MockTracker_GetMediaListIDs_Call) Return(vs []entities.SourceID, err e
 	return _c
 }
 
-func (_c *MockTracker_GetMediaListIDs_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]entities.SourceID, error)) *MockTracker_GetMediaListIDs_Call {
+func (_c *MockTracker_GetMediaListIDs_Call) RunAndReturn(run func(ctx context.Context, userID string, statuses ...string) ([]entities.SourceID, error)) *MockTracker_GetMediaListIDs_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1565,3 +2549,93 @@ func (_c *MockTracker_GetUserID_Call) RunAndReturn(run func(ctx context.Context,
 	_c.Call.Return(run)
 	return _c
 }
+
+// NewMockUserIDCache creates a new instance of MockUserIDCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserIDCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserIDCache {
+	mock := &MockUserIDCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserIDCache is an autogenerated mock type for the UserIDCache type
+type MockUserIDCache struct {
+	mock.Mock
+}
+
+type MockUserIDCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserIDCache) EXPECT() *MockUserIDCache_Expecter {
+	return &MockUserIDCache_Expecter{mock: &_m.Mock}
+}
+
+// PutUserID provides a mock function for the type MockUserIDCache
+func (_mock *MockUserIDCache) PutUserID(ctx context.Context, name string, userID string) error {
+	ret := _mock.Called(ctx, name, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutUserID")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, name, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserIDCache_PutUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutUserID'
+type MockUserIDCache_PutUserID_Call struct {
+	*mock.Call
+}
+
+// PutUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - userID string
+func (_e *MockUserIDCache_Expecter) PutUserID(ctx interface{}, name interface{}, userID interface{}) *MockUserIDCache_PutUserID_Call {
+	return &MockUserIDCache_PutUserID_Call{Call: _e.mock.On("PutUserID", ctx, name, userID)}
+}
+
+func (_c *MockUserIDCache_PutUserID_Call) Run(run func(ctx context.Context, name string, userID string)) *MockUserIDCache_PutUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserIDCache_PutUserID_Call) Return(err error) *MockUserIDCache_PutUserID_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserIDCache_PutUserID_Call) RunAndReturn(run func(ctx context.Context, name string, userID string) error) *MockUserIDCache_PutUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
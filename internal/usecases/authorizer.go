@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+)
+
+// Authorizer grants access to the data of tracker users through the OAuth2
+// authorization code flow.
+//
+// AuthCodeURL returns the URL users consent at, which then redirects back with
+// state and a code. Authorize exchanges such code for the token of the user.
+//
+//mockery:generate: true
+type Authorizer interface {
+	AuthCodeURL(state string) string
+	Authorize(ctx context.Context, code string) (*entities.Token, error)
+}
@@ -16,6 +16,17 @@ type Cache interface {
 	SetString(ctx context.Context, key, value string, options ...CacheOption) error
 }
 
+// ExpiringCache is a [Cache] that reports how long its entries have left
+//
+//mockery:generate: true
+type ExpiringCache interface {
+	Cache
+
+	// GetStringTTL retrieves the value for key along with the time left until
+	// it expires, which is zero for entries without expiration.
+	GetStringTTL(ctx context.Context, key string) (string, time.Duration, error)
+}
+
 // CacheOptions contains optional parameters to use when setting cache entries.
 type CacheOptions struct {
 	TTL time.Duration
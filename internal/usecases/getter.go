@@ -47,19 +47,88 @@ func HTTPGetter(doer Doer) GetterFn {
 		}
 		defer res.Body.Close()
 
-		err = ErrorFromHTTPStatus(res.StatusCode)
+		return readResponse(res)
+	})
+}
+
+// ConditionalHTTPGetter converts a [Doer] to a [Getter] that revalidates data
+// during refreshes. It stores the ETag and Last-Modified validators of each URI
+// in cache under namespace, and fails with [ErrNotModified] if the server
+// reports the data did not change since. It behaves as [HTTPGetter] outside
+// refreshes.
+func ConditionalHTTPGetter(doer Doer, cache Cache, namespace string) GetterFn {
+	return GetterFn(func(ctx context.Context, uri string) ([]byte, error) {
+		run := revalidationFrom(ctx)
+		if run == nil {
+			return HTTPGetter(doer).Get(ctx, uri)
+		}
+
+		key := fmt.Sprintf(cacheKeyValidators, namespace, run.scope, uri)
+
+		req, err := newConditionalRequest(ctx, uri, run, cache, key)
 		if err != nil {
-			return nil, fmt.Errorf("%w: %s", err, "failed to execute HTTP request")
+			return nil, err
+		}
+
+		res, err := doer.Do(req)
+		if err != nil {
+			return nil, errors.Join(ErrStatusUnknown, err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode == http.StatusNotModified {
+			run.notModify()
+
+			return nil, ErrNotModified
 		}
 
-		if res.StatusCode != http.StatusOK {
-			return nil, ErrStatusUnknown
+		data, err := readResponse(res)
+		if err != nil {
+			return nil, err
 		}
 
-		return io.ReadAll(res.Body)
+		run.modify(newValidators(res.Header).store(cache, key))
+
+		return data, nil
 	})
 }
 
+// newConditionalRequest creates a GET request with the validators stored at
+// key, unless the revalidation is unconditional.
+func newConditionalRequest(
+	ctx context.Context,
+	uri string,
+	run *revalidation,
+	cache Cache,
+	key string,
+) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, errors.Join(ErrStatusInvalidArgument, err)
+	}
+
+	if !run.unconditional {
+		getValidators(ctx, cache, key).setHeaders(req.Header)
+	}
+
+	return req, nil
+}
+
+// readResponse reads the body of successful responses, and converts the status
+// of any others to an error.
+func readResponse(res *http.Response) ([]byte, error) {
+	err := ErrorFromHTTPStatus(res.StatusCode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, "failed to execute HTTP request")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ErrStatusUnknown
+	}
+
+	return io.ReadAll(res.Body)
+}
+
 // FSGetter wraps a filesystem handler as a [Getter].
 func FSGetter(root fs.FS) GetterFn {
 	return GetterFn(func(_ context.Context, uri string) ([]byte, error) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"sync"
 
@@ -14,19 +15,48 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/entities"
 )
 
-var _ MediaLister = (*MediaList)(nil)
+var (
+	_ MediaLister      = (*MediaList)(nil)
+	_ ConflictReporter = (*MediaList)(nil)
+)
 
 // MediaList handles both a tracker to fetch user data and a mapper that can
-// transform the media IDs from the tracker to another service
+// transform the media IDs from the tracker to another service.
+//
+// Shows and movies map to distinct services, so each one has its own source
+// and store. Movies are optional: Generate works without MovieSource and
+// MovieStore.
+//
+// Overrides optionally correct the show mappings. Those apply on top of the
+// Store on lookups only, which keeps the Source data as is, so changes take
+// effect without waiting for the next refresh, and removing them restores the
+// Source mappings.
+//
+// Prune makes refreshes replace all stored mappings, which drops those removed
+// upstream. Refreshes of sources that fetch only partially, e.g. merged ones of
+// which some fail, store their mappings without pruning instead, as those lack
+// the mappings of the failed ones.
 type MediaList struct {
-	Tracker Tracker
-	Source  Source
-	Store   Store
+	Tracker     Tracker
+	Source      Source
+	Store       Store
+	MovieSource Source
+	MovieStore  Store
+	Overrides   Overrides
+	Prune       bool
 }
 
 // Generate fetches the user media list from the Tracker and transform the IDs
-// found to the target service through the Mapper
-func (lister *MediaList) Generate(ctx context.Context, name string) (entities.CustomList, error) {
+// found to the target service through the Mapper. The optional statuses
+// filter which tracker entries to include.
+//
+// Medias that map to the same show result in a single entry, which lists the
+// seasons to monitor if all of those mappings have one.
+func (lister *MediaList) Generate(
+	ctx context.Context,
+	name string,
+	statuses ...string,
+) (entities.CustomList, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
@@ -34,41 +64,52 @@ func (lister *MediaList) Generate(ctx context.Context, name string) (entities.Cu
 		return nil, ErrStatusFailedPrecondition
 	}
 
-	log := telemetry.Logr(ctx).WithValues("username", name)
-
-	log.Info("retrieving user ID")
-
-	userID, err := lister.GetUserID(ctx, name)
+	medias, err := lister.mapUserMedia(ctx, lister.mapShowIDs, name, statuses)
 	if err != nil {
-		return nil, span.Assert(fmt.Errorf("failed to get user ID: %w", err))
+		return nil, span.Assert(err)
 	}
 
-	log.Info("retrieving media list IDs", "userID", userID)
+	customList, err := newCustomList(medias)
 
-	sourceIDs, err := lister.Tracker.GetMediaListIDs(ctx, userID)
-	if err != nil {
-		return nil, span.Assert(fmt.Errorf("failed to get media list IDs: %w", err))
+	return customList, span.Assert(err)
+}
+
+// GenerateMovies fetches the user media list from the Tracker and transform the
+// IDs found to movies through the MovieStore. The optional statuses filter which
+// tracker entries to include.
+func (lister *MediaList) GenerateMovies(
+	ctx context.Context,
+	name string,
+	statuses ...string,
+) (entities.MovieList, error) {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	if lister.Tracker == nil {
+		return nil, ErrStatusFailedPrecondition
+	}
+
+	if lister.MovieStore == nil {
+		return nil, span.Assert(fmt.Errorf("%w: movies are not supported", ErrStatusUnimplemented))
 	}
 
-	targetIDs, err := lister.MapIDs(ctx, sourceIDs)
+	medias, err := lister.mapUserMedia(ctx, lister.mapMovieIDs, name, statuses)
 	if err != nil {
-		return nil, span.Assert(fmt.Errorf("failed to get mapped IDs: %w", err))
+		return nil, span.Assert(err)
 	}
 
-	customList := make(entities.CustomList, 0, len(targetIDs))
+	movieList := make(entities.MovieList, 0, len(medias))
 
-	for _, entry := range targetIDs {
-		tvdbID, err := strconv.ParseUint(entry, 10, 0)
+	for _, media := range medias {
+		movie, err := entities.ParseMovieEntry(media.TargetID)
 		if err != nil {
-			return nil, span.Assert(fmt.Errorf("failed to parse TVDB ID: %w", err))
+			return nil, span.Assert(err)
 		}
 
-		customList = append(customList, entities.CustomEntry{
-			TvdbID: tvdbID,
-		})
+		movieList = append(movieList, movie)
 	}
 
-	return customList, span.Assert(nil)
+	return movieList, span.Assert(nil)
 }
 
 // GetUserID searches the Tracker for the user ID by their name/handle
@@ -89,6 +130,7 @@ func (lister *MediaList) GetUserID(ctx context.Context, name string) (string, er
 func (lister *MediaList) Close() error {
 	closers := [...]io.Closer{
 		lister.Store,
+		lister.MovieStore,
 		lister.Tracker,
 	}
 	outErr := make(chan error)
@@ -124,7 +166,8 @@ func (lister *MediaList) Close() error {
 	return multierror.Append(nil, errs...).ErrorOrNil()
 }
 
-// Refresh requests the Mapper to update its mapping definitions
+// Refresh requests the Mapper to update its mapping definitions. It refreshes
+// the movie mappings as well if both MovieSource and MovieStore are set.
 func (lister *MediaList) Refresh(ctx context.Context, client Getter) error {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
@@ -133,11 +176,201 @@ func (lister *MediaList) Refresh(ctx context.Context, client Getter) error {
 		return ErrStatusFailedPrecondition
 	}
 
-	data, err := lister.Source.Fetch(ctx, client)
+	err := lister.refresh(ctx, client, lister.Source, lister.Store)
+	if err != nil {
+		return span.Assert(err)
+	}
+
+	if lister.MovieSource == nil || lister.MovieStore == nil {
+		return span.Assert(nil)
+	}
+
+	return span.Assert(lister.refresh(ctx, client, lister.MovieSource, lister.MovieStore))
+}
+
+// Conflicts returns the mapping conflicts of both Source and MovieSource, if
+// those report any.
+func (lister *MediaList) Conflicts() []entities.MappingConflict {
+	conflicts := []entities.MappingConflict{}
+
+	for _, source := range [...]Source{lister.Source, lister.MovieSource} {
+		reporter, ok := source.(ConflictReporter)
+		if !ok {
+			continue
+		}
+
+		conflicts = append(conflicts, reporter.Conflicts()...)
+	}
+
+	return conflicts
+}
+
+// MapIDs converts IDs between a source tracker and a target reference. Returns
+// all IDs that were found, or an empty slice if no matches were found.
+func (lister *MediaList) MapIDs(
+	ctx context.Context,
+	ids []entities.SourceID,
+) ([]entities.TargetID, error) {
+	medias, err := lister.mapShowIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	targetIDs := make([]entities.TargetID, 0, len(medias))
+	for _, media := range medias {
+		targetIDs = append(targetIDs, media.TargetID)
+	}
+
+	return targetIDs, nil
+}
+
+// mapUserMedia fetches the media list of an user from the Tracker and maps the
+// IDs found through mapper.
+func (lister *MediaList) mapUserMedia(
+	ctx context.Context,
+	mapper func(ctx context.Context, ids []entities.SourceID) ([]*entities.Media, error),
+	name string,
+	statuses []string,
+) ([]*entities.Media, error) {
+	log := telemetry.Logr(ctx).WithValues("username", name)
+
+	log.Info("retrieving user ID")
+
+	userID, err := lister.GetUserID(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ID: %w", err)
+	}
+
+	log.Info("retrieving media list IDs", "userID", userID, "statuses", statuses)
+
+	sourceIDs, err := lister.Tracker.GetMediaListIDs(ctx, userID, statuses...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media list IDs: %w", err)
+	}
+
+	medias, err := mapper(ctx, sourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mapped IDs: %w", err)
+	}
+
+	return medias, nil
+}
+
+// mapShowIDs maps IDs through the Store with the Overrides on top of it, in
+// the same order. Excluded IDs never map, and overridden ones skip the Store.
+func (lister *MediaList) mapShowIDs(
+	ctx context.Context,
+	ids []entities.SourceID,
+) ([]*entities.Media, error) {
+	overrides, err := lister.getOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupIDs := slices.DeleteFunc(slices.Clone(ids), func(id entities.SourceID) bool {
+		return overrides.Excluded(id) || overrides.Media(id) != nil
+	})
+
+	stored, err := mapIDs(ctx, lister.Store, lookupIDs)
+	if err != nil || len(lookupIDs) == len(ids) {
+		return stored, err
+	}
+
+	storedMedias := make(map[entities.SourceID]*entities.Media, len(stored))
+	for _, media := range stored {
+		storedMedias[media.SourceID] = media
+	}
+
+	medias := make([]*entities.Media, 0, len(ids))
+
+	for _, id := range ids {
+		media := overrides.Media(id)
+		if media == nil {
+			media = storedMedias[id]
+		}
+
+		if media != nil {
+			medias = append(medias, media)
+		}
+	}
+
+	return medias, nil
+}
+
+// mapMovieIDs maps IDs through the MovieStore.
+func (lister *MediaList) mapMovieIDs(
+	ctx context.Context,
+	ids []entities.SourceID,
+) ([]*entities.Media, error) {
+	return mapIDs(ctx, lister.MovieStore, ids)
+}
+
+// getOverrides retrieves the current Overrides, or none if it is not set.
+func (lister *MediaList) getOverrides(ctx context.Context) (entities.MappingOverrides, error) {
+	if lister.Overrides == nil {
+		return entities.MappingOverrides{Mappings: nil, Exclude: nil}, nil
+	}
+
+	overrides, err := lister.Overrides.GetOverrides(ctx)
 	if err != nil {
+		return overrides, fmt.Errorf("failed to get overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// refresh fetches the metadata from source and stores all valid entries. It
+// replaces the stored entries if pruning, unless the fetch is partial.
+func (lister *MediaList) refresh(
+	ctx context.Context,
+	client Getter,
+	source Source,
+	store Store,
+) error {
+	ctx, span := telemetry.Start(ctx)
+	defer span.End()
+
+	log := telemetry.Logr(ctx).WithValues("source", source.String())
+
+	data, run, err := fetchRevalidated(ctx, client, source)
+	if errors.Is(err, ErrNotModified) {
+		log.Info("source not modified, skipping refresh")
+
+		return span.Assert(nil)
+	}
+
+	partial := errors.Is(err, ErrPartialFetch)
+	if err != nil && !partial {
 		return span.Assert(fmt.Errorf("failed to refresh anilist mapper: %w", err))
 	}
 
+	if partial {
+		log.Error(err, "source fetched partially, keeping stale mappings")
+	}
+
+	medias := newMedias(data)
+
+	if lister.Prune && !partial {
+		err = store.ReplaceMediaBulk(ctx, medias)
+	} else {
+		err = store.PutMediaBulk(ctx, medias)
+	}
+
+	if err != nil {
+		return span.Assert(fmt.Errorf("failed to store media during refresh: %w", err))
+	}
+
+	// validators only save downloads, so the next refresh retries in full
+	err = run.commit(ctx)
+	if err != nil {
+		log.Error(err, "failed to commit validators")
+	}
+
+	return span.Assert(nil)
+}
+
+// newMedias converts the valid metadata entries to medias.
+func newMedias(data []Metadata) []*entities.Media {
 	medias := make([]*entities.Media, 0, len(data))
 
 	for _, entry := range data {
@@ -145,42 +378,110 @@ func (lister *MediaList) Refresh(ctx context.Context, client Getter) error {
 			continue
 		}
 
+		var targetSeason *uint64
+
+		if seasonEntry, ok := entry.(SeasonMetadata); ok {
+			targetSeason = seasonEntry.GetTargetSeason()
+		}
+
 		medias = append(medias, &entities.Media{
-			SourceID: entry.GetSourceID(),
-			TargetID: entry.GetTargetID(),
+			SourceID:     entry.GetSourceID(),
+			TargetID:     entry.GetTargetID(),
+			TargetSeason: targetSeason,
 		})
 	}
 
-	err = lister.Store.PutMediaBulk(ctx, medias)
-	if err != nil {
-		return span.Assert(fmt.Errorf("failed to store media during refresh: %w", err))
+	return medias
+}
+
+// fetchRevalidated fetches the metadata from source with conditional requests.
+// It fails with [ErrNotModified] if none of the data changed, and fetches it
+// again unconditionally if only part of it did, as sources need all of it.
+func fetchRevalidated(
+	ctx context.Context,
+	client Getter,
+	source Source,
+) ([]Metadata, *revalidation, error) {
+	runCtx, run := withRevalidation(ctx, source.String(), false)
+
+	data, err := source.Fetch(runCtx, client)
+
+	switch {
+	case run.unchanged():
+		return nil, run, ErrNotModified
+	case run.partial():
+		runCtx, run = withRevalidation(ctx, source.String(), true)
+
+		data, err = source.Fetch(runCtx, client)
 	}
 
-	return span.Assert(nil)
+	//nolint:wrapcheck // callers wrap it
+	return data, run, err
 }
 
-// MapIDs converts IDs between a source tracker and a target reference. Returns
-// all IDs that were found, or an empty slice if no matches were found.
-func (lister *MediaList) MapIDs(
+// mapIDs converts IDs between a source tracker and a target reference through
+// store. Returns all medias that were found, or an empty slice if no matches
+// were found.
+func mapIDs(
 	ctx context.Context,
+	store Store,
 	ids []entities.SourceID,
-) ([]entities.TargetID, error) {
+) ([]*entities.Media, error) {
 	ctx, span := telemetry.Start(ctx)
 	defer span.End()
 
-	if lister.Store == nil {
+	if store == nil {
 		return nil, ErrStatusFailedPrecondition
 	}
 
-	records, err := lister.Store.GetMediaBulk(ctx, ids)
+	records, err := store.GetMediaBulk(ctx, ids)
 	if err != nil && !errors.Is(err, ErrStatusNotFound) {
 		return nil, span.Assert(fmt.Errorf("failed to map IDs: %w", err))
 	}
 
-	targetIDs := make([]string, 0, len(records))
-	for _, record := range records {
-		targetIDs = append(targetIDs, record.TargetID)
+	medias := make([]*entities.Media, 0, len(records))
+	medias = append(medias, records...)
+
+	return medias, span.Assert(nil)
+}
+
+// newCustomList groups medias by their TVDB show, in order. Entries list the
+// seasons of their medias, or none if any of those matches all seasons.
+func newCustomList(medias []*entities.Media) (entities.CustomList, error) {
+	customList := make(entities.CustomList, 0, len(medias))
+	// indexes tracks the custom list position of each show
+	indexes := make(map[uint64]int, len(medias))
+	// allSeasons tracks shows with a media that matches all of its seasons
+	allSeasons := make(map[uint64]bool, len(medias))
+
+	for _, media := range medias {
+		tvdbID, err := strconv.ParseUint(media.TargetID, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TVDB ID: %w", err)
+		}
+
+		index, ok := indexes[tvdbID]
+		if !ok {
+			index = len(customList)
+			indexes[tvdbID] = index
+
+			customList = append(customList, entities.CustomEntry{
+				Seasons: nil,
+				TvdbID:  tvdbID,
+			})
+		}
+
+		if media.TargetSeason == nil || allSeasons[tvdbID] {
+			allSeasons[tvdbID] = true
+			customList[index].Seasons = nil
+
+			continue
+		}
+
+		if !slices.Contains(customList[index].Seasons, *media.TargetSeason) {
+			customList[index].Seasons = append(customList[index].Seasons, *media.TargetSeason)
+		}
 	}
 
-	return targetIDs, span.Assert(nil)
+	return customList, nil
 }
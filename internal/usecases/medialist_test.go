@@ -94,6 +94,124 @@ func TestMediaList_Generate(t *testing.T) {
 	assert.Equal(t, customList, got)
 }
 
+func TestMediaList_Generate_statuses(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	userID := "1"
+	sourceIDs := []entities.SourceID{"1"}
+
+	store := test.NewMockStore(t)
+	tracker := test.NewMockTracker(t)
+
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		Return(userID, nil).Once()
+	tracker.EXPECT().GetMediaListIDs(mock.Anything, userID, []string{"COMPLETED", "PAUSED"}).
+		Return(sourceIDs, nil).Once()
+	store.EXPECT().GetMediaBulk(mock.Anything, sourceIDs).
+		Return([]*entities.Media{{SourceID: "1", TargetID: "91"}}, nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Store:   store,
+		Tracker: tracker,
+	}
+
+	got, err := mediaLister.Generate(t.Context(), username, "COMPLETED", "PAUSED")
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.CustomList{{TvdbID: 91}}, got)
+}
+
+func TestMediaList_Generate_seasons(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	userID := "1"
+	sourceIDs := []entities.SourceID{"1", "2", "3", "4", "5", "6"}
+	seasonOne := uint64(1)
+	seasonTwo := uint64(2)
+
+	store := test.NewMockStore(t)
+	tracker := test.NewMockTracker(t)
+
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		Return(userID, nil).Once()
+	tracker.EXPECT().GetMediaListIDs(mock.Anything, userID).
+		Return(sourceIDs, nil).Once()
+	store.EXPECT().GetMediaBulk(mock.Anything, sourceIDs).
+		Return([]*entities.Media{
+			{SourceID: "1", TargetID: "91", TargetSeason: &seasonOne},
+			{SourceID: "2", TargetID: "91", TargetSeason: &seasonTwo},
+			{SourceID: "3", TargetID: "91", TargetSeason: &seasonTwo},
+			{SourceID: "4", TargetID: "92", TargetSeason: &seasonOne},
+			{SourceID: "5", TargetID: "92"},
+			{SourceID: "6", TargetID: "93"},
+		}, nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Store:   store,
+		Tracker: tracker,
+	}
+
+	got, err := mediaLister.Generate(t.Context(), username)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.CustomList{
+		{TvdbID: 91, Seasons: []uint64{1, 2}},
+		{TvdbID: 92},
+		{TvdbID: 93},
+	}, got)
+}
+
+func TestMediaList_GenerateMovies(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	userID := "1"
+	sourceIDs := []entities.SourceID{"1", "2"}
+
+	movieStore := test.NewMockStore(t)
+	tracker := test.NewMockTracker(t)
+
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		Return(userID, nil).Once()
+	tracker.EXPECT().GetMediaListIDs(mock.Anything, userID).
+		Return(sourceIDs, nil).Once()
+	movieStore.EXPECT().GetMediaBulk(mock.Anything, sourceIDs).
+		Return([]*entities.Media{
+			{SourceID: "1", TargetID: "129/tt0245429"},
+			{SourceID: "2", TargetID: "4935"},
+		}, nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Store:      test.NewMockStore(t),
+		MovieStore: movieStore,
+		Tracker:    tracker,
+	}
+
+	got, err := mediaLister.GenerateMovies(t.Context(), username)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.MovieList{
+		{TmdbID: 129, ImdbID: "tt0245429"},
+		{TmdbID: 4935},
+	}, got)
+}
+
+func TestMediaList_GenerateMovies_unimplemented(t *testing.T) {
+	t.Parallel()
+
+	mediaLister := usecases.MediaList{
+		Store:   test.NewMockStore(t),
+		Tracker: test.NewMockTracker(t),
+	}
+
+	got, err := mediaLister.GenerateMovies(t.Context(), "foo")
+	require.ErrorIs(t, err, usecases.ErrStatusUnimplemented)
+
+	assert.Nil(t, got)
+}
+
 func TestMediaList_Generate_GetUserID_error(t *testing.T) {
 	t.Parallel()
 
@@ -271,6 +389,7 @@ func TestMediaList_Refresh(t *testing.T) {
 	store := test.NewMockStore(t)
 	tracker := test.NewMockTracker(t)
 
+	source.EXPECT().String().Return("mem://source").Maybe()
 	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
 		Return(data, nil).Once()
 	store.EXPECT().PutMediaBulk(mock.Anything, medias).
@@ -286,6 +405,144 @@ func TestMediaList_Refresh(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMediaList_Refresh_movies(t *testing.T) {
+	t.Parallel()
+
+	getter := usecases.HTTPGetter(nil)
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+	movieSource := test.NewMockSource(t)
+	movieStore := test.NewMockStore(t)
+
+	source.EXPECT().String().Return("mem://source").Maybe()
+	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
+		Return([]usecases.Metadata{test.Metadata{SourceID: "1", TargetID: "91"}}, nil).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{{SourceID: "1", TargetID: "91"}}).
+		Return(nil).Once()
+	movieSource.EXPECT().String().Return("mem://movieSource").Maybe()
+	movieSource.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
+		Return([]usecases.Metadata{test.Metadata{SourceID: "2", TargetID: "129"}}, nil).Once()
+	movieStore.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{{SourceID: "2", TargetID: "129"}}).
+		Return(nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:      source,
+		Store:       store,
+		MovieSource: movieSource,
+		MovieStore:  movieStore,
+		Tracker:     test.NewMockTracker(t),
+	}
+
+	err := mediaLister.Refresh(t.Context(), getter)
+	require.NoError(t, err)
+}
+
+func TestMediaList_Refresh_seasons(t *testing.T) {
+	t.Parallel()
+
+	season := uint64(2)
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+
+	source.EXPECT().String().Return("mem://source").Maybe()
+	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
+		Return([]usecases.Metadata{
+			test.SeasonMetadata{
+				Metadata:     test.Metadata{SourceID: "1", TargetID: "91"},
+				TargetSeason: &season,
+			},
+		}, nil).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{
+		{SourceID: "1", TargetID: "91", TargetSeason: &season},
+	}).Return(nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+	}
+
+	err := mediaLister.Refresh(t.Context(), usecases.HTTPGetter(nil))
+	require.NoError(t, err)
+}
+
+func TestMediaList_Refresh_overrides(t *testing.T) {
+	t.Parallel()
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+
+	source.EXPECT().String().Return("mem://source").Maybe()
+	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
+		Return([]usecases.Metadata{
+			test.Metadata{SourceID: "1", TargetID: "91"},
+			test.Metadata{SourceID: "2", TargetID: "92"},
+		}, nil).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "2", TargetID: "92"},
+	}).Return(nil).Once()
+
+	// overrides apply on lookups only, so refreshes store the source data as is
+	mediaLister := usecases.MediaList{
+		Source:    source,
+		Store:     store,
+		Tracker:   test.NewMockTracker(t),
+		Overrides: test.NewMockOverrides(t),
+	}
+
+	err := mediaLister.Refresh(t.Context(), usecases.HTTPGetter(nil))
+	require.NoError(t, err)
+}
+
+func TestMediaList_Refresh_prune(t *testing.T) {
+	t.Parallel()
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+
+	source.EXPECT().String().Return("mem://source").Maybe()
+	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
+		Return([]usecases.Metadata{test.Metadata{SourceID: "1", TargetID: "91"}}, nil).Once()
+	store.EXPECT().ReplaceMediaBulk(mock.Anything, []*entities.Media{{SourceID: "1", TargetID: "91"}}).
+		Return(nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+		Prune:   true,
+	}
+
+	err := mediaLister.Refresh(t.Context(), usecases.HTTPGetter(nil))
+	require.NoError(t, err)
+}
+
+func TestMediaList_Refresh_prune_partial(t *testing.T) {
+	t.Parallel()
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+
+	source.EXPECT().String().Return("mem://source").Maybe()
+	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
+		Return([]usecases.Metadata{test.Metadata{SourceID: "1", TargetID: "91"}}, usecases.ErrPartialFetch).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{{SourceID: "1", TargetID: "91"}}).
+		Return(nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+		Prune:   true,
+	}
+
+	err := mediaLister.Refresh(t.Context(), usecases.HTTPGetter(nil))
+	require.NoError(t, err)
+}
+
 func TestMediaList_Refresh_Source_error(t *testing.T) {
 	t.Parallel()
 
@@ -297,6 +554,7 @@ func TestMediaList_Refresh_Source_error(t *testing.T) {
 	store := test.NewMockStore(t)
 	tracker := test.NewMockTracker(t)
 
+	source.EXPECT().String().Return("mem://source").Maybe()
 	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
 		Return(data, errors.New("foo")).Once()
 
@@ -339,6 +597,7 @@ func TestMediaList_Refresh_Store_error(t *testing.T) {
 	store := test.NewMockStore(t)
 	tracker := test.NewMockTracker(t)
 
+	source.EXPECT().String().Return("mem://source").Maybe()
 	source.EXPECT().Fetch(mock.Anything, implements[usecases.Getter](t)).
 		Return(data, nil).Once()
 	store.EXPECT().PutMediaBulk(mock.Anything, medias).
@@ -409,6 +668,72 @@ func TestMediaList_MapIDs_Store_error(t *testing.T) {
 	assert.Nil(t, got)
 }
 
+func TestMediaList_MapIDs_overrides(t *testing.T) {
+	t.Parallel()
+
+	store := test.NewMockStore(t)
+	overrides := test.NewMockOverrides(t)
+
+	overrides.EXPECT().GetOverrides(mock.Anything).Return(entities.MappingOverrides{
+		Mappings: map[entities.SourceID]entities.TargetID{"2": "192", "8": "198"},
+		Exclude:  []entities.SourceID{"3"},
+	}, nil).Once()
+	store.EXPECT().GetMediaBulk(mock.Anything, []entities.SourceID{"1", "5"}).
+		Return([]*entities.Media{
+			{SourceID: "1", TargetID: "91"},
+			{SourceID: "5", TargetID: "95"},
+		}, nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:    test.NewMockSource(t),
+		Store:     store,
+		Tracker:   test.NewMockTracker(t),
+		Overrides: overrides,
+	}
+
+	got, err := mediaLister.MapIDs(t.Context(), []entities.SourceID{"1", "2", "3", "5", "8"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []entities.TargetID{"91", "192", "95", "198"}, got)
+}
+
+func TestMediaList_Generate_overrides(t *testing.T) {
+	t.Parallel()
+
+	username := "foo"
+	userID := "1"
+
+	store := test.NewMockStore(t)
+	tracker := test.NewMockTracker(t)
+	overrides := test.NewMockOverrides(t)
+
+	tracker.EXPECT().GetUserID(mock.Anything, username).
+		Return(userID, nil).Once()
+	tracker.EXPECT().GetMediaListIDs(mock.Anything, userID).
+		Return([]entities.SourceID{"1", "2", "3"}, nil).Once()
+	overrides.EXPECT().GetOverrides(mock.Anything).Return(entities.MappingOverrides{
+		Mappings: map[entities.SourceID]entities.TargetID{"3": "193"},
+		Exclude:  []entities.SourceID{"1"},
+	}, nil).Once()
+	store.EXPECT().GetMediaBulk(mock.Anything, []entities.SourceID{"2"}).
+		Return([]*entities.Media{{SourceID: "2", TargetID: "92"}}, nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:    test.NewMockSource(t),
+		Store:     store,
+		Tracker:   tracker,
+		Overrides: overrides,
+	}
+
+	got, err := mediaLister.Generate(t.Context(), username)
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.CustomList{
+		entities.CustomEntry{TvdbID: 92},
+		entities.CustomEntry{TvdbID: 193},
+	}, got)
+}
+
 func TestMediaList_Close(t *testing.T) {
 	t.Parallel()
 
@@ -429,6 +754,38 @@ func TestMediaList_Close(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMediaList_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	reporter := test.NewMockConflictReporter(t)
+	conflicts := []entities.MappingConflict{
+		{
+			SourceID: "1",
+			Targets: []entities.MappingTarget{
+				{Provider: "foo", TargetID: "91"},
+				{Provider: "bar", TargetID: "92"},
+			},
+		},
+	}
+
+	reporter.EXPECT().Conflicts().Return(conflicts).Once()
+
+	mediaLister := usecases.MediaList{
+		Source: test.NewMockSource(t),
+		MovieSource: struct {
+			*test.MockSource
+			*test.MockConflictReporter
+		}{
+			test.NewMockSource(t),
+			reporter,
+		},
+		Tracker: test.NewMockTracker(t),
+	}
+
+	assert.Equal(t, conflicts, mediaLister.Conflicts())
+	assert.Empty(t, (&usecases.MediaList{}).Conflicts())
+}
+
 func implements[T any](tb testing.TB) any {
 	tb.Helper()
 
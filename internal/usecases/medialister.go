@@ -7,8 +7,8 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/entities"
 )
 
-// MediaLister converts media IDs between services and generates Sonarr custom
-// list results. It handles providers, trackers and stores to fetch the data
+// MediaLister converts media IDs between services and generates Sonarr and
+// Radarr custom list results. It handles providers, trackers and stores to fetch the data
 // required for such conversions.
 //
 //mockery:generate: true
@@ -16,8 +16,14 @@ type MediaLister interface {
 	io.Closer
 
 	// Generate fetches the user media list from the Tracker and transform the IDs
-	// found to the target service through the Mapper
-	Generate(ctx context.Context, name string) (entities.CustomList, error)
+	// found to the target service through the Mapper. The optional statuses
+	// filter which tracker entries to include.
+	Generate(ctx context.Context, name string, statuses ...string) (entities.CustomList, error)
+
+	// GenerateMovies fetches the user media list from the Tracker and transform
+	// the IDs found to movies. The optional statuses filter which tracker
+	// entries to include.
+	GenerateMovies(ctx context.Context, name string, statuses ...string) (entities.MovieList, error)
 
 	// GetUserID searches the Tracker for the user ID by their name/handle
 	GetUserID(ctx context.Context, name string) (string, error)
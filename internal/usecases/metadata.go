@@ -9,3 +9,14 @@ type Metadata interface {
 	GetTargetID() string
 	Valid() bool
 }
+
+// SeasonMetadata is a [Metadata] that also knows which season of the target
+// media the source one matches to. Sources that know it should provide entries
+// that implement this interface.
+type SeasonMetadata interface {
+	Metadata
+
+	// GetTargetSeason returns the target media season, or nil if the source
+	// media matches all seasons or if it is unknown.
+	GetTargetSeason() *uint64
+}
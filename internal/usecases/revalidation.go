@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+const cacheKeyValidators string = "%s:validators:%s:%s"
+
+// ErrNotModified signals that the data at an URI did not change since the last
+// time a conditional getter fetched it.
+var ErrNotModified = errors.New("not modified")
+
+// validators are the HTTP response headers that identify a version of the data
+// at an URI.
+type validators struct {
+	Etag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// revalidation tracks the conditional requests made while fetching a source,
+// which tells whether its data changed at all. The validators of changed data
+// are only committed after it is stored, so a failed refresh retries in full.
+type revalidation struct {
+	scope         string
+	commits       []func(ctx context.Context) error
+	modified      int
+	notModified   int
+	mutex         sync.Mutex
+	unconditional bool
+}
+
+type revalidationKey struct{}
+
+// newValidators extracts the validators of a response.
+func newValidators(header http.Header) validators {
+	return validators{
+		Etag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+}
+
+// getValidators retrieves the validators stored at key. Those are optional, so
+// any errors result in none.
+func getValidators(ctx context.Context, cache Cache, key string) validators {
+	var values validators
+
+	data, err := cache.GetString(ctx, key)
+	if err != nil {
+		return values
+	}
+
+	//nolint:errcheck // invalid entries mean there are no validators
+	_ = json.Unmarshal([]byte(data), &values)
+
+	return values
+}
+
+// setHeaders adds the conditional request headers that match the validators.
+func (values validators) setHeaders(header http.Header) {
+	if values.Etag != "" {
+		header.Set("If-None-Match", values.Etag)
+	}
+
+	if values.LastModified != "" {
+		header.Set("If-Modified-Since", values.LastModified)
+	}
+}
+
+// store returns a commit that stores the validators at key, or nil if there
+// are none to store.
+func (values validators) store(cache Cache, key string) func(ctx context.Context) error {
+	if values.Etag == "" && values.LastModified == "" {
+		return nil
+	}
+
+	return func(ctx context.Context) error {
+		data, err := json.Marshal(values)
+		if err != nil {
+			return errors.Join(ErrStatusInternal, err)
+		}
+
+		err = cache.SetString(ctx, key, string(data))
+		if err != nil {
+			return fmt.Errorf("failed to store validators: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// withRevalidation starts tracking the conditional requests of getters that
+// use the returned context. Scope identifies the data being fetched, as the
+// same URI may have distinct uses. Unconditional revalidations send no
+// validators, but still collect them.
+func withRevalidation(
+	ctx context.Context,
+	scope string,
+	unconditional bool,
+) (context.Context, *revalidation) {
+	var run revalidation
+
+	run.scope = scope
+	run.unconditional = unconditional
+
+	return context.WithValue(ctx, revalidationKey{}, &run), &run
+}
+
+// revalidationFrom retrieves the revalidation of a context, or nil if there's
+// none.
+func revalidationFrom(ctx context.Context) *revalidation {
+	run, ok := ctx.Value(revalidationKey{}).(*revalidation)
+	if !ok {
+		return nil
+	}
+
+	return run
+}
+
+// notModify records a request whose data did not change.
+func (run *revalidation) notModify() {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	run.notModified++
+}
+
+// modify records a request whose data changed, and the commit of its
+// validators if there's one.
+func (run *revalidation) modify(commit func(ctx context.Context) error) {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	run.modified++
+
+	if commit != nil {
+		run.commits = append(run.commits, commit)
+	}
+}
+
+// unchanged returns true if all requests found their data unchanged.
+func (run *revalidation) unchanged() bool {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	return run.notModified > 0 && run.modified == 0
+}
+
+// partial returns true if some requests found their data changed and others
+// did not.
+func (run *revalidation) partial() bool {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	return run.notModified > 0 && run.modified > 0
+}
+
+// commit stores the validators of all changed data.
+func (run *revalidation) commit(ctx context.Context) error {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	errs := make([]error, 0, len(run.commits))
+
+	for _, commit := range run.commits {
+		errs = append(errs, commit(ctx))
+	}
+
+	return errors.Join(errs...)
+}
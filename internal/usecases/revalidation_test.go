@@ -0,0 +1,235 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
+	"github.com/wwmoraes/anilistarr/internal/test"
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+const (
+	validatorsNamespace = "anilist"
+	validatorsScope     = "mem://source"
+)
+
+// newVersionedServer serves each path of versions with its version as both the
+// body and ETag, and answers 304 Not Modified to requests that match it.
+func newVersionedServer(tb testing.TB, versions map[string]string) *httptest.Server {
+	tb.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, ok := versions[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		etag := `"` + version + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+
+		_, err := w.Write([]byte(version))
+		if err != nil {
+			tb.Error(err)
+		}
+	}))
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+// fetchAll fetches all URIs as a merging source does, which skips failed ones
+// unless all of them fail. Each URI maps its last path segment to its body.
+func fetchAll(uris ...string) func(ctx context.Context, client usecases.Getter) ([]usecases.Metadata, error) {
+	return func(ctx context.Context, client usecases.Getter) ([]usecases.Metadata, error) {
+		entries := make([]usecases.Metadata, 0, len(uris))
+		errs := make([]error, 0, len(uris))
+
+		for _, uri := range uris {
+			data, err := client.Get(ctx, uri)
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			entries = append(entries, test.Metadata{
+				SourceID: uri[strings.LastIndex(uri, "/")+1:],
+				TargetID: string(data),
+			})
+		}
+
+		if len(errs) == len(uris) {
+			return nil, errors.Join(errs...)
+		}
+
+		return entries, nil
+	}
+}
+
+func validatorsKey(uri string) string {
+	return fmt.Sprintf("%s:validators:%s:%s", validatorsNamespace, validatorsScope, uri)
+}
+
+func TestConditionalHTTPGetter_unchanged(t *testing.T) {
+	t.Parallel()
+
+	server := newVersionedServer(t, map[string]string{"/1": "91"})
+	uri := server.URL + "/1"
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+	cache := test.NewMockCache(t)
+
+	source.EXPECT().String().Return(validatorsScope)
+	source.EXPECT().Fetch(mock.Anything, mock.Anything).
+		RunAndReturn(fetchAll(uri)).Once()
+	cache.EXPECT().GetString(mock.Anything, validatorsKey(uri)).
+		Return(`{"etag":"\"91\""}`, nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+	}
+
+	err := mediaLister.Refresh(
+		t.Context(),
+		usecases.ConditionalHTTPGetter(server.Client(), cache, validatorsNamespace),
+	)
+	require.NoError(t, err)
+}
+
+func TestConditionalHTTPGetter_changed(t *testing.T) {
+	t.Parallel()
+
+	server := newVersionedServer(t, map[string]string{"/1": "91"})
+	uri := server.URL + "/1"
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+	cache := test.NewMockCache(t)
+
+	source.EXPECT().String().Return(validatorsScope)
+	source.EXPECT().Fetch(mock.Anything, mock.Anything).
+		RunAndReturn(fetchAll(uri)).Once()
+	cache.EXPECT().GetString(mock.Anything, validatorsKey(uri)).
+		Return(`{"etag":"\"90\""}`, nil).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{{SourceID: "1", TargetID: "91"}}).
+		Return(nil).Once()
+	cache.EXPECT().SetString(mock.Anything, validatorsKey(uri), `{"etag":"\"91\""}`).
+		Return(nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+	}
+
+	err := mediaLister.Refresh(
+		t.Context(),
+		usecases.ConditionalHTTPGetter(server.Client(), cache, validatorsNamespace),
+	)
+	require.NoError(t, err)
+}
+
+func TestConditionalHTTPGetter_partial(t *testing.T) {
+	t.Parallel()
+
+	server := newVersionedServer(t, map[string]string{"/1": "91", "/2": "92"})
+	first := server.URL + "/1"
+	second := server.URL + "/2"
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+	cache := test.NewMockCache(t)
+
+	source.EXPECT().String().Return(validatorsScope)
+	source.EXPECT().Fetch(mock.Anything, mock.Anything).
+		RunAndReturn(fetchAll(first, second)).Twice()
+	cache.EXPECT().GetString(mock.Anything, validatorsKey(first)).
+		Return(`{"etag":"\"91\""}`, nil).Once()
+	cache.EXPECT().GetString(mock.Anything, validatorsKey(second)).
+		Return("", usecases.ErrStatusNotFound).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, []*entities.Media{
+		{SourceID: "1", TargetID: "91"},
+		{SourceID: "2", TargetID: "92"},
+	}).Return(nil).Once()
+	cache.EXPECT().SetString(mock.Anything, validatorsKey(first), `{"etag":"\"91\""}`).
+		Return(nil).Once()
+	cache.EXPECT().SetString(mock.Anything, validatorsKey(second), `{"etag":"\"92\""}`).
+		Return(nil).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+	}
+
+	err := mediaLister.Refresh(
+		t.Context(),
+		usecases.ConditionalHTTPGetter(server.Client(), cache, validatorsNamespace),
+	)
+	require.NoError(t, err)
+}
+
+func TestConditionalHTTPGetter_Store_error(t *testing.T) {
+	t.Parallel()
+
+	server := newVersionedServer(t, map[string]string{"/1": "91"})
+	uri := server.URL + "/1"
+
+	source := test.NewMockSource(t)
+	store := test.NewMockStore(t)
+	cache := test.NewMockCache(t)
+
+	source.EXPECT().String().Return(validatorsScope)
+	source.EXPECT().Fetch(mock.Anything, mock.Anything).
+		RunAndReturn(fetchAll(uri)).Once()
+	cache.EXPECT().GetString(mock.Anything, validatorsKey(uri)).
+		Return("", usecases.ErrStatusNotFound).Once()
+	store.EXPECT().PutMediaBulk(mock.Anything, mock.Anything).
+		Return(usecases.ErrStatusUnavailable).Once()
+
+	mediaLister := usecases.MediaList{
+		Source:  source,
+		Store:   store,
+		Tracker: test.NewMockTracker(t),
+	}
+
+	err := mediaLister.Refresh(
+		t.Context(),
+		usecases.ConditionalHTTPGetter(server.Client(), cache, validatorsNamespace),
+	)
+	require.ErrorIs(t, err, usecases.ErrStatusUnavailable)
+}
+
+func TestConditionalHTTPGetter_outside_refresh(t *testing.T) {
+	t.Parallel()
+
+	server := newVersionedServer(t, map[string]string{"/1": "91"})
+
+	getter := usecases.ConditionalHTTPGetter(server.Client(), test.NewMockCache(t), validatorsNamespace)
+
+	got, err := getter.Get(t.Context(), server.URL+"/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("91"), got)
+}
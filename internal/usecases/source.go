@@ -2,9 +2,17 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	"github.com/wwmoraes/anilistarr/internal/entities"
 )
 
+// ErrPartialFetch signals that a source fetched only part of its metadata, e.g.
+// as some of the origins it merges failed. Sources return it along with the
+// metadata they did fetch.
+var ErrPartialFetch = errors.New("partial fetch")
+
 // Source is a data origin that provides metadata for mapping media IDs
 //
 //mockery:generate: true
@@ -13,3 +21,12 @@ type Source interface {
 
 	Fetch(ctx context.Context, client Getter) ([]Metadata, error)
 }
+
+// ConflictReporter reports the mapping conflicts found when merging metadata
+// from multiple sources.
+//
+//mockery:generate: true
+type ConflictReporter interface {
+	// Conflicts returns the conflicts found during the last refresh.
+	Conflicts() []entities.MappingConflict
+}
@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type stalenessKey struct{}
+
+// WithStaleness returns a context where lookups may flag the data they serve
+// as stale, and a function that tells whether any did so.
+func WithStaleness(ctx context.Context) (context.Context, func() bool) {
+	var stale atomic.Bool
+
+	return context.WithValue(ctx, stalenessKey{}, &stale), stale.Load
+}
+
+// MarkStale flags the data served within ctx as stale i.e. past its TTL. It
+// does nothing on contexts without [WithStaleness].
+func MarkStale(ctx context.Context) {
+	stale, ok := ctx.Value(stalenessKey{}).(*atomic.Bool)
+	if ok {
+		stale.Store(true)
+	}
+}
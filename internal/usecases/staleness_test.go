@@ -0,0 +1,22 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wwmoraes/anilistarr/internal/usecases"
+)
+
+func TestMarkStale(t *testing.T) {
+	t.Parallel()
+
+	// no-op without tracking
+	usecases.MarkStale(t.Context())
+
+	ctx, stale := usecases.WithStaleness(t.Context())
+	assert.False(t, stale())
+
+	usecases.MarkStale(ctx)
+	assert.True(t, stale())
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/wwmoraes/anilistarr/internal/entities"
 )
 
-// Store handles the persistent storage and retrieval of media mapping data
+// Store handles the persistent storage and retrieval of media mapping data.
+// ReplaceMediaBulk differs from PutMediaBulk as it atomically drops any medias
+// not given as well.
 //
 //mockery:generate: true
 type Store interface {
@@ -17,4 +19,24 @@ type Store interface {
 	GetMediaBulk(ctx context.Context, ids []string) ([]*entities.Media, error)
 	PutMedia(ctx context.Context, media *entities.Media) error
 	PutMediaBulk(ctx context.Context, medias []*entities.Media) error
+	ReplaceMediaBulk(ctx context.Context, medias []*entities.Media) error
+}
+
+// TokenStore handles the persistent storage and retrieval of user access
+// tokens. Tokens are retrievable by either user ID or name, the latter being
+// case-insensitive. Putting a token replaces any other of the same user.
+//
+//mockery:generate: true
+type TokenStore interface {
+	GetToken(ctx context.Context, userID string) (*entities.Token, error)
+	GetTokenByName(ctx context.Context, name string) (*entities.Token, error)
+	PutToken(ctx context.Context, token *entities.Token) error
+}
+
+// Overrides provides local corrections to the mappings from sources, which
+// take precedence over both sources and stores.
+//
+//mockery:generate: true
+type Overrides interface {
+	GetOverrides(ctx context.Context) (entities.MappingOverrides, error)
 }
@@ -2,18 +2,39 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/wwmoraes/anilistarr/internal/entities"
 )
 
+// ErrPartialMediaList signals that fetching a media list failed midway. The
+// entries fetched until then are incomplete, so trackers discard those.
+var ErrPartialMediaList = errors.New("partial media list")
+
 // Tracker provides access to user metadata such as ID and media list from an
-// upstream media tracking service
+// upstream media tracking service.
+//
+// Media lists can be filtered by statuses, which use the tracker-specific
+// values. Trackers fall back to their default statuses when none is given.
 //
 //mockery:generate: true
 type Tracker interface {
 	io.Closer
 
 	GetUserID(ctx context.Context, name string) (string, error)
-	GetMediaListIDs(ctx context.Context, userID string) ([]entities.SourceID, error)
+	GetMediaListIDs(
+		ctx context.Context,
+		userID string,
+		statuses ...string,
+	) ([]entities.SourceID, error)
+}
+
+// UserIDCache records the IDs of tracker users known ahead of any lookup, such
+// as those that authorize access to their data. Those supersede any cached
+// absence of such users.
+//
+//mockery:generate: true
+type UserIDCache interface {
+	PutUserID(ctx context.Context, name, userID string) error
 }